@@ -5,12 +5,14 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"gym-tracker-api/internal/handlers"
 	"gym-tracker-api/internal/middleware"
-	"gym-tracker-api/internal/repository/db"
 	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/storage"
 
 	"github.com/akrylysov/algnhsa"
 	"github.com/aws/aws-sdk-go/aws"
@@ -42,29 +44,132 @@ func init() {
 	cognitoClient = cognitoidentityprovider.New(sess)
 }
 
-func setupHandlers() (*handlers.WorkoutHandler, *handlers.ExerciseHandler, *handlers.AuthHandler) {
+// newRepositories picks the storage backend from STORAGE: "memory" keeps all
+// data in-process for local development and tests, "sqlite" and "postgres"
+// connect to DATABASE_URL, and anything else uses DynamoDB.
+func newRepositories() *storage.Repositories {
+	backend := os.Getenv("STORAGE")
+	if backend == storage.BackendMemory {
+		log.Println("Using in-memory storage; data will be lost on restart")
+	}
+	repos, err := storage.Open(storage.Config{
+		Backend:           backend,
+		DSN:               os.Getenv("DATABASE_URL"),
+		Dynamo:            dynamoClient,
+		WorkoutsTable:     os.Getenv("DYNAMO_TABLE_WORKOUTS"),
+		ExercisesTable:    os.Getenv("DYNAMO_TABLE_EXERCISES"),
+		TemplatesTable:    os.Getenv("DYNAMO_TABLE_TEMPLATES"),
+		ProgramsTable:     os.Getenv("DYNAMO_TABLE_PROGRAMS"),
+		RecordsTable:      os.Getenv("DYNAMO_TABLE_RECORDS"),
+		ProfilesTable:     os.Getenv("DYNAMO_TABLE_PROFILES"),
+		MeasurementsTable: os.Getenv("DYNAMO_TABLE_MEASUREMENTS"),
+		TrashRetention:    trashRetention(),
+	})
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
+	}
+	if repos.Sweeps() {
+		go sweepTrash(repos)
+	}
+	return repos
+}
+
+// trashRetention reads TRASH_RETENTION_DAYS, how long deleted workouts and
+// exercises can be restored. Unset means the storage default.
+func trashRetention() time.Duration {
+	value := os.Getenv("TRASH_RETENTION_DAYS")
+	if value == "" {
+		return 0
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 {
+		log.Fatalf("TRASH_RETENTION_DAYS must be a positive number of days, got %q", value)
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// sweepTrash purges expired items from the trash every hour, for backends
+// without DynamoDB's TTL.
+func sweepTrash(repos *storage.Repositories) {
+	for range time.Tick(time.Hour) {
+		n, err := repos.SweepTrash()
+		if err != nil {
+			log.Printf("failed to sweep trash: %v", err)
+		}
+		if n > 0 {
+			log.Printf("purged %d expired items from the trash", n)
+		}
+	}
+}
+
+// onLambda reports whether the API runs as a Lambda function behind API
+// Gateway rather than as a standalone HTTP server.
+func onLambda() bool {
+	return os.Getenv("AWS_LAMBDA_RUNTIME_API") != ""
+}
+
+func setupHandlers() (*handlers.WorkoutHandler, *handlers.ExerciseHandler, *handlers.TemplateHandler, *handlers.ProgramHandler, *handlers.RecordHandler, *handlers.StatsHandler, *handlers.ProfileHandler, *handlers.MeasurementHandler, *handlers.EventHandler, *handlers.TrashHandler, *handlers.AuthHandler, services.ProfileService) {
 	// Repository layer
-	workoutRepo := db.NewDynamoWorkoutRepository(dynamoClient, os.Getenv("DYNAMO_TABLE_WORKOUTS"))
-	exerciseRepo := db.NewDynamoExerciseRepository(dynamoClient, os.Getenv("DYNAMO_TABLE_EXERCISES"))
+	repos := newRepositories()
 	
 	// Service layer
-	workoutService := services.NewWorkoutService(workoutRepo)
-	exerciseService := services.NewExerciseService(exerciseRepo)
+	// Live updates only reach clients connected to the same instance.
+	eventBus := services.NewEventBus()
+	profileService := services.NewProfileService(repos.Profiles)
+	workoutService := services.NewWorkoutService(repos.Workouts, repos.Exercises, profileService, eventBus)
+	recordService := services.NewRecordService(repos.Records)
+	exerciseService := services.NewExerciseService(repos.Exercises, recordService, eventBus)
+	// Exercises started from templates and programs hold targets, not
+	// performances, so they only count towards records once edited.
+	plannedExercises := services.NewExerciseService(repos.Exercises, nil, eventBus)
+	measurementService := services.NewMeasurementService(repos.Measurements, profileService)
+	statsService := services.NewStatsService(repos.Workouts, repos.Exercises, repos.Measurements)
+	cardioService := services.NewCardioService(repos.Workouts, repos.Exercises, services.DefaultMachineMetrics())
+	templateService := services.NewTemplateService(repos.Templates, workoutService, plannedExercises)
+	programService := services.NewProgramService(repos.Programs, repos.Templates, workoutService, plannedExercises)
 	
 	// Handler layer
-	workoutHandler := handlers.NewWorkoutHandler(workoutService)
-	exerciseHandler := handlers.NewExerciseHandler(exerciseService)
+	workoutHandler := handlers.NewWorkoutHandler(workoutService, profileService)
+	exerciseHandler := handlers.NewExerciseHandler(exerciseService, profileService)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	programHandler := handlers.NewProgramHandler(programService)
+	recordHandler := handlers.NewRecordHandler(recordService)
+	statsHandler := handlers.NewStatsHandler(statsService, cardioService)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	measurementHandler := handlers.NewMeasurementHandler(measurementService, profileService)
+	// API Gateway buffers Lambda responses, so event streams fall back to polling there.
+	eventHandler := handlers.NewEventHandler(workoutService, exerciseService, profileService, eventBus, !onLambda())
+	trashHandler := handlers.NewTrashHandler(workoutService, exerciseService, profileService, repos.TrashRetention)
 	authHandler := handlers.NewAuthHandler(cognitoClient)
 	
-	return workoutHandler, exerciseHandler, authHandler
+	return workoutHandler, exerciseHandler, templateHandler, programHandler, recordHandler, statsHandler, profileHandler, measurementHandler, eventHandler, trashHandler, authHandler, profileService
 } 
 
+// newTokenVerifier validates access tokens offline against the user pool's JWKS.
+// JWKS_FILE points at a local key set for development; without a user pool ID
+// it falls back to a Cognito GetUser call per request.
+func newTokenVerifier() middleware.TokenVerifier {
+	region := os.Getenv("AWS_REGION")
+	userPoolID := os.Getenv("COGNITO_USER_POOL_ID")
+	clientID := os.Getenv("COGNITO_CLIENT_ID")
+
+	if jwksFile := os.Getenv("JWKS_FILE"); jwksFile != "" {
+		return middleware.NewJWTVerifier(middleware.NewFileKeySource(jwksFile), middleware.CognitoIssuer(region, userPoolID), clientID)
+	}
+	if userPoolID == "" {
+		log.Println("COGNITO_USER_POOL_ID not set, validating tokens with Cognito GetUser")
+		return middleware.NewCognitoVerifier(cognitoClient)
+	}
+	return middleware.NewCognitoJWTVerifier(region, userPoolID, clientID)
+}
+
 func main() {
 	// Initialize handlers with proper dependency injection
-	workoutHandler, exerciseHandler, authHandler := setupHandlers()
+	workoutHandler, exerciseHandler, templateHandler, programHandler, recordHandler, statsHandler, profileHandler, measurementHandler, eventHandler, trashHandler, authHandler, profileService := setupHandlers()
 	
 	// Setup middleware
-	authMiddleware := middleware.NewAuthMiddleware(cognitoClient)
+	// Profiles are created lazily on each user's first authenticated request.
+	authMiddleware := middleware.NewAuthMiddleware(newTokenVerifier(), os.Getenv("COGNITO_ADMIN_GROUP")).WithProfiles(profileService)
 	originsEnv := os.Getenv("CORS_ALLOWED_ORIGINS")
 	if originsEnv == "" {
 		originsEnv = "http://localhost:5173,capacitor://localhost"
@@ -73,11 +178,13 @@ func main() {
 	corsMiddleware := middleware.NewCORSMiddleware(allowedOrigins)
 	
 	r := mux.NewRouter()
+	r.NotFoundHandler = http.HandlerFunc(handlers.NotFound)
+	r.MethodNotAllowedHandler = http.HandlerFunc(handlers.MethodNotAllowed)
 	
 	// Add basic logging middleware first to verify requests are coming in
 	r.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			log.Printf("Request: %s %s", r.Method, r.URL.Path)
+			log.Printf("Request %s: %s %s", middleware.RequestIDFromContext(r.Context()), r.Method, r.URL.Path)
 			next.ServeHTTP(w, r)
 		})
 	})
@@ -96,29 +203,73 @@ func main() {
 	r.HandleFunc("/workouts/{userId}", authMiddleware.Authenticate(workoutHandler.CreateWorkout)).Methods("POST")
 	r.HandleFunc("/workouts/{userId}/{workoutId}", authMiddleware.Authenticate(workoutHandler.UpdateWorkout)).Methods("PUT")
 	r.HandleFunc("/workouts/{userId}/{workoutId}", authMiddleware.Authenticate(workoutHandler.DeleteWorkout)).Methods("DELETE")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/restore", authMiddleware.Authenticate(workoutHandler.RestoreWorkout)).Methods("POST")
 	r.HandleFunc("/workouts/{userId}/{workoutId}/exercises", authMiddleware.Authenticate(workoutHandler.ListExercisesInWorkout)).Methods("GET")
 	r.HandleFunc("/workouts/{userId}/{workoutId}/exercises/{exerciseId}", authMiddleware.Authenticate(workoutHandler.AddExerciseToWorkout)).Methods("POST")
 	r.HandleFunc("/workouts/{userId}/{workoutId}/exercises/{exerciseId}", authMiddleware.Authenticate(workoutHandler.RemoveExerciseFromWorkout)).Methods("DELETE")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/order", authMiddleware.Authenticate(workoutHandler.ReorderWorkout)).Methods("PUT")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/groups", authMiddleware.Authenticate(workoutHandler.GroupExercises)).Methods("POST")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/groups/{groupId}", authMiddleware.Authenticate(workoutHandler.UngroupExercises)).Methods("DELETE")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/start", authMiddleware.Authenticate(workoutHandler.StartWorkout)).Methods("POST")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/pause", authMiddleware.Authenticate(workoutHandler.PauseWorkout)).Methods("POST")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/resume", authMiddleware.Authenticate(workoutHandler.ResumeWorkout)).Methods("POST")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/finish", authMiddleware.Authenticate(workoutHandler.FinishWorkout)).Methods("POST")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/abandon", authMiddleware.Authenticate(workoutHandler.AbandonWorkout)).Methods("POST")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/events", authMiddleware.AuthenticateStream(eventHandler.WorkoutEvents)).Methods("GET")
 	r.HandleFunc("/exercises/{userId}/{exerciseId}", authMiddleware.Authenticate(exerciseHandler.GetExercise)).Methods("GET")
 	r.HandleFunc("/exercises/{userId}/name/{exerciseName}", authMiddleware.Authenticate(exerciseHandler.ListExercisesByName)).Methods("GET")
 	r.HandleFunc("/exercises/{userId}", authMiddleware.Authenticate(exerciseHandler.GetExercises)).Methods("GET")
 	r.HandleFunc("/exercises/{userId}", authMiddleware.Authenticate(exerciseHandler.CreateExercise)).Methods("POST")
 	r.HandleFunc("/exercises/{userId}/{exerciseId}", authMiddleware.Authenticate(exerciseHandler.UpdateExercise)).Methods("PUT")
 	r.HandleFunc("/exercises/{userId}/{exerciseId}", authMiddleware.Authenticate(exerciseHandler.DeleteExercise)).Methods("DELETE")
+	r.HandleFunc("/exercises/{userId}/{exerciseId}/restore", authMiddleware.Authenticate(exerciseHandler.RestoreExercise)).Methods("POST")
+	r.HandleFunc("/trash/{userId}", authMiddleware.Authenticate(trashHandler.ListTrash)).Methods("GET")
+	r.HandleFunc("/templates/{userId}", authMiddleware.Authenticate(templateHandler.ListTemplates)).Methods("GET")
+	r.HandleFunc("/templates/{userId}", authMiddleware.Authenticate(templateHandler.CreateTemplate)).Methods("POST")
+	r.HandleFunc("/templates/{userId}/{templateId}", authMiddleware.Authenticate(templateHandler.GetTemplate)).Methods("GET")
+	r.HandleFunc("/templates/{userId}/{templateId}", authMiddleware.Authenticate(templateHandler.UpdateTemplate)).Methods("PUT")
+	r.HandleFunc("/templates/{userId}/{templateId}", authMiddleware.Authenticate(templateHandler.DeleteTemplate)).Methods("DELETE")
+	r.HandleFunc("/templates/{userId}/{templateId}/start", authMiddleware.Authenticate(templateHandler.StartTemplate)).Methods("POST")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/template", authMiddleware.Authenticate(templateHandler.CreateTemplateFromWorkout)).Methods("POST")
+	r.HandleFunc("/programs/{userId}", authMiddleware.Authenticate(programHandler.ListPrograms)).Methods("GET")
+	r.HandleFunc("/programs/{userId}", authMiddleware.Authenticate(programHandler.CreateProgram)).Methods("POST")
+	r.HandleFunc("/programs/{userId}/{programId}", authMiddleware.Authenticate(programHandler.GetProgram)).Methods("GET")
+	r.HandleFunc("/programs/{userId}/{programId}", authMiddleware.Authenticate(programHandler.UpdateProgram)).Methods("PUT")
+	r.HandleFunc("/programs/{userId}/{programId}", authMiddleware.Authenticate(programHandler.DeleteProgram)).Methods("DELETE")
+	r.HandleFunc("/programs/{userId}/{programId}/enrollment", authMiddleware.Authenticate(programHandler.Enroll)).Methods("PUT")
+	r.HandleFunc("/programs/{userId}/{programId}/enrollment", authMiddleware.Authenticate(programHandler.Unenroll)).Methods("DELETE")
+	r.HandleFunc("/programs/{userId}/{programId}/today", authMiddleware.Authenticate(programHandler.GetTodaySession)).Methods("GET")
+	r.HandleFunc("/programs/{userId}/{programId}/progress", authMiddleware.Authenticate(programHandler.GetProgress)).Methods("GET")
+	r.HandleFunc("/programs/{userId}/{programId}/sessions", authMiddleware.Authenticate(programHandler.StartSession)).Methods("POST")
+	r.HandleFunc("/records/{userId}", authMiddleware.Authenticate(recordHandler.ListRecords)).Methods("GET")
+	r.HandleFunc("/stats/{userId}/exercises/{name}", authMiddleware.Authenticate(statsHandler.GetExerciseStats)).Methods("GET")
+	r.HandleFunc("/stats/{userId}/cardio/{name}", authMiddleware.Authenticate(statsHandler.GetCardioStats)).Methods("GET")
+	r.HandleFunc("/users/{userId}/profile", authMiddleware.Authenticate(profileHandler.GetProfile)).Methods("GET")
+	r.HandleFunc("/users/{userId}/profile", authMiddleware.Authenticate(profileHandler.UpdateProfile)).Methods("PUT")
+	r.HandleFunc("/users/{userId}/units", authMiddleware.Authenticate(profileHandler.GetUnits)).Methods("GET")
+	r.HandleFunc("/users/{userId}/units", authMiddleware.Authenticate(profileHandler.UpdateUnits)).Methods("PUT")
+	r.HandleFunc("/measurements/{userId}", authMiddleware.Authenticate(measurementHandler.ListMeasurements)).Methods("GET")
+	r.HandleFunc("/measurements/{userId}", authMiddleware.Authenticate(measurementHandler.CreateMeasurement)).Methods("POST")
+	r.HandleFunc("/measurements/{userId}/{measurementId}", authMiddleware.Authenticate(measurementHandler.GetMeasurement)).Methods("GET")
+	r.HandleFunc("/measurements/{userId}/{measurementId}", authMiddleware.Authenticate(measurementHandler.UpdateMeasurement)).Methods("PUT")
+	r.HandleFunc("/measurements/{userId}/{measurementId}", authMiddleware.Authenticate(measurementHandler.DeleteMeasurement)).Methods("DELETE")
 	
 	// Wrap router with CORS middleware so it runs before routing —
 	// gorilla/mux r.Use() only runs when a route matches, which
 	// excludes OPTIONS preflight requests that have no registered route.
+	// RequestID wraps everything so every response, including errors from
+	// unmatched routes, carries an X-Request-ID.
+	handler := middleware.RequestID(corsMiddleware.Handler(r))
 	
 	// Run as Lambda function if AWS_LAMBDA_RUNTIME_API is set, otherwise run as standard HTTP server
-	if os.Getenv("AWS_LAMBDA_RUNTIME_API") != "" {
-		algnhsa.ListenAndServe(corsMiddleware.Handler(r), nil)
+	if onLambda() {
+		algnhsa.ListenAndServe(handler, nil)
 	} else {
 		port := os.Getenv("PORT")
 		if port == "" {
 			port = "8080"
 		}
 		log.Printf("Server running on port %s", port)
-		log.Fatal(http.ListenAndServe(":"+port, corsMiddleware.Handler(r)))
+		log.Fatal(http.ListenAndServe(":"+port, handler))
 	}
 }
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/storage"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// backfill rewrites every exercise for a user through the repository so that
+// derived index attributes (NameKey, UserType) are populated on items written
+// before those indexes existed, and stores every workout saved as a plain
+// exercise list with one single group per exercise.
+func main() {
+	userID := flag.String("user-id", "", "Cognito UserID (sub) whose data should be backfilled (required)")
+	env := flag.String("env", "prod", "Environment suffix for DynamoDB table names (prod or test)")
+	backend := flag.String("storage", storage.BackendDynamo, "Storage backend: dynamo, sqlite or postgres")
+	dsn := flag.String("dsn", os.Getenv("DATABASE_URL"), "Database connection string for sqlite or postgres (defaults to $DATABASE_URL)")
+	dryRun := flag.Bool("dry-run", false, "List what would be rewritten without writing anything")
+	flag.Parse()
+
+	if *userID == "" {
+		log.Fatal("--user-id is required")
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewEnvCredentials(),
+	}))
+	dynamo := dynamodb.New(sess)
+
+	repos, err := storage.Open(storage.Config{
+		Backend:        *backend,
+		DSN:            *dsn,
+		Dynamo:         dynamo,
+		WorkoutsTable:  fmt.Sprintf("Workouts-%s", *env),
+		ExercisesTable: fmt.Sprintf("Exercises-%s", *env),
+	})
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
+	}
+	defer repos.Close()
+	exerciseRepo, workoutRepo := repos.Exercises, repos.Workouts
+
+	if *dryRun {
+		fmt.Println("DRY RUN — no data will be written")
+	}
+
+	exercises, err := repository.ListAllExercises(exerciseRepo, *userID)
+	if err != nil {
+		log.Fatalf("failed to list exercises: %v", err)
+	}
+
+	fmt.Printf("Found %d exercises\n", len(exercises))
+	rewritten := 0
+	for _, ex := range exercises {
+		if *dryRun {
+			fmt.Printf("  [exercise] %s (%s) %s\n", ex.Name, ex.ExerciseType, ex.ExerciseID)
+			continue
+		}
+		if err := exerciseRepo.Update(*userID, ex); err != nil {
+			log.Printf("WARNING: failed to rewrite exercise %s (%s): %v", ex.ExerciseID, ex.Name, err)
+			continue
+		}
+		rewritten++
+	}
+
+	workouts, err := repository.ListAllWorkouts(workoutRepo, *userID)
+	if err != nil {
+		log.Fatalf("failed to list workouts: %v", err)
+	}
+
+	fmt.Printf("Found %d workouts\n", len(workouts))
+	grouped := 0
+	for _, w := range workouts {
+		if len(w.Groups) > 0 {
+			continue
+		}
+		if *dryRun {
+			fmt.Printf("  [workout] %s — %s %s (%d exercises)\n", w.Date, w.Name, w.WorkoutID, len(w.Exercises))
+			continue
+		}
+		w.NormalizeGroups()
+		if err := workoutRepo.Update(w); err != nil {
+			log.Printf("WARNING: failed to group workout %s (%s %s): %v", w.WorkoutID, w.Date, w.Name, err)
+			continue
+		}
+		grouped++
+	}
+
+	if !*dryRun {
+		fmt.Printf("\nDone. Rewrote %d exercises and grouped %d workouts.\n", rewritten, grouped)
+	}
+}
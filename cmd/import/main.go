@@ -11,7 +11,7 @@ import (
 	"time"
 
 	"gym-tracker-api/internal/models"
-	repoDb "gym-tracker-api/internal/repository/db"
+	"gym-tracker-api/internal/storage"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -61,6 +61,8 @@ func main() {
 	userID := flag.String("user-id", "", "Cognito UserID (sub) to assign the data to (required)")
 	filePath := flag.String("file", "", "Path to the CSV file (required)")
 	env := flag.String("env", "prod", "Environment suffix for DynamoDB table names (prod or test)")
+	backend := flag.String("storage", storage.BackendDynamo, "Storage backend: dynamo, sqlite or postgres")
+	dsn := flag.String("dsn", os.Getenv("DATABASE_URL"), "Database connection string for sqlite or postgres (defaults to $DATABASE_URL)")
 	dryRun := flag.Bool("dry-run", false, "Parse and print what would be written without writing to DynamoDB")
 	flag.Parse()
 
@@ -83,11 +85,18 @@ func main() {
 	}))
 	dynamo := dynamodb.New(sess)
 
-	workoutsTable := fmt.Sprintf("Workouts-%s", *env)
-	exercisesTable := fmt.Sprintf("Exercises-%s", *env)
-
-	workoutRepo := repoDb.NewDynamoWorkoutRepository(dynamo, workoutsTable)
-	exerciseRepo := repoDb.NewDynamoExerciseRepository(dynamo, exercisesTable)
+	repos, err := storage.Open(storage.Config{
+		Backend:        *backend,
+		DSN:            *dsn,
+		Dynamo:         dynamo,
+		WorkoutsTable:  fmt.Sprintf("Workouts-%s", *env),
+		ExercisesTable: fmt.Sprintf("Exercises-%s", *env),
+	})
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
+	}
+	defer repos.Close()
+	workoutRepo, exerciseRepo := repos.Workouts, repos.Exercises
 
 	// --- Parse CSV ---
 	groups, err := parseCSV(*filePath)
@@ -111,12 +120,16 @@ func main() {
 
 	for _, group := range groups {
 		exerciseIDs := make([]string, 0)
+		// Exercises are dated by their session, so that any records later
+		// tracked for them keep the day they were set.
+		performedAt, _ := models.ParseDate(group.date)
 
 		for _, row := range group.rows {
 			exercises := buildExercises(row, nearestTimes[rowIdx])
 			rowIdx++
 
 			for _, exercise := range exercises {
+				exercise.CreatedAt = performedAt
 				if *dryRun {
 					setsDesc := fmt.Sprintf("sets=%d", len(exercise.Sets))
 					if len(exercise.Sets) > 0 {
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 
-	repoDb "gym-tracker-api/internal/repository/db"
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/storage"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -17,7 +20,10 @@ import (
 func main() {
 	userID := flag.String("user-id", "", "Cognito UserID (sub) whose data should be deleted (required)")
 	env := flag.String("env", "prod", "Environment suffix for DynamoDB table names (prod or test)")
+	backend := flag.String("storage", storage.BackendDynamo, "Storage backend: dynamo, sqlite or postgres")
+	dsn := flag.String("dsn", os.Getenv("DATABASE_URL"), "Database connection string for sqlite or postgres (defaults to $DATABASE_URL)")
 	dryRun := flag.Bool("dry-run", false, "List what would be deleted without deleting anything")
+	purge := flag.Bool("purge", false, "Delete everything for good: empty the trash and delete templates, programs, measurements and the profile, which cannot be restored")
 	flag.Parse()
 
 	if *userID == "" {
@@ -35,18 +41,30 @@ func main() {
 	}))
 	dynamo := dynamodb.New(sess)
 
-	workoutsTable := fmt.Sprintf("Workouts-%s", *env)
-	exercisesTable := fmt.Sprintf("Exercises-%s", *env)
-
-	workoutRepo := repoDb.NewDynamoWorkoutRepository(dynamo, workoutsTable)
-	exerciseRepo := repoDb.NewDynamoExerciseRepository(dynamo, exercisesTable)
+	repos, err := storage.Open(storage.Config{
+		Backend:           *backend,
+		DSN:               *dsn,
+		Dynamo:            dynamo,
+		WorkoutsTable:     fmt.Sprintf("Workouts-%s", *env),
+		ExercisesTable:    fmt.Sprintf("Exercises-%s", *env),
+		TemplatesTable:    fmt.Sprintf("Templates-%s", *env),
+		ProgramsTable:     fmt.Sprintf("Programs-%s", *env),
+		RecordsTable:      fmt.Sprintf("Records-%s", *env),
+		ProfilesTable:     fmt.Sprintf("Profiles-%s", *env),
+		MeasurementsTable: fmt.Sprintf("Measurements-%s", *env),
+	})
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
+	}
+	defer repos.Close()
+	workoutRepo, exerciseRepo, templateRepo, programRepo, recordRepo, profileRepo, measurementRepo := repos.Workouts, repos.Exercises, repos.Templates, repos.Programs, repos.Records, repos.Profiles, repos.Measurements
 
 	if *dryRun {
 		fmt.Println("DRY RUN — no data will be deleted")
 	}
 
 	// --- Delete exercises ---
-	exercises, err := exerciseRepo.ListByUserID(*userID)
+	exercises, err := repository.ListAllExercises(exerciseRepo, *userID)
 	if err != nil {
 		log.Fatalf("failed to list exercises: %v", err)
 	}
@@ -66,7 +84,7 @@ func main() {
 	}
 
 	// --- Delete workouts ---
-	workouts, err := workoutRepo.ListByUserID(*userID)
+	workouts, err := repository.ListAllWorkouts(workoutRepo, *userID)
 	if err != nil {
 		log.Fatalf("failed to list workouts: %v", err)
 	}
@@ -85,7 +103,161 @@ func main() {
 		deletedWorkouts++
 	}
 
+	// --- Clear personal records ---
+	// Like deleting an exercise through the API, trashing it clears the records
+	// it set; restoring it through the API tracks them again. Records are
+	// grouped by the exercise that set them and removed per exercise.
+	records, err := repository.ListAllRecords(recordRepo, *userID)
+	if err != nil {
+		log.Fatalf("failed to list records: %v", err)
+	}
+
+	fmt.Printf("Found %d personal records\n", len(records))
+	deletedRecords := 0
+	recordsByExercise := make(map[string]int)
+	for _, rec := range records {
+		if *dryRun {
+			fmt.Printf("  [record] %s %s %v %s\n", rec.ExerciseName, rec.Kind, rec.Value, rec.Unit)
+			continue
+		}
+		recordsByExercise[rec.ExerciseID]++
+	}
+	for exerciseID, n := range recordsByExercise {
+		if err := recordRepo.DeleteByExercise(*userID, exerciseID); err != nil {
+			log.Printf("WARNING: failed to delete records for exercise %s: %v", exerciseID, err)
+			continue
+		}
+		deletedRecords += n
+	}
+
+	// Everything above can be restored until the trash retention passes.
+	// Emptying the trash and deleting templates, programs, measurements and
+	// the profile, which have no trash, cannot be undone, so it needs --purge.
+	if !*purge {
+		if !*dryRun {
+			fmt.Printf("\nDone. Moved %d exercises and %d workouts to the trash and cleared %d records.\n", deletedExercises, deletedWorkouts, deletedRecords)
+			fmt.Println("They can be restored until the trash retention passes. Templates, programs, measurements and the profile were kept; run with --purge to delete everything for good.")
+		}
+		return
+	}
+
+	// --- Purge the trash ---
+	trashedExercises, err := repository.ListAllDeletedExercises(exerciseRepo, *userID)
+	if err != nil {
+		log.Fatalf("failed to list deleted exercises: %v", err)
+	}
+	trashedWorkouts, err := repository.ListAllDeletedWorkouts(workoutRepo, *userID)
+	if err != nil {
+		log.Fatalf("failed to list deleted workouts: %v", err)
+	}
+
+	fmt.Printf("Found %d exercises and %d workouts in the trash\n", len(trashedExercises), len(trashedWorkouts))
+	purgedExercises, purgedWorkouts := 0, 0
+	for _, ex := range trashedExercises {
+		if *dryRun {
+			fmt.Printf("  [trash] exercise %s (%s) %s\n", ex.Name, ex.ExerciseType, ex.ExerciseID)
+			continue
+		}
+		if err := exerciseRepo.Purge(*userID, ex.ExerciseID); err != nil {
+			log.Printf("WARNING: failed to purge exercise %s (%s): %v", ex.ExerciseID, ex.Name, err)
+			continue
+		}
+		purgedExercises++
+	}
+	for _, w := range trashedWorkouts {
+		if *dryRun {
+			fmt.Printf("  [trash] workout %s — %s %s\n", w.Date, w.Name, w.WorkoutID)
+			continue
+		}
+		if err := workoutRepo.Purge(*userID, w.WorkoutID); err != nil {
+			log.Printf("WARNING: failed to purge workout %s (%s %s): %v", w.WorkoutID, w.Date, w.Name, err)
+			continue
+		}
+		purgedWorkouts++
+	}
+
+	// --- Delete templates ---
+	templates, err := repository.ListAllTemplates(templateRepo, *userID)
+	if err != nil {
+		log.Fatalf("failed to list templates: %v", err)
+	}
+
+	fmt.Printf("Found %d templates\n", len(templates))
+	deletedTemplates := 0
+	for _, t := range templates {
+		if *dryRun {
+			fmt.Printf("  [template] %s %s\n", t.Name, t.TemplateID)
+			continue
+		}
+		if err := templateRepo.Delete(*userID, t.TemplateID); err != nil {
+			log.Printf("WARNING: failed to delete template %s (%s): %v", t.TemplateID, t.Name, err)
+			continue
+		}
+		deletedTemplates++
+	}
+
+	// --- Delete programs ---
+	programs, err := repository.ListAllPrograms(programRepo, *userID)
+	if err != nil {
+		log.Fatalf("failed to list programs: %v", err)
+	}
+
+	fmt.Printf("Found %d programs\n", len(programs))
+	deletedPrograms := 0
+	for _, p := range programs {
+		if *dryRun {
+			fmt.Printf("  [program] %s %s\n", p.Name, p.ProgramID)
+			continue
+		}
+		if err := programRepo.Delete(*userID, p.ProgramID); err != nil {
+			log.Printf("WARNING: failed to delete program %s (%s): %v", p.ProgramID, p.Name, err)
+			continue
+		}
+		deletedPrograms++
+	}
+
+	// --- Delete measurements ---
+	measurements, err := repository.ListAllMeasurements(measurementRepo, *userID)
+	if err != nil {
+		log.Fatalf("failed to list measurements: %v", err)
+	}
+
+	fmt.Printf("Found %d measurements\n", len(measurements))
+	deletedMeasurements := 0
+	for _, m := range measurements {
+		if *dryRun {
+			fmt.Printf("  [measurement] %s %s\n", m.Date, m.MeasurementID)
+			continue
+		}
+		if err := measurementRepo.Delete(*userID, m.MeasurementID); err != nil {
+			log.Printf("WARNING: failed to delete measurement %s (%s): %v", m.MeasurementID, m.Date, err)
+			continue
+		}
+		deletedMeasurements++
+	}
+
+	// --- Delete profile ---
+	deletedProfile := false
+	profile, err := profileRepo.GetByUserID(*userID)
+	switch {
+	case errors.Is(err, models.ErrProfileNotFound):
+		fmt.Println("Found no profile")
+	case err != nil:
+		log.Fatalf("failed to get profile: %v", err)
+	case *dryRun:
+		fmt.Println("Found a profile")
+		fmt.Printf("  [profile] %s (%s, %s)\n", profile.UserID, profile.Units.Weight, profile.Units.Distance)
+	default:
+		fmt.Println("Found a profile")
+		if err := profileRepo.Delete(*userID); err != nil {
+			log.Printf("WARNING: failed to delete profile: %v", err)
+		} else {
+			deletedProfile = true
+		}
+	}
+
 	if !*dryRun {
-		fmt.Printf("\nDone. Deleted %d exercises and %d workouts.\n", deletedExercises, deletedWorkouts)
+		fmt.Printf("\nDone. Deleted %d exercises, %d workouts, %d templates, %d programs, %d records and %d measurements (profile deleted: %t).\n", deletedExercises, deletedWorkouts, deletedTemplates, deletedPrograms, deletedRecords, deletedMeasurements, deletedProfile)
+		fmt.Printf("Purged %d exercises and %d workouts from the trash.\n", purgedExercises, purgedWorkouts)
 	}
 }
@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/utils"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
 )
 
@@ -35,9 +40,8 @@ func NewAuthHandler(cognito *cognitoidentityprovider.CognitoIdentityProvider) *A
 // Auth handlers
 func (a *AuthHandler) SignUp(w http.ResponseWriter, r *http.Request) {
 	var user User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid JSON"})
+	if err := utils.DecodeJSON(r.Body, &user); err != nil {
+		utils.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -53,8 +57,7 @@ func (a *AuthHandler) SignUp(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		utils.WriteErrorResponse(w, cognitoError(err))
 		return
 	}
 
@@ -67,9 +70,8 @@ func (a *AuthHandler) ConfirmSignUp(w http.ResponseWriter, r *http.Request) {
 		Email string `json:"email"`
 		Code  string `json:"code"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid JSON"})
+	if err := utils.DecodeJSON(r.Body, &reqData); err != nil {
+		utils.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -79,8 +81,7 @@ func (a *AuthHandler) ConfirmSignUp(w http.ResponseWriter, r *http.Request) {
 		ConfirmationCode: aws.String(reqData.Code),
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		utils.WriteErrorResponse(w, cognitoError(err))
 		return
 	}
 
@@ -90,9 +91,8 @@ func (a *AuthHandler) ConfirmSignUp(w http.ResponseWriter, r *http.Request) {
 
 func (a *AuthHandler) SignIn(w http.ResponseWriter, r *http.Request) {
 	var user User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid JSON"})
+	if err := utils.DecodeJSON(r.Body, &user); err != nil {
+		utils.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -106,8 +106,7 @@ func (a *AuthHandler) SignIn(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid credentials"})
+		utils.WriteErrorResponse(w, models.ErrInvalidCredentials)
 		return
 	}
 
@@ -126,9 +125,8 @@ func (a *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	var reqData struct {
 		Email string `json:"email"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid JSON"})
+	if err := utils.DecodeJSON(r.Body, &reqData); err != nil {
+		utils.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -137,8 +135,7 @@ func (a *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 		Username: aws.String(reqData.Email),
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		utils.WriteErrorResponse(w, cognitoError(err))
 		return
 	}
 
@@ -152,9 +149,8 @@ func (a *AuthHandler) ConfirmForgotPassword(w http.ResponseWriter, r *http.Reque
 		Code        string `json:"code"`
 		NewPassword string `json:"new_password"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid JSON"})
+	if err := utils.DecodeJSON(r.Body, &reqData); err != nil {
+		utils.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -165,8 +161,7 @@ func (a *AuthHandler) ConfirmForgotPassword(w http.ResponseWriter, r *http.Reque
 		Password:         aws.String(reqData.NewPassword),
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		utils.WriteErrorResponse(w, cognitoError(err))
 		return
 	}
 
@@ -178,9 +173,8 @@ func (a *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var reqData struct {
 		RefreshToken string `json:"refresh_token"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid JSON"})
+	if err := utils.DecodeJSON(r.Body, &reqData); err != nil {
+		utils.WriteErrorResponse(w, err)
 		return
 	}
 
@@ -193,8 +187,7 @@ func (a *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid refresh token"})
+		utils.WriteErrorResponse(w, utils.NewHTTPErrorCode(http.StatusUnauthorized, "invalid_refresh_token", "Invalid refresh token"))
 		return
 	}
 
@@ -207,3 +200,26 @@ func (a *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(authResponse)
 }
+
+// cognitoError maps a Cognito API error to a problem with a stable code.
+// Unrecognised Cognito errors are reported as 400 with the Cognito message.
+func cognitoError(err error) error {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return err
+	}
+	switch aerr.Code() {
+	case cognitoidentityprovider.ErrCodeUsernameExistsException:
+		return models.ErrUserAlreadyExists
+	case cognitoidentityprovider.ErrCodeUserNotFoundException:
+		return models.ErrUserNotFound
+	case cognitoidentityprovider.ErrCodeInvalidPasswordException:
+		return utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_password", aerr.Message())
+	case cognitoidentityprovider.ErrCodeCodeMismatchException, cognitoidentityprovider.ErrCodeExpiredCodeException:
+		return utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_confirmation_code", aerr.Message())
+	case cognitoidentityprovider.ErrCodeLimitExceededException, cognitoidentityprovider.ErrCodeTooManyRequestsException:
+		return utils.NewHTTPErrorCode(http.StatusTooManyRequests, "too_many_requests", aerr.Message())
+	default:
+		return utils.NewHTTPErrorCode(http.StatusBadRequest, "auth_request_failed", aerr.Message())
+	}
+}
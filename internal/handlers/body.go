@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"gym-tracker-api/internal/utils"
+)
+
+// decodeOptionalJSON decodes the request body into v, leaving v untouched when
+// the body is empty.
+func decodeOptionalJSON(r *http.Request, v interface{}) error {
+	if r.Body == nil {
+		return nil
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_json", "failed to read request body")
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	return utils.DecodeJSON(bytes.NewReader(data), v)
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gym-tracker-api/internal/utils"
+)
+
+// NotFound answers requests that match no route with a problem response.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	utils.WriteErrorResponse(w, utils.NewHTTPErrorCode(http.StatusNotFound, "route_not_found", "no route for "+r.Method+" "+r.URL.Path))
+}
+
+// MethodNotAllowed answers requests whose path matches a route but whose method does not.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	utils.WriteErrorResponse(w, utils.NewHTTPError(http.StatusMethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path))
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// keepAliveInterval spaces the comments that stop proxies from closing
+	// an idle stream.
+	keepAliveInterval = 25 * time.Second
+	// pollInterval is the reconnection delay sent to clients of a server
+	// that cannot stream, so that their EventSource polls instead.
+	pollInterval = 5 * time.Second
+)
+
+// EventHandler streams workout changes to clients as Server-Sent Events.
+type EventHandler struct {
+	workouts  services.WorkoutService
+	exercises services.ExerciseService
+	profiles  services.ProfileService
+	bus       services.EventBus
+	streaming bool
+	keepAlive time.Duration
+}
+
+// NewEventHandler returns an EventHandler. When streaming is false, as behind
+// API Gateway where responses are buffered, each request gets the current
+// workout and an instruction to reconnect after pollInterval.
+func NewEventHandler(workouts services.WorkoutService, exercises services.ExerciseService, profiles services.ProfileService, bus services.EventBus, streaming bool) *EventHandler {
+	return &EventHandler{
+		workouts:  workouts,
+		exercises: exercises,
+		profiles:  profiles,
+		bus:       bus,
+		streaming: streaming,
+		keepAlive: keepAliveInterval,
+	}
+}
+
+// WorkoutEvents opens an event stream for one workout. The first event is the
+// workout itself; later ones are the models.WorkoutEvent types, carrying the
+// changed workout or exercise. Exercises honour ?units= as elsewhere. The
+// stream ends when the workout is deleted.
+func (h *EventHandler) WorkoutEvents(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	workoutID := mux.Vars(r)["workoutId"]
+
+	flusher, canFlush := w.(http.Flusher)
+	streaming := h.streaming && canFlush
+	var events <-chan models.WorkoutEvent
+	if streaming {
+		// Subscribe before reading the workout so no change falls between
+		// the snapshot and the stream.
+		var cancel func()
+		events, cancel = h.bus.Subscribe(userID)
+		defer cancel()
+	}
+
+	workout, err := h.workouts.GetWorkout(userID, workoutID)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	stream := &eventStream{w: w, units: units, exerciseIDs: exerciseSet(workout)}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	snapshot := models.WorkoutEvent{Type: models.EventWorkout, WorkoutID: workoutID, At: utils.GetCurrentTime(), Workout: workout}
+	if !streaming {
+		fmt.Fprintf(w, "retry: %d\n", pollInterval.Milliseconds())
+		stream.send(snapshot)
+		return
+	}
+	stream.send(snapshot)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(h.keepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and
+				// starts again from a fresh snapshot.
+				return
+			}
+			if !h.fill(userID, workoutID, &event, stream) {
+				continue
+			}
+			stream.send(event)
+			if event.Type == models.EventWorkoutDeleted {
+				flusher.Flush()
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// fill attaches the current workout or exercise to an event and reports
+// whether it concerns the streamed workout. Exercise events are matched by
+// the workout's exercises, which workout events keep up to date.
+func (h *EventHandler) fill(userID, workoutID string, event *models.WorkoutEvent, stream *eventStream) bool {
+	if event.WorkoutID != "" {
+		if event.WorkoutID != workoutID {
+			return false
+		}
+		if event.Type == models.EventWorkoutDeleted {
+			return true
+		}
+		if workout, err := h.workouts.GetWorkout(userID, workoutID); err == nil {
+			event.Workout = workout
+			stream.exerciseIDs = exerciseSet(workout)
+		}
+		return true
+	}
+
+	if !stream.exerciseIDs[event.ExerciseID] {
+		return false
+	}
+	if event.Type != models.EventExerciseRemoved {
+		if exercise, err := h.exercises.GetExercise(userID, event.ExerciseID); err == nil {
+			convertExercises(stream.units, exercise)
+			event.Exercise = exercise
+		}
+	}
+	return true
+}
+
+// eventStream writes events in the text/event-stream format.
+type eventStream struct {
+	w           http.ResponseWriter
+	units       *models.UnitPreferences
+	exerciseIDs map[string]bool
+}
+
+func (s *eventStream) send(event models.WorkoutEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event.Type, data)
+}
+
+func exerciseSet(workout *models.Workout) map[string]bool {
+	ids := make(map[string]bool, len(workout.Exercises))
+	for _, id := range workout.Exercises {
+		ids[id] = true
+	}
+	return ids
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/middleware"
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository/memory"
+	"gym-tracker-api/internal/services"
+
+	"github.com/gorilla/mux"
+)
+
+// fakeBus hands the handler a channel the test publishes to directly.
+type fakeBus struct {
+	events    chan models.WorkoutEvent
+	cancelled chan struct{}
+}
+
+func newFakeBus() *fakeBus {
+	return &fakeBus{events: make(chan models.WorkoutEvent), cancelled: make(chan struct{})}
+}
+
+func (b *fakeBus) Publish(event models.WorkoutEvent) {
+	b.events <- event
+}
+
+func (b *fakeBus) Subscribe(userID string) (<-chan models.WorkoutEvent, func()) {
+	return b.events, func() { close(b.cancelled) }
+}
+
+// flushRecorder passes what the handler has written so far to flushes each
+// time it flushes, so the test never reads the body while the handler writes.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes chan string
+	sent    int
+}
+
+func newFlushRecorder() *flushRecorder {
+	return &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushes: make(chan string, 16)}
+}
+
+func (r *flushRecorder) Flush() {
+	body := r.Body.String()
+	r.flushes <- body[r.sent:]
+	r.sent = len(body)
+}
+
+// next returns what was written before the next flush.
+func (r *flushRecorder) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case chunk := <-r.flushes:
+		return chunk
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a flush")
+		return ""
+	}
+}
+
+type eventFixture struct {
+	handler *EventHandler
+	bus     *fakeBus
+}
+
+// newEventFixture stores workout w-1 holding exercise ex-1 for user-1.
+func newEventFixture(t *testing.T, streaming bool) *eventFixture {
+	t.Helper()
+	workouts, exercises := memory.NewWorkoutRepository(), memory.NewExerciseRepository()
+	if err := exercises.Create("user-1", &models.Exercise{ExerciseID: "ex-1", Name: "Bench Press", ExerciseType: models.ExerciseTypeWeights}); err != nil {
+		t.Fatalf("create exercise: %v", err)
+	}
+	if err := workouts.Create(&models.Workout{UserID: "user-1", WorkoutID: "w-1", Name: "Push Day", Date: "2024-01-15", Exercises: []string{"ex-1"}}); err != nil {
+		t.Fatalf("create workout: %v", err)
+	}
+	bus := newFakeBus()
+	handler := NewEventHandler(services.NewWorkoutService(workouts, exercises, nil, nil), services.NewExerciseService(exercises, nil, nil), nil, bus, streaming)
+	return &eventFixture{handler: handler, bus: bus}
+}
+
+func eventsRequest(ctx context.Context, workoutID string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/workouts/user-1/"+workoutID+"/events", nil)
+	req = req.WithContext(middleware.WithIdentity(ctx, &middleware.Identity{UserID: "user-1"}))
+	return mux.SetURLVars(req, map[string]string{"userId": "user-1", "workoutId": workoutID})
+}
+
+// serve runs WorkoutEvents in the background and returns a channel closed when
+// it returns.
+func (f *eventFixture) serve(ctx context.Context, w http.ResponseWriter, workoutID string) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.handler.WorkoutEvents(w, eventsRequest(ctx, workoutID))
+	}()
+	return done
+}
+
+func waitDone(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not return")
+	}
+}
+
+func TestWorkoutEvents_StartsWithSnapshot(t *testing.T) {
+	f := newEventFixture(t, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := newFlushRecorder()
+	done := f.serve(ctx, rec, "w-1")
+
+	snapshot := rec.next(t)
+	if !strings.HasPrefix(snapshot, "event: workout\ndata: {") || !strings.Contains(snapshot, `"workoutId":"w-1"`) ||
+		!strings.Contains(snapshot, `"exercises":["ex-1"]`) {
+		t.Errorf("first event = %q, want the workout snapshot", snapshot)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	cancel()
+	waitDone(t, done)
+	<-f.bus.cancelled
+}
+
+func TestWorkoutEvents_PollingFallback(t *testing.T) {
+	f := newEventFixture(t, false)
+	rec := httptest.NewRecorder()
+	f.handler.WorkoutEvents(rec, eventsRequest(context.Background(), "w-1"))
+
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "retry: 5000\nevent: workout\ndata: {") || !strings.HasSuffix(body, "\n\n") {
+		t.Errorf("body = %q, want a retry instruction and the snapshot", body)
+	}
+	if strings.Count(body, "event:") != 1 {
+		t.Errorf("body = %q, want only the snapshot", body)
+	}
+}
+
+func TestWorkoutEvents_MissingWorkout(t *testing.T) {
+	f := newEventFixture(t, false)
+	rec := httptest.NewRecorder()
+	f.handler.WorkoutEvents(rec, eventsRequest(context.Background(), "missing"))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestWorkoutEvents_DropsOtherExercisesAndWorkouts(t *testing.T) {
+	f := newEventFixture(t, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rec := newFlushRecorder()
+	done := f.serve(ctx, rec, "w-1")
+	rec.next(t) // snapshot
+
+	f.bus.Publish(models.WorkoutEvent{Type: models.EventExerciseUpdated, UserID: "user-1", ExerciseID: "ex-other"})
+	f.bus.Publish(models.WorkoutEvent{Type: models.EventWorkoutUpdated, UserID: "user-1", WorkoutID: "w-other"})
+	f.bus.Publish(models.WorkoutEvent{Type: models.EventExerciseUpdated, UserID: "user-1", ExerciseID: "ex-1"})
+
+	chunk := rec.next(t)
+	if strings.Contains(chunk, "ex-other") || strings.Contains(chunk, "w-other") {
+		t.Errorf("streamed %q, want events for other exercises and workouts dropped", chunk)
+	}
+	if !strings.HasPrefix(chunk, "event: exercise_updated\n") || !strings.Contains(chunk, `"name":"Bench Press"`) {
+		t.Errorf("streamed %q, want exercise_updated carrying ex-1", chunk)
+	}
+
+	cancel()
+	waitDone(t, done)
+}
+
+func TestWorkoutEvents_ClosesOnWorkoutDeleted(t *testing.T) {
+	f := newEventFixture(t, true)
+	rec := newFlushRecorder()
+	done := f.serve(context.Background(), rec, "w-1")
+	rec.next(t) // snapshot
+
+	f.bus.Publish(models.WorkoutEvent{Type: models.EventWorkoutDeleted, UserID: "user-1", WorkoutID: "w-1"})
+	if chunk := rec.next(t); !strings.HasPrefix(chunk, "event: workout_deleted\n") {
+		t.Errorf("streamed %q, want workout_deleted", chunk)
+	}
+	waitDone(t, done)
+	<-f.bus.cancelled
+}
+
+func TestWorkoutEvents_SendsKeepAlives(t *testing.T) {
+	f := newEventFixture(t, true)
+	f.handler.keepAlive = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := newFlushRecorder()
+	done := f.serve(ctx, rec, "w-1")
+	rec.next(t) // snapshot
+
+	if chunk := rec.next(t); chunk != ": keep-alive\n\n" {
+		t.Errorf("streamed %q, want a keep-alive comment", chunk)
+	}
+
+	cancel()
+	waitDone(t, done)
+}
+
+// tokenVerifier accepts only the token "valid", as user-1.
+type tokenVerifier struct{}
+
+func (tokenVerifier) Verify(token string) (*middleware.TokenClaims, error) {
+	if token != "valid" {
+		return nil, models.ErrTokenInvalid
+	}
+	return &middleware.TokenClaims{Sub: "user-1"}, nil
+}
+
+// EventSource cannot send an Authorization header, so browsers authenticate
+// the stream with the access_token query parameter instead.
+func TestWorkoutEvents_QueryTokenWithoutHeader(t *testing.T) {
+	f := newEventFixture(t, false)
+	r := mux.NewRouter()
+	auth := middleware.NewAuthMiddleware(tokenVerifier{}, "")
+	r.HandleFunc("/workouts/{userId}/{workoutId}/events", auth.AuthenticateStream(f.handler.WorkoutEvents)).Methods("GET")
+
+	tests := []struct {
+		query string
+		code  int
+	}{
+		{"?access_token=valid", http.StatusOK},
+		{"?access_token=forged", http.StatusUnauthorized},
+		{"", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/workouts/user-1/w-1/events"+tt.query, nil))
+		if rec.Code != tt.code {
+			t.Errorf("%q: status = %d, want %d", tt.query, rec.Code, tt.code)
+		}
+		if tt.code == http.StatusOK && !strings.Contains(rec.Body.String(), "event: workout\n") {
+			t.Errorf("%q: body = %q, want the workout snapshot", tt.query, rec.Body.String())
+		}
+	}
+}
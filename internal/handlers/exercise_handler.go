@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
 	"gym-tracker-api/internal/services"
 	"gym-tracker-api/internal/utils"
 	"net/http"
@@ -10,50 +11,108 @@ import (
 )
 
 type ExerciseHandler struct {
-	service services.ExerciseService
+	service  services.ExerciseService
+	profiles services.ProfileService
 }
 
-func NewExerciseHandler(service services.ExerciseService) *ExerciseHandler {
+func NewExerciseHandler(service services.ExerciseService, profiles services.ProfileService) *ExerciseHandler {
 	return &ExerciseHandler{
-		service: service,
+		service:  service,
+		profiles: profiles,
 	}
 }
 
 func (h *ExerciseHandler) GetExercises(w http.ResponseWriter, r *http.Request) {
-	userID := mux.Vars(r)["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 
-	exercises, err := h.service.GetExercises(userID)
+	opts, err := listOptionsFromRequest(r)
 	if err != nil {
 		utils.WriteErrorResponse(w, err)
 		return
 	}
-	utils.WriteJSONResponse(w, exercises, http.StatusOK)
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+	exerciseType := query.Get("type")
+
+	var page *repository.Page[*models.Exercise]
+	switch {
+	case prefix != "" && exerciseType != "":
+		utils.WriteErrorResponse(w, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "prefix and type cannot be combined"))
+		return
+	case exerciseType != "":
+		if !models.IsValidExerciseType(exerciseType) {
+			utils.WriteErrorResponse(w, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query",
+				"invalid type: must be one of weights, cardio, body_weight, other"))
+			return
+		}
+		page, err = h.service.ListExercisesByType(userID, exerciseType, opts)
+	case prefix != "":
+		page, err = h.service.SearchExercisesByName(userID, prefix, opts)
+	default:
+		page, err = h.service.GetExercises(userID, opts)
+	}
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	convertExercises(units, page.Items...)
+	utils.WriteJSONResponse(w, page, http.StatusOK)
 }
 
 func (h *ExerciseHandler) GetExercise(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	exerciseID := vars["exerciseId"]
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 
 	exercise, err := h.service.GetExercise(userID, exerciseID)
 	if err != nil {
 		utils.WriteErrorResponse(w, err)
 		return
 	}
+	convertExercises(units, exercise)
 	utils.WriteJSONResponse(w, exercise, http.StatusOK)
 }
 
 func (h *ExerciseHandler) ListExercisesByName(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	exerciseName := vars["exerciseName"]
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 
-	exercise, err := h.service.ListExercisesByName(userID, exerciseName)
+	exercises, err := h.service.ListExercisesByName(userID, exerciseName)
 	if err != nil {
 		utils.WriteErrorResponse(w, err)
 		return
 	}
-	utils.WriteJSONResponse(w, exercise, http.StatusOK)
+	convertExercises(units, exercises...)
+	utils.WriteJSONResponse(w, exercises, http.StatusOK)
 }
 
 type exerciseRequest struct {
@@ -62,8 +121,17 @@ type exerciseRequest struct {
 }
 
 func (h *ExerciseHandler) CreateExercise(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userId := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 
 	var req exerciseRequest
 	if err := utils.DecodeJSON(r.Body, &req); err != nil {
@@ -72,19 +140,30 @@ func (h *ExerciseHandler) CreateExercise(w http.ResponseWriter, r *http.Request)
 	}
 
 	exercise := req.Exercise
-	if err := h.service.CreateExercise(userId, &exercise, req.StoreRPM); err != nil {
+	if err := h.service.CreateExercise(userID, &exercise, req.StoreRPM); err != nil {
 		utils.WriteErrorResponse(w, err)
 		return
 	}
 
+	convertExercises(units, &exercise)
 	utils.WriteJSONResponse(w, exercise, http.StatusCreated)
 }
 
 func (h *ExerciseHandler) UpdateExercise(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	exerciseID := vars["exerciseId"]
 
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
 	var req exerciseRequest
 	if err := utils.DecodeJSON(r.Body, &req); err != nil {
 		utils.WriteErrorResponse(w, err)
@@ -97,12 +176,17 @@ func (h *ExerciseHandler) UpdateExercise(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	convertExercises(units, &exercise)
 	utils.WriteJSONResponse(w, exercise, http.StatusOK)
 }
 
 func (h *ExerciseHandler) DeleteExercise(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	exerciseID := vars["exerciseId"]
 
 	if err := h.service.DeleteExercise(userID, exerciseID); err != nil {
@@ -112,3 +196,27 @@ func (h *ExerciseHandler) DeleteExercise(w http.ResponseWriter, r *http.Request)
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// RestoreExercise takes a deleted exercise out of the trash and returns it.
+func (h *ExerciseHandler) RestoreExercise(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	exerciseID := vars["exerciseId"]
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	exercise, err := h.service.RestoreExercise(userID, exerciseID)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	convertExercises(units, exercise)
+	utils.WriteJSONResponse(w, exercise, http.StatusOK)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gym-tracker-api/internal/middleware"
+	"gym-tracker-api/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// requestUserID returns the ID of the user whose data the request operates on.
+// For regular callers this is always their own Cognito sub; admins may act on
+// behalf of the user named in the {userId} path segment.
+func requestUserID(r *http.Request) (string, error) {
+	identity, ok := middleware.IdentityFromContext(r.Context())
+	if !ok {
+		return "", utils.NewHTTPError(http.StatusUnauthorized, "unauthenticated request")
+	}
+	if pathUserID := mux.Vars(r)["userId"]; identity.Admin && pathUserID != "" {
+		return pathUserID, nil
+	}
+	return identity.UserID, nil
+}
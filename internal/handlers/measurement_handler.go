@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type MeasurementHandler struct {
+	service  services.MeasurementService
+	profiles services.ProfileService
+}
+
+func NewMeasurementHandler(service services.MeasurementService, profiles services.ProfileService) *MeasurementHandler {
+	return &MeasurementHandler{
+		service:  service,
+		profiles: profiles,
+	}
+}
+
+func (h *MeasurementHandler) GetMeasurement(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	measurement, err := h.service.GetMeasurement(userID, mux.Vars(r)["measurementId"])
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	convertMeasurements(units, measurement)
+	utils.WriteJSONResponse(w, measurement, http.StatusOK)
+}
+
+// ListMeasurements returns the user's measurements ordered by date, optionally
+// limited to the from and to query parameters.
+func (h *MeasurementHandler) ListMeasurements(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	opts, err := listOptionsFromRequest(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	dateRange, err := dateRangeFromRequest(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	page, err := h.service.ListMeasurements(userID, dateRange, opts)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	convertMeasurements(units, page.Items...)
+	utils.WriteJSONResponse(w, page, http.StatusOK)
+}
+
+// CreateMeasurement records a measurement. The date defaults to today in the
+// user's timezone.
+func (h *MeasurementHandler) CreateMeasurement(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var measurement models.Measurement
+	if err := utils.DecodeJSON(r.Body, &measurement); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	// IDs and ownership are assigned by the server, never taken from the body.
+	measurement.UserID = userID
+	measurement.MeasurementID = utils.GenerateUUID()
+	measurement.CreatedAt = utils.GetCurrentTime()
+
+	if err := h.service.CreateMeasurement(&measurement); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, measurement, http.StatusCreated)
+}
+
+func (h *MeasurementHandler) UpdateMeasurement(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	measurementID := mux.Vars(r)["measurementId"]
+
+	existing, err := h.service.GetMeasurement(userID, measurementID)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var measurement models.Measurement
+	if err := utils.DecodeJSON(r.Body, &measurement); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	measurement.CreatedAt = existing.CreatedAt
+	if measurement.Date == "" {
+		measurement.Date = existing.Date
+	}
+
+	if err := h.service.UpdateMeasurement(userID, measurementID, &measurement); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, measurement, http.StatusOK)
+}
+
+func (h *MeasurementHandler) DeleteMeasurement(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	if err := h.service.DeleteMeasurement(userID, mux.Vars(r)["measurementId"]); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
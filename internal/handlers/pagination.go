@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/utils"
+)
+
+// listOptionsFromRequest reads the limit, cursor and order query parameters.
+func listOptionsFromRequest(r *http.Request) (repository.ListOptions, error) {
+	query := r.URL.Query()
+	opts := repository.ListOptions{Cursor: query.Get("cursor")}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return opts, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "limit must be a positive integer")
+		}
+		opts.Limit = n
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		opts.Descending = true
+	default:
+		return opts, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "order must be asc or desc")
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/utils"
+)
+
+type ProfileHandler struct {
+	service services.ProfileService
+}
+
+func NewProfileHandler(service services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{
+		service: service,
+	}
+}
+
+// GetProfile returns the user's profile, creating it with default settings on
+// first use.
+func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	profile, err := h.service.GetProfile(userID)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, profile, http.StatusOK)
+}
+
+// UpdateProfile replaces the user's profile settings.
+func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var profile models.UserProfile
+	if err := utils.DecodeJSON(r.Body, &profile); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	if err := h.service.UpdateProfile(userID, &profile); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, profile, http.StatusOK)
+}
+
+// GetUnits returns the user's preferred weight and distance units.
+func (h *ProfileHandler) GetUnits(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	units, err := h.service.GetUnits(userID)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, units, http.StatusOK)
+}
+
+// UpdateUnits replaces the user's preferred weight and distance units.
+func (h *ProfileHandler) UpdateUnits(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var units models.UnitPreferences
+	if err := utils.DecodeJSON(r.Body, &units); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	units, err = h.service.UpdateUnits(userID, units)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, units, http.StatusOK)
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type ProgramHandler struct {
+	service services.ProgramService
+}
+
+func NewProgramHandler(service services.ProgramService) *ProgramHandler {
+	return &ProgramHandler{
+		service: service,
+	}
+}
+
+func (h *ProgramHandler) GetProgram(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	program, err := h.service.GetProgram(userID, mux.Vars(r)["programId"])
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, program, http.StatusOK)
+}
+
+func (h *ProgramHandler) ListPrograms(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	opts, err := listOptionsFromRequest(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	page, err := h.service.GetPrograms(userID, opts)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, page, http.StatusOK)
+}
+
+func (h *ProgramHandler) CreateProgram(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var program models.Program
+	if err := utils.DecodeJSON(r.Body, &program); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	// IDs and ownership are assigned by the server, never taken from the body.
+	program.UserID = userID
+	program.ProgramID = utils.GenerateUUID()
+	program.CreatedAt = utils.GetCurrentTime()
+
+	if err := h.service.CreateProgram(&program); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, program, http.StatusCreated)
+}
+
+func (h *ProgramHandler) UpdateProgram(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var program models.Program
+	if err := utils.DecodeJSON(r.Body, &program); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	if err := h.service.UpdateProgram(userID, mux.Vars(r)["programId"], &program); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, program, http.StatusOK)
+}
+
+func (h *ProgramHandler) DeleteProgram(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	if err := h.service.DeleteProgram(userID, mux.Vars(r)["programId"]); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Enroll starts the program. The optional body {"startDate": "2024-03-04"}
+// sets the first day of week 1; it defaults to today.
+func (h *ProgramHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var body struct {
+		StartDate string `json:"startDate"`
+	}
+	if err := decodeOptionalJSON(r, &body); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	program, err := h.service.Enroll(userID, mux.Vars(r)["programId"], body.StartDate)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, program, http.StatusOK)
+}
+
+func (h *ProgramHandler) Unenroll(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	program, err := h.service.Unenroll(userID, mux.Vars(r)["programId"])
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, program, http.StatusOK)
+}
+
+// GetTodaySession returns the session scheduled today, or on ?date=YYYY-MM-DD.
+func (h *ProgramHandler) GetTodaySession(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	session, err := h.service.SessionOn(userID, mux.Vars(r)["programId"], r.URL.Query().Get("date"))
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, session, http.StatusOK)
+}
+
+// GetProgress reports progress through the block as of today, or ?date=YYYY-MM-DD.
+func (h *ProgramHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	progress, err := h.service.Progress(userID, mux.Vars(r)["programId"], r.URL.Query().Get("date"))
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, progress, http.StatusOK)
+}
+
+// StartSession creates the workout for a scheduled session. The optional body
+// {"date": "2024-03-11"} picks the session; it defaults to today's.
+func (h *ProgramHandler) StartSession(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var body struct {
+		Date string `json:"date"`
+	}
+	if err := decodeOptionalJSON(r, &body); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	detail, err := h.service.StartSession(userID, mux.Vars(r)["programId"], body.Date)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, detail, http.StatusCreated)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/utils"
+)
+
+type RecordHandler struct {
+	service services.RecordService
+}
+
+func NewRecordHandler(service services.RecordService) *RecordHandler {
+	return &RecordHandler{
+		service: service,
+	}
+}
+
+// ListRecords returns the current personal records and their history for each
+// exercise name, optionally limited to one name with ?name=.
+func (h *RecordHandler) ListRecords(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	records, err := h.service.GetRecords(userID, r.URL.Query().Get("name"))
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, records, http.StatusOK)
+}
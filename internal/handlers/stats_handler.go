@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type StatsHandler struct {
+	service services.StatsService
+	cardio  services.CardioService
+}
+
+func NewStatsHandler(service services.StatsService, cardio services.CardioService) *StatsHandler {
+	return &StatsHandler{
+		service: service,
+		cardio:  cardio,
+	}
+}
+
+// GetExerciseStats returns a time series for one exercise name. Query
+// parameters: metric (e1rm, tonnage, reps, sets, density), bucket (day, week,
+// month), formula (epley, brzycki), unit (kg, lb) and an optional from/to date
+// range.
+func (h *StatsHandler) GetExerciseStats(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	dateRange, err := dateRangeFromRequest(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	query := r.URL.Query()
+	statsQuery := models.StatsQuery{
+		Metric:  query.Get("metric"),
+		Bucket:  query.Get("bucket"),
+		Formula: query.Get("formula"),
+		Unit:    query.Get("unit"),
+		From:    dateRange.From,
+		To:      dateRange.To,
+	}
+
+	stats, err := h.service.ExerciseStats(userID, mux.Vars(r)["name"], statsQuery)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, stats, http.StatusOK)
+}
+
+// GetCardioStats returns the analysed sessions of one cardio exercise name.
+// Query parameters: unit (km, mi), bucket (day, week, month) for the RPM
+// trend, and an optional from/to date range.
+func (h *StatsHandler) GetCardioStats(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	dateRange, err := dateRangeFromRequest(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	query := r.URL.Query()
+	cardioQuery := models.CardioQuery{
+		Unit:   query.Get("unit"),
+		Bucket: query.Get("bucket"),
+		From:   dateRange.From,
+		To:     dateRange.To,
+	}
+
+	stats, err := h.cardio.CardioStats(userID, mux.Vars(r)["name"], cardioQuery)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, stats, http.StatusOK)
+}
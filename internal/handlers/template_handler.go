@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type TemplateHandler struct {
+	service services.TemplateService
+}
+
+func NewTemplateHandler(service services.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		service: service,
+	}
+}
+
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	template, err := h.service.GetTemplate(userID, mux.Vars(r)["templateId"])
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, template, http.StatusOK)
+}
+
+func (h *TemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	opts, err := listOptionsFromRequest(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	page, err := h.service.GetTemplates(userID, opts)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, page, http.StatusOK)
+}
+
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var template models.Template
+	if err := utils.DecodeJSON(r.Body, &template); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	// IDs and ownership are assigned by the server, never taken from the body.
+	template.UserID = userID
+	template.TemplateID = utils.GenerateUUID()
+	template.CreatedAt = utils.GetCurrentTime()
+
+	if err := h.service.CreateTemplate(&template); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, template, http.StatusCreated)
+}
+
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	templateID := mux.Vars(r)["templateId"]
+
+	existing, err := h.service.GetTemplate(userID, templateID)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var template models.Template
+	if err := utils.DecodeJSON(r.Body, &template); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	template.CreatedAt = existing.CreatedAt
+
+	if err := h.service.UpdateTemplate(userID, templateID, &template); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, template, http.StatusOK)
+}
+
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	if err := h.service.DeleteTemplate(userID, mux.Vars(r)["templateId"]); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StartTemplate creates a workout from the template. The optional body
+// {"date": "2024-01-15"} sets the workout date; it defaults to today.
+func (h *TemplateHandler) StartTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var body struct {
+		Date string `json:"date"`
+	}
+	if err := decodeOptionalJSON(r, &body); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	detail, err := h.service.StartTemplate(userID, mux.Vars(r)["templateId"], body.Date)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, detail, http.StatusCreated)
+}
+
+// CreateTemplateFromWorkout saves a workout as a template. The optional body
+// {"name": "..."} names the template; it defaults to the workout's name.
+func (h *TemplateHandler) CreateTemplateFromWorkout(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := decodeOptionalJSON(r, &body); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	template, err := h.service.TemplateFromWorkout(userID, mux.Vars(r)["workoutId"], body.Name)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, template, http.StatusCreated)
+}
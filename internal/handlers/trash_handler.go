@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/utils"
+)
+
+// TrashHandler lists the workouts and exercises a user has deleted and can
+// still restore.
+type TrashHandler struct {
+	workouts  services.WorkoutService
+	exercises services.ExerciseService
+	profiles  services.ProfileService
+	retention time.Duration
+}
+
+// NewTrashHandler returns a TrashHandler. retention is how long deleted items
+// are kept, reported to clients so they can show when items will be purged.
+func NewTrashHandler(workouts services.WorkoutService, exercises services.ExerciseService, profiles services.ProfileService, retention time.Duration) *TrashHandler {
+	return &TrashHandler{
+		workouts:  workouts,
+		exercises: exercises,
+		profiles:  profiles,
+		retention: retention,
+	}
+}
+
+type trashResponse struct {
+	Workouts      *repository.Page[*models.Workout]  `json:"workouts,omitempty"`
+	Exercises     *repository.Page[*models.Exercise] `json:"exercises,omitempty"`
+	RetentionDays int                                `json:"retentionDays"`
+}
+
+// ListTrash returns the first page of deleted workouts and of deleted
+// exercises. ?type=workouts or ?type=exercises lists only that kind, and is
+// required to follow a cursor. Exercises honour ?units= as elsewhere.
+func (h *TrashHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	opts, err := listOptionsFromRequest(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	units, err := responseUnits(r, userID, h.profiles)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	kind := r.URL.Query().Get("type")
+	switch kind {
+	case "":
+		if opts.Cursor != "" {
+			utils.WriteErrorResponse(w, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "cursor requires type"))
+			return
+		}
+	case "workouts", "exercises":
+	default:
+		utils.WriteErrorResponse(w, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "type must be workouts or exercises"))
+		return
+	}
+
+	response := trashResponse{RetentionDays: int(h.retention / (24 * time.Hour))}
+	if kind != "exercises" {
+		if response.Workouts, err = h.workouts.ListDeletedWorkouts(userID, opts); err != nil {
+			utils.WriteErrorResponse(w, err)
+			return
+		}
+	}
+	if kind != "workouts" {
+		if response.Exercises, err = h.exercises.ListDeletedExercises(userID, opts); err != nil {
+			utils.WriteErrorResponse(w, err)
+			return
+		}
+		convertExercises(units, response.Exercises.Items...)
+	}
+	utils.WriteJSONResponse(w, response, http.StatusOK)
+}
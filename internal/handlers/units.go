@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/services"
+	"gym-tracker-api/internal/utils"
+)
+
+// responseUnits returns the units exercises in the response should be
+// converted to, from ?units= or else the Accept-Units header: "preferred"
+// for the user's profile units, or explicit units such as "lb,mi" that
+// override them. It returns nil when the caller asked for neither, in which
+// case exercises are returned in the units they were logged in.
+func responseUnits(r *http.Request, userID string, profiles services.ProfileService) (*models.UnitPreferences, error) {
+	spec := r.URL.Query().Get("units")
+	if spec == "" {
+		spec = r.Header.Get("Accept-Units")
+	}
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	preferred, err := profiles.GetUnits(userID)
+	if err != nil {
+		return nil, err
+	}
+	units, err := models.ParseUnits(spec, preferred)
+	if err != nil {
+		return nil, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "units: "+err.Error())
+	}
+	return &units, nil
+}
+
+// convertExercises converts exercises in place; a nil units is a no-op.
+func convertExercises(units *models.UnitPreferences, exercises ...*models.Exercise) {
+	if units == nil {
+		return
+	}
+	for _, exercise := range exercises {
+		exercise.ConvertUnits(*units)
+	}
+}
+
+// convertMeasurements converts measurements in place; a nil units is a no-op.
+func convertMeasurements(units *models.UnitPreferences, measurements ...*models.Measurement) {
+	if units == nil {
+		return
+	}
+	for _, measurement := range measurements {
+		measurement.ConvertUnits(*units)
+	}
+}
@@ -2,28 +2,57 @@ package handlers
 
 import (
 	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
 	"gym-tracker-api/internal/services"
 	"gym-tracker-api/internal/utils"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 type WorkoutHandler struct {
-	service services.WorkoutService
+	service  services.WorkoutService
+	profiles services.ProfileService
 }
 
-func NewWorkoutHandler(service services.WorkoutService) *WorkoutHandler {
+func NewWorkoutHandler(service services.WorkoutService, profiles services.ProfileService) *WorkoutHandler {
 	return &WorkoutHandler{
-		service: service,
+		service:  service,
+		profiles: profiles,
 	}
 }
 
 func (h *WorkoutHandler) GetWorkout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	workoutID := vars["workoutId"]
 
+	switch r.URL.Query().Get("expand") {
+	case "":
+	case "exercises":
+		units, err := responseUnits(r, userID, h.profiles)
+		if err != nil {
+			utils.WriteErrorResponse(w, err)
+			return
+		}
+		detail, err := h.service.GetWorkoutDetail(userID, workoutID)
+		if err != nil {
+			utils.WriteErrorResponse(w, err)
+			return
+		}
+		convertExercises(units, detail.Exercises...)
+		utils.WriteJSONResponse(w, detail, http.StatusOK)
+		return
+	default:
+		utils.WriteErrorResponse(w, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "expand must be exercises"))
+		return
+	}
+
 	workout, err := h.service.GetWorkout(userID, workoutID)
 	if err != nil {
 		utils.WriteErrorResponse(w, err)
@@ -33,29 +62,84 @@ func (h *WorkoutHandler) GetWorkout(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *WorkoutHandler) ListWorkouts(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 
-	workouts, err := h.service.GetWorkouts(userID)
+	opts, err := listOptionsFromRequest(r)
 	if err != nil {
 		utils.WriteErrorResponse(w, err)
 		return
 	}
 
-	utils.WriteJSONResponse(w, workouts, http.StatusOK)
+	var page *repository.Page[*models.Workout]
+	query := r.URL.Query()
+	if query.Has("from") || query.Has("to") || query.Has("order") {
+		var dateRange repository.DateRange
+		if dateRange, err = dateRangeFromRequest(r); err != nil {
+			utils.WriteErrorResponse(w, err)
+			return
+		}
+		page, err = h.service.ListWorkoutsByDateRange(userID, dateRange, opts)
+	} else {
+		page, err = h.service.GetWorkouts(userID, opts)
+	}
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, page, http.StatusOK)
+}
+
+// dateRangeFromRequest reads the from and to query parameters, which must be
+// ISO-8601 dates or timestamps.
+func dateRangeFromRequest(r *http.Request) (repository.DateRange, error) {
+	query := r.URL.Query()
+	dateRange := repository.DateRange{From: query.Get("from"), To: query.Get("to")}
+
+	var from, to time.Time
+	var err error
+	if dateRange.From != "" {
+		if from, err = models.ParseDate(dateRange.From); err != nil {
+			return dateRange, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "from: "+err.Error())
+		}
+	}
+	if dateRange.To != "" {
+		if to, err = models.ParseDate(dateRange.To); err != nil {
+			return dateRange, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "to: "+err.Error())
+		}
+	}
+	if dateRange.From != "" && dateRange.To != "" && to.Before(from) {
+		return dateRange, utils.NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "from must not be after to")
+	}
+
+	return dateRange, nil
 }
 
 func (h *WorkoutHandler) CreateWorkout(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
 	var workout models.Workout
 	workout.WorkoutID = utils.GenerateUUID()
 	workout.CreatedAt = utils.GetCurrentTime()
-	workout.UserID = mux.Vars(r)["userId"]
 	workout.Exercises = []string{}
 
 	if err := utils.DecodeJSON(r.Body, &workout); err != nil {
 		utils.WriteErrorResponse(w, err)
 		return
 	}
+	// The owner always comes from the authenticated identity, never the body.
+	workout.UserID = userID
+	if workout.Date == "" {
+		workout.Date = h.service.Today(userID)
+	}
 
 	if err := h.service.CreateWorkout(&workout); err != nil {
 		utils.WriteErrorResponse(w, err)
@@ -67,7 +151,11 @@ func (h *WorkoutHandler) CreateWorkout(w http.ResponseWriter, r *http.Request) {
 
 func (h *WorkoutHandler) UpdateWorkout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	workoutID := vars["workoutId"]
 
 	var workout models.Workout
@@ -86,7 +174,11 @@ func (h *WorkoutHandler) UpdateWorkout(w http.ResponseWriter, r *http.Request) {
 
 func (h *WorkoutHandler) DeleteWorkout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	workoutID := vars["workoutId"]
 
 	if err := h.service.DeleteWorkout(userID, workoutID); err != nil {
@@ -97,9 +189,18 @@ func (h *WorkoutHandler) DeleteWorkout(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RestoreWorkout takes a deleted workout out of the trash and returns it.
+func (h *WorkoutHandler) RestoreWorkout(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.RestoreWorkout)
+}
+
 func (h *WorkoutHandler) AddExerciseToWorkout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	workoutID := vars["workoutId"]
 	exerciseID := vars["exerciseId"]
 
@@ -113,7 +214,11 @@ func (h *WorkoutHandler) AddExerciseToWorkout(w http.ResponseWriter, r *http.Req
 
 func (h *WorkoutHandler) RemoveExerciseFromWorkout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	workoutID := vars["workoutId"]
 	exerciseID := vars["exerciseId"]
 
@@ -125,9 +230,117 @@ func (h *WorkoutHandler) RemoveExerciseFromWorkout(w http.ResponseWriter, r *htt
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ReorderWorkout rearranges the workout's exercises. The body
+// {"exerciseIds": [...]} lists every exercise in its new position; exercises
+// in a group must stay together.
+func (h *WorkoutHandler) ReorderWorkout(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var body struct {
+		ExerciseIDs []string `json:"exerciseIds"`
+	}
+	if err := utils.DecodeJSON(r.Body, &body); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	workout, err := h.service.ReorderWorkout(userID, mux.Vars(r)["workoutId"], body.ExerciseIDs)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, workout, http.StatusOK)
+}
+
+// GroupExercises gathers exercises already in the workout into a superset,
+// circuit, EMOM or AMRAP described by the body, e.g.
+// {"kind": "superset", "exerciseIds": ["a", "b"], "rounds": 3, "restSeconds": 90}.
+func (h *WorkoutHandler) GroupExercises(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	var group models.ExerciseGroup
+	if err := utils.DecodeJSON(r.Body, &group); err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	workout, err := h.service.GroupExercises(userID, mux.Vars(r)["workoutId"], group)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, workout, http.StatusCreated)
+}
+
+// UngroupExercises splits a group back into single exercises.
+func (h *WorkoutHandler) UngroupExercises(w http.ResponseWriter, r *http.Request) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	workout, err := h.service.UngroupExercises(userID, vars["workoutId"], vars["groupId"])
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, workout, http.StatusOK)
+}
+
+// StartWorkout, PauseWorkout, ResumeWorkout, FinishWorkout and
+// AbandonWorkout move a workout through its live session.
+func (h *WorkoutHandler) StartWorkout(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.StartWorkout)
+}
+
+func (h *WorkoutHandler) PauseWorkout(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.PauseWorkout)
+}
+
+func (h *WorkoutHandler) ResumeWorkout(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.ResumeWorkout)
+}
+
+func (h *WorkoutHandler) FinishWorkout(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.FinishWorkout)
+}
+
+func (h *WorkoutHandler) AbandonWorkout(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.AbandonWorkout)
+}
+
+func (h *WorkoutHandler) transition(w http.ResponseWriter, r *http.Request, apply func(userID, workoutID string) (*models.Workout, error)) {
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+
+	workout, err := apply(userID, mux.Vars(r)["workoutId"])
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
+	utils.WriteJSONResponse(w, workout, http.StatusOK)
+}
+
 func (h *WorkoutHandler) ListExercisesInWorkout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userId"]
+	userID, err := requestUserID(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, err)
+		return
+	}
 	workoutID := vars["workoutId"]
 
 	workout, err := h.service.GetWorkout(userID, workoutID)
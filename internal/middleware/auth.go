@@ -1,54 +1,196 @@
 package middleware
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strings"
+	"sync"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/utils"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider/cognitoidentityprovideriface"
+	"github.com/gorilla/mux"
 )
 
+type contextKey string
+
+const identityContextKey contextKey = "identity"
+
+// Identity describes the authenticated caller of a request.
+type Identity struct {
+	UserID string   // Cognito sub
+	Groups []string // Cognito groups from the access token
+	Admin  bool     // member of the configured admin group
+}
+
+// WithIdentity returns a copy of ctx carrying the given identity.
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey, identity)
+}
+
+// IdentityFromContext returns the identity stored by Authenticate, if any.
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityContextKey).(*Identity)
+	return identity, ok && identity != nil
+}
+
+// ProfileEnsurer creates a user's profile if it does not exist yet.
+type ProfileEnsurer interface {
+	EnsureProfile(userID string) error
+}
+
 // AuthMiddleware is a middleware for authenticating requests using AWS Cognito
 type AuthMiddleware struct {
-	cognito *cognitoidentityprovider.CognitoIdentityProvider
+	verifier   TokenVerifier
+	adminGroup string
+	profiles   ProfileEnsurer
+	ensured    sync.Map // user IDs whose profile is known to exist
 }
 
-func NewAuthMiddleware(cognito *cognitoidentityprovider.CognitoIdentityProvider) *AuthMiddleware {
+// NewAuthMiddleware creates an AuthMiddleware. Members of adminGroup may access
+// any user's data; an empty adminGroup disables the admin override.
+func NewAuthMiddleware(verifier TokenVerifier, adminGroup string) *AuthMiddleware {
 	return &AuthMiddleware{
-		cognito: cognito,
+		verifier:   verifier,
+		adminGroup: adminGroup,
 	}
 }
 
+// WithProfiles makes Authenticate create a default profile the first time it
+// sees each caller. Failures are logged and retried on the next request; they
+// never fail the request itself.
+func (m *AuthMiddleware) WithProfiles(profiles ProfileEnsurer) *AuthMiddleware {
+	m.profiles = profiles
+	return m
+}
+
+func (m *AuthMiddleware) ensureProfile(userID string) {
+	if m.profiles == nil {
+		return
+	}
+	if _, ok := m.ensured.Load(userID); ok {
+		return
+	}
+	if err := m.profiles.EnsureProfile(userID); err != nil {
+		log.Printf("failed to create profile for %s: %v", userID, err)
+		return
+	}
+	m.ensured.Store(userID, struct{}{})
+}
+
 func (m *AuthMiddleware) Authenticate(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Authorization header required"})
+			utils.WriteErrorResponse(w, utils.NewHTTPErrorCode(http.StatusUnauthorized, "missing_token", "Authorization header required"))
 			return
 		}
 
 		// Extract token from "Bearer <token>"
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid authorization format"})
+			utils.WriteErrorResponse(w, utils.NewHTTPErrorCode(http.StatusUnauthorized, "missing_token", "Invalid authorization format"))
 			return
 		}
 
-		accessToken := tokenParts[1]
+		m.authenticateToken(w, r, tokenParts[1], next)
+	}
+}
 
-		// Validate token with Cognito
-		_, err := m.cognito.GetUser(&cognitoidentityprovider.GetUserInput{
-			AccessToken: aws.String(accessToken),
-		})
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid or expired token"})
+// AuthenticateStream is Authenticate for event streams. Browser EventSource
+// cannot send an Authorization header, so the access token may instead be
+// passed in the access_token query parameter. It is verified exactly like a
+// bearer token, so it expires with the Cognito session.
+func (m *AuthMiddleware) AuthenticateStream(next http.HandlerFunc) http.HandlerFunc {
+	authenticate := m.Authenticate(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("access_token")
+		if token == "" || r.Header.Get("Authorization") != "" {
+			authenticate(w, r)
 			return
 		}
+		m.authenticateToken(w, r, token, next)
+	}
+}
+
+// authenticateToken verifies token and calls next with the caller's identity.
+func (m *AuthMiddleware) authenticateToken(w http.ResponseWriter, r *http.Request, token string, next http.HandlerFunc) {
+	claims, err := m.verifier.Verify(token)
+	if err != nil {
+		// Report only expired vs invalid; the cause may carry upstream details.
+		if errors.Is(err, models.ErrTokenExpired) {
+			utils.WriteErrorResponse(w, models.ErrTokenExpired)
+		} else {
+			utils.WriteErrorResponse(w, models.ErrTokenInvalid)
+		}
+		return
+	}
+
+	identity := &Identity{
+		UserID: claims.Sub,
+		Groups: claims.Groups,
+	}
+	for _, group := range identity.Groups {
+		if m.adminGroup != "" && group == m.adminGroup {
+			identity.Admin = true
+		}
+	}
+
+	// Callers may only touch their own data unless they are an admin.
+	if pathUserID := mux.Vars(r)["userId"]; pathUserID != "" && pathUserID != identity.UserID && !identity.Admin {
+		utils.WriteErrorResponse(w, utils.NewHTTPError(http.StatusForbidden, "Access to this user's data is forbidden"))
+		return
+	}
+
+	m.ensureProfile(identity.UserID)
+	next(w, r.WithContext(WithIdentity(r.Context(), identity)))
+}
+
+// CognitoVerifier validates tokens by calling Cognito's GetUser API. It costs a
+// network round trip per request, so it is only used when the user pool's JWKS
+// cannot be used (e.g. COGNITO_USER_POOL_ID is not configured).
+type CognitoVerifier struct {
+	cognito cognitoidentityprovideriface.CognitoIdentityProviderAPI
+}
+
+func NewCognitoVerifier(cognito cognitoidentityprovideriface.CognitoIdentityProviderAPI) *CognitoVerifier {
+	return &CognitoVerifier{
+		cognito: cognito,
+	}
+}
+
+func (v *CognitoVerifier) Verify(token string) (*TokenClaims, error) {
+	user, err := v.cognito.GetUser(&cognitoidentityprovider.GetUserInput{
+		AccessToken: aws.String(token),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrTokenInvalid, err)
+	}
+
+	// Group membership is not returned by GetUser, so it is read from the
+	// token payload; Cognito has already validated the token at this point.
+	claims := &TokenClaims{}
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		decodeSegment(parts[1], claims)
+	}
 
-		next(w, r)
+	claims.Sub = ""
+	for _, attr := range user.UserAttributes {
+		if aws.StringValue(attr.Name) == "sub" {
+			claims.Sub = aws.StringValue(attr.Value)
+			break
+		}
+	}
+	if claims.Sub == "" {
+		return nil, fmt.Errorf("%w: user has no sub attribute", models.ErrTokenInvalid)
 	}
+
+	return claims, nil
 }
@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider/cognitoidentityprovideriface"
+	"github.com/gorilla/mux"
+)
+
+// mockCognito implements the GetUser call used by AuthMiddleware.
+type mockCognito struct {
+	cognitoidentityprovideriface.CognitoIdentityProviderAPI
+	sub string
+	err error
+}
+
+func (m *mockCognito) GetUser(*cognitoidentityprovider.GetUserInput) (*cognitoidentityprovider.GetUserOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &cognitoidentityprovider.GetUserOutput{
+		UserAttributes: []*cognitoidentityprovider.AttributeType{
+			{Name: aws.String("email"), Value: aws.String("user@example.com")},
+			{Name: aws.String("sub"), Value: aws.String(m.sub)},
+		},
+	}, nil
+}
+
+// fakeToken builds an unsigned JWT carrying the given claims.
+func fakeToken(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	return "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+// stubVerifier returns fixed claims for any token.
+type stubVerifier struct {
+	claims *TokenClaims
+	err    error
+}
+
+func (s *stubVerifier) Verify(string) (*TokenClaims, error) {
+	return s.claims, s.err
+}
+
+// serveAuthenticated routes a request for /workouts/{userId} through the middleware
+// and returns the recorder plus the identity seen by the handler.
+func serveAuthenticated(m *AuthMiddleware, path, token string) (*httptest.ResponseRecorder, *Identity) {
+	var seen *Identity
+	r := mux.NewRouter()
+	r.HandleFunc("/workouts/{userId}", m.Authenticate(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = IdentityFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr, seen
+}
+
+func TestAuthenticate_MatchingUser(t *testing.T) {
+	m := NewAuthMiddleware(&stubVerifier{claims: &TokenClaims{Sub: "user-1"}}, "admin")
+
+	rr, identity := serveAuthenticated(m, "/workouts/user-1", "token")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	if identity == nil || identity.UserID != "user-1" {
+		t.Errorf("identity = %+v, want UserID user-1", identity)
+	}
+	if identity != nil && identity.Admin {
+		t.Error("expected non-admin identity")
+	}
+}
+
+func TestAuthenticate_OtherUserForbidden(t *testing.T) {
+	m := NewAuthMiddleware(&stubVerifier{claims: &TokenClaims{Sub: "user-1"}}, "admin")
+
+	rr, identity := serveAuthenticated(m, "/workouts/user-2", "token")
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+	if code := problemCode(t, rr); code != "forbidden" {
+		t.Errorf("code = %q, want forbidden", code)
+	}
+	if identity != nil {
+		t.Error("handler should not run for a forbidden request")
+	}
+}
+
+func TestAuthenticate_AdminMayAccessOtherUser(t *testing.T) {
+	m := NewAuthMiddleware(&stubVerifier{claims: &TokenClaims{Sub: "admin-1", Groups: []string{"admin"}}}, "admin")
+
+	rr, identity := serveAuthenticated(m, "/workouts/user-2", "token")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	if identity == nil || !identity.Admin {
+		t.Errorf("identity = %+v, want admin", identity)
+	}
+}
+
+func TestAuthenticate_AdminGroupDisabled(t *testing.T) {
+	m := NewAuthMiddleware(&stubVerifier{claims: &TokenClaims{Sub: "admin-1", Groups: []string{"admin"}}}, "")
+
+	rr, _ := serveAuthenticated(m, "/workouts/user-2", "token")
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+}
+
+func TestAuthenticate_InvalidToken(t *testing.T) {
+	m := NewAuthMiddleware(&stubVerifier{err: models.ErrTokenInvalid}, "admin")
+
+	rr, _ := serveAuthenticated(m, "/workouts/user-1", "bad-token")
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+	if code := problemCode(t, rr); code != "token_invalid" {
+		t.Errorf("code = %q, want token_invalid", code)
+	}
+}
+
+func TestAuthenticate_ExpiredToken(t *testing.T) {
+	m := NewAuthMiddleware(&stubVerifier{err: fmt.Errorf("%w: exp in the past", models.ErrTokenExpired)}, "admin")
+
+	rr, _ := serveAuthenticated(m, "/workouts/user-1", "old-token")
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+	if code := problemCode(t, rr); code != "token_expired" {
+		t.Errorf("code = %q, want token_expired", code)
+	}
+}
+
+// problemCode checks rr is a problem+json response and returns its code.
+func problemCode(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	if ct := rr.Header().Get("Content-Type"); ct != utils.ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, utils.ProblemContentType)
+	}
+	var problem utils.Problem
+	if err := json.NewDecoder(rr.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	return problem.Code
+}
+
+func TestAuthenticate_MissingHeader(t *testing.T) {
+	m := NewAuthMiddleware(&stubVerifier{claims: &TokenClaims{Sub: "user-1"}}, "admin")
+
+	rr, _ := serveAuthenticated(m, "/workouts/user-1", "")
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+}
+
+// CognitoVerifier
+
+func TestCognitoVerifier_ReadsSubAndGroups(t *testing.T) {
+	v := NewCognitoVerifier(&mockCognito{sub: "user-1"})
+	token := fakeToken(t, map[string]interface{}{"sub": "ignored", "cognito:groups": []string{"admin"}})
+
+	claims, err := v.Verify(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Sub != "user-1" {
+		t.Errorf("Sub = %q, want user-1 from GetUser", claims.Sub)
+	}
+	if len(claims.Groups) != 1 || claims.Groups[0] != "admin" {
+		t.Errorf("Groups = %v, want [admin]", claims.Groups)
+	}
+}
+
+func TestCognitoVerifier_GetUserError(t *testing.T) {
+	v := NewCognitoVerifier(&mockCognito{err: errors.New("NotAuthorizedException")})
+
+	_, err := v.Verify("bad-token")
+	if !errors.Is(err, models.ErrTokenInvalid) {
+		t.Errorf("err = %v, want ErrTokenInvalid", err)
+	}
+}
+
+type countingEnsurer struct {
+	calls map[string]int
+	err   error
+}
+
+func (c *countingEnsurer) EnsureProfile(userID string) error {
+	c.calls[userID]++
+	return c.err
+}
+
+func TestAuthenticate_EnsuresProfileOnce(t *testing.T) {
+	profiles := &countingEnsurer{calls: map[string]int{}}
+	m := NewAuthMiddleware(&stubVerifier{claims: &TokenClaims{Sub: "user-1"}}, "admin").WithProfiles(profiles)
+
+	for i := 0; i < 3; i++ {
+		if rr, _ := serveAuthenticated(m, "/workouts/user-1", "token"); rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rr.Code)
+		}
+	}
+	if profiles.calls["user-1"] != 1 {
+		t.Errorf("EnsureProfile called %d times, want 1", profiles.calls["user-1"])
+	}
+}
+
+func TestAuthenticate_ProfileFailureDoesNotFailRequest(t *testing.T) {
+	profiles := &countingEnsurer{calls: map[string]int{}, err: errors.New("db down")}
+	m := NewAuthMiddleware(&stubVerifier{claims: &TokenClaims{Sub: "user-1"}}, "admin").WithProfiles(profiles)
+
+	for i := 0; i < 2; i++ {
+		if rr, _ := serveAuthenticated(m, "/workouts/user-1", "token"); rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rr.Code)
+		}
+	}
+	if profiles.calls["user-1"] != 2 {
+		t.Errorf("EnsureProfile called %d times, want a retry on each request", profiles.calls["user-1"])
+	}
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// KeySource supplies the JSON Web Key Set used to verify token signatures.
+// Implementations fetch the raw key set; caching and rotation are handled by KeyCache.
+type KeySource interface {
+	Keys() (map[string]*rsa.PublicKey, error)
+}
+
+// URLKeySource fetches a JWKS document over HTTP, e.g. from Cognito's
+// https://cognito-idp.{region}.amazonaws.com/{userPoolId}/.well-known/jwks.json.
+type URLKeySource struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewURLKeySource(url string) *URLKeySource {
+	return &URLKeySource{
+		URL:    url,
+		Client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (s *URLKeySource) Keys() (map[string]*rsa.PublicKey, error) {
+	resp, err := s.Client.Get(s.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	return parseJWKS(body)
+}
+
+// FileKeySource reads a JWKS document from a local file.
+type FileKeySource struct {
+	Path string
+}
+
+func NewFileKeySource(path string) *FileKeySource {
+	return &FileKeySource{Path: path}
+}
+
+func (s *FileKeySource) Keys() (map[string]*rsa.PublicKey, error) {
+	body, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	return parseJWKS(body)
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKS decodes the RSA signing keys in a JWKS document, indexed by key ID.
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus for key %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent for key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// KeyCache caches the keys from a KeySource. Keys are refreshed once the TTL
+// expires, or early when a token references an unknown key ID (Cognito key
+// rotation). Early refreshes are rate limited so forged key IDs cannot be used
+// to hammer the source.
+type KeyCache struct {
+	source     KeySource
+	ttl        time.Duration
+	minRefresh time.Duration
+	now        func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time // last successful fetch
+	checkedAt time.Time // last fetch attempt
+}
+
+func NewKeyCache(source KeySource, ttl time.Duration) *KeyCache {
+	return &KeyCache{
+		source:     source,
+		ttl:        ttl,
+		minRefresh: time.Minute,
+		now:        time.Now,
+	}
+}
+
+// Key returns the public key with the given key ID.
+func (c *KeyCache) Key(kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	_, known := c.keys[kid]
+	stale := now.Sub(c.fetchedAt) >= c.ttl
+	if c.keys == nil || ((stale || !known) && now.Sub(c.checkedAt) >= c.minRefresh) {
+		c.checkedAt = now
+		keys, err := c.source.Keys()
+		if err != nil && c.keys == nil {
+			return nil, err
+		}
+		// On a failed refresh keep serving the previous key set.
+		if err == nil {
+			c.keys = keys
+			c.fetchedAt = now
+		}
+	}
+
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
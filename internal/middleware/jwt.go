@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gym-tracker-api/internal/models"
+)
+
+// TokenClaims are the access token claims the API relies on.
+type TokenClaims struct {
+	Sub      string   `json:"sub"`
+	Groups   []string `json:"cognito:groups"`
+	Issuer   string   `json:"iss"`
+	ClientID string   `json:"client_id"`
+	TokenUse string   `json:"token_use"`
+	Expiry   int64    `json:"exp"`
+	IssuedAt int64    `json:"iat"`
+}
+
+// TokenVerifier validates an access token and returns its claims.
+type TokenVerifier interface {
+	Verify(token string) (*TokenClaims, error)
+}
+
+// JWTVerifier validates Cognito RS256 access tokens offline against a cached JWKS.
+type JWTVerifier struct {
+	keys     *KeyCache
+	issuer   string
+	clientID string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+func NewJWTVerifier(source KeySource, issuer, clientID string) *JWTVerifier {
+	return &JWTVerifier{
+		keys:     NewKeyCache(source, time.Hour),
+		issuer:   issuer,
+		clientID: clientID,
+		leeway:   30 * time.Second,
+		now:      time.Now,
+	}
+}
+
+// CognitoIssuer returns the token issuer for a Cognito user pool.
+func CognitoIssuer(region, userPoolID string) string {
+	return fmt.Sprintf("https://cognito-idp.%s.amazonaws.com/%s", region, userPoolID)
+}
+
+// NewCognitoJWTVerifier creates a verifier that fetches keys from the user pool's JWKS endpoint.
+func NewCognitoJWTVerifier(region, userPoolID, clientID string) *JWTVerifier {
+	issuer := CognitoIssuer(region, userPoolID)
+	return NewJWTVerifier(NewURLKeySource(issuer+"/.well-known/jwks.json"), issuer, clientID)
+}
+
+func (v *JWTVerifier) Verify(token string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", models.ErrTokenInvalid)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: bad header: %v", models.ErrTokenInvalid, err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", models.ErrTokenInvalid, header.Alg)
+	}
+
+	key, err := v.keys.Key(header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrTokenInvalid, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad signature encoding", models.ErrTokenInvalid)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: signature verification failed", models.ErrTokenInvalid)
+	}
+
+	var claims TokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: bad claims: %v", models.ErrTokenInvalid, err)
+	}
+	if err := v.validateClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+func (v *JWTVerifier) validateClaims(claims *TokenClaims) error {
+	if claims.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", models.ErrTokenInvalid, claims.Issuer)
+	}
+	if claims.TokenUse != "access" {
+		return fmt.Errorf("%w: token_use must be access, got %q", models.ErrTokenInvalid, claims.TokenUse)
+	}
+	if claims.ClientID != v.clientID {
+		return fmt.Errorf("%w: unexpected client_id %q", models.ErrTokenInvalid, claims.ClientID)
+	}
+	if claims.Sub == "" {
+		return fmt.Errorf("%w: missing sub", models.ErrTokenInvalid)
+	}
+	if claims.Expiry == 0 || v.now().After(time.Unix(claims.Expiry, 0).Add(v.leeway)) {
+		return models.ErrTokenExpired
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,250 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/models"
+)
+
+const (
+	testIssuer   = "https://cognito-idp.us-east-1.amazonaws.com/us-east-1_test"
+	testClientID = "client-123"
+)
+
+type signingKey struct {
+	kid string
+	key *rsa.PrivateKey
+}
+
+func newSigningKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return signingKey{kid: kid, key: key}
+}
+
+func jwksJSON(keys ...signingKey) []byte {
+	type jwk struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kid: k.kid,
+			Kty: "RSA",
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(k.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.key.E)).Bytes()),
+		})
+	}
+	data, _ := json.Marshal(set)
+	return data
+}
+
+func signToken(t *testing.T, k signingKey, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": k.kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, k.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":            "user-1",
+		"iss":            testIssuer,
+		"client_id":      testClientID,
+		"token_use":      "access",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"cognito:groups": []string{"admin"},
+	}
+}
+
+// jwksServer serves whatever key set is currently configured and counts fetches.
+type jwksServer struct {
+	mu      sync.Mutex
+	body    []byte
+	fetches int
+}
+
+func (s *jwksServer) set(keys ...signingKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body = jwksJSON(keys...)
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches++
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(s.body)
+}
+
+func newTestVerifier(t *testing.T, keys ...signingKey) (*JWTVerifier, *jwksServer) {
+	t.Helper()
+	jwks := &jwksServer{}
+	jwks.set(keys...)
+	srv := httptest.NewServer(jwks)
+	t.Cleanup(srv.Close)
+	return NewJWTVerifier(NewURLKeySource(srv.URL), testIssuer, testClientID), jwks
+}
+
+func TestJWTVerifier_ValidToken(t *testing.T) {
+	key := newSigningKey(t, "key-1")
+	v, _ := newTestVerifier(t, key)
+
+	claims, err := v.Verify(signToken(t, key, validClaims()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Sub != "user-1" {
+		t.Errorf("Sub = %q, want user-1", claims.Sub)
+	}
+	if len(claims.Groups) != 1 || claims.Groups[0] != "admin" {
+		t.Errorf("Groups = %v, want [admin]", claims.Groups)
+	}
+}
+
+func TestJWTVerifier_RejectsBadClaims(t *testing.T) {
+	key := newSigningKey(t, "key-1")
+	v, _ := newTestVerifier(t, key)
+
+	tests := []struct {
+		name  string
+		claim string
+		value interface{}
+		want  error
+	}{
+		{"wrong issuer", "iss", "https://evil.example.com", models.ErrTokenInvalid},
+		{"id token", "token_use", "id", models.ErrTokenInvalid},
+		{"wrong client", "client_id", "other-client", models.ErrTokenInvalid},
+		{"missing sub", "sub", "", models.ErrTokenInvalid},
+		{"expired", "exp", time.Now().Add(-time.Hour).Unix(), models.ErrTokenExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			claims[tt.claim] = tt.value
+
+			_, err := v.Verify(signToken(t, key, claims))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWTVerifier_RejectsForeignSignature(t *testing.T) {
+	trusted := newSigningKey(t, "key-1")
+	attacker := newSigningKey(t, "key-1")
+	v, _ := newTestVerifier(t, trusted)
+
+	_, err := v.Verify(signToken(t, attacker, validClaims()))
+	if !errors.Is(err, models.ErrTokenInvalid) {
+		t.Errorf("err = %v, want ErrTokenInvalid", err)
+	}
+}
+
+func TestJWTVerifier_RejectsNonRS256(t *testing.T) {
+	key := newSigningKey(t, "key-1")
+	v, _ := newTestVerifier(t, key)
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"key-1"}`))
+	payload, _ := json.Marshal(validClaims())
+	token := header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+
+	if _, err := v.Verify(token); !errors.Is(err, models.ErrTokenInvalid) {
+		t.Errorf("err = %v, want ErrTokenInvalid", err)
+	}
+}
+
+func TestJWTVerifier_CachesKeys(t *testing.T) {
+	key := newSigningKey(t, "key-1")
+	v, jwks := newTestVerifier(t, key)
+
+	for i := 0; i < 3; i++ {
+		if _, err := v.Verify(signToken(t, key, validClaims())); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if jwks.fetches != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", jwks.fetches)
+	}
+}
+
+func TestJWTVerifier_KeyRotation(t *testing.T) {
+	oldKey := newSigningKey(t, "key-1")
+	newKey := newSigningKey(t, "key-2")
+	v, jwks := newTestVerifier(t, oldKey)
+
+	now := time.Now()
+	v.keys.now = func() time.Time { return now }
+
+	if _, err := v.Verify(signToken(t, oldKey, validClaims())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Cognito rotates in a new key. Within the refresh rate limit the unknown
+	// kid is rejected without refetching.
+	jwks.set(oldKey, newKey)
+	if _, err := v.Verify(signToken(t, newKey, validClaims())); err == nil {
+		t.Fatal("expected unknown kid to be rejected within the refresh interval")
+	}
+	if jwks.fetches != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", jwks.fetches)
+	}
+
+	// After the interval an unknown kid triggers a refresh.
+	now = now.Add(2 * time.Minute)
+	if _, err := v.Verify(signToken(t, newKey, validClaims())); err != nil {
+		t.Fatalf("unexpected error after rotation: %v", err)
+	}
+	if jwks.fetches != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", jwks.fetches)
+	}
+}
+
+func TestFileKeySource(t *testing.T) {
+	key := newSigningKey(t, "key-1")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwksJSON(key), 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	v := NewJWTVerifier(NewFileKeySource(path), testIssuer, testClientID)
+	if _, err := v.Verify(signToken(t, key, validClaims())); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gym-tracker-api/internal/utils"
+)
+
+func serveWithRequestID(header string) (*httptest.ResponseRecorder, string) {
+	var seen string
+	h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set(utils.RequestIDHeader, header)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr, seen
+}
+
+func TestRequestID_ReusesClientID(t *testing.T) {
+	rr, seen := serveWithRequestID("client-abc-123")
+	if seen != "client-abc-123" || rr.Header().Get(utils.RequestIDHeader) != "client-abc-123" {
+		t.Errorf("context %q, header %q; want client-abc-123", seen, rr.Header().Get(utils.RequestIDHeader))
+	}
+}
+
+func TestRequestID_GeneratesWhenMissingOrInvalid(t *testing.T) {
+	for _, header := range []string{"", "has spaces\r\nX-Evil: 1", strings.Repeat("a", maxRequestIDLength+1)} {
+		rr, seen := serveWithRequestID(header)
+		if seen == "" || seen == header {
+			t.Errorf("header %q: context ID = %q, want a generated ID", header, seen)
+		}
+		if rr.Header().Get(utils.RequestIDHeader) != seen {
+			t.Errorf("header %q: response ID %q != context ID %q", header, rr.Header().Get(utils.RequestIDHeader), seen)
+		}
+	}
+}
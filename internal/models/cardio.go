@@ -0,0 +1,135 @@
+package models
+
+import "strings"
+
+// Distance units. Calories are not a distance, but machines such as the ski
+// erg record work in calories in the same field.
+const (
+	DistanceKm       = "km"
+	DistanceMiles    = "mi"
+	DistanceMeters   = "m"
+	DistanceCalories = "cal"
+)
+
+const metersPerMile = 1609.344
+
+// NormalizeDistanceUnit maps the spellings clients send to one of the
+// Distance constants. Unknown units, including the empty string, return "".
+func NormalizeDistanceUnit(unit string) string {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "km", "kms", "kilometer", "kilometers", "kilometre", "kilometres":
+		return DistanceKm
+	case "mi", "mile", "miles":
+		return DistanceMiles
+	case "m", "meter", "meters", "metre", "metres":
+		return DistanceMeters
+	case "cal", "cals", "calorie", "calories", "kcal":
+		return DistanceCalories
+	}
+	return ""
+}
+
+// DistanceInMeters converts a distance to metres. It reports false for
+// calories and unknown units.
+func DistanceInMeters(value float64, unit string) (float64, bool) {
+	switch NormalizeDistanceUnit(unit) {
+	case DistanceKm:
+		return value * 1000, true
+	case DistanceMiles:
+		return value * metersPerMile, true
+	case DistanceMeters:
+		return value, true
+	}
+	return 0, false
+}
+
+// MetersIn converts metres into unit, which must be km, mi or m.
+func MetersIn(meters float64, unit string) float64 {
+	switch NormalizeDistanceUnit(unit) {
+	case DistanceMiles:
+		return meters / metersPerMile
+	case DistanceMeters:
+		return meters
+	}
+	return meters / 1000
+}
+
+// MachineValue is a machine-specific performance figure, such as a rower's
+// split or a ski erg's watts.
+type MachineValue struct {
+	Metric string  `json:"metric"`
+	Unit   string  `json:"unit"`
+	Value  float64 `json:"value"`
+}
+
+// IntervalComparison compares an interval session with the last earlier
+// session of the same structure: the same number of rounds over the same
+// per-round distance.
+type IntervalComparison struct {
+	PreviousWorkoutID string  `json:"previousWorkoutId"`
+	PreviousDate      string  `json:"previousDate"`
+	AverageTimeDelta  float64 `json:"averageTimeDelta"` // seconds per round; negative is faster
+	Improved          bool    `json:"improved"`
+}
+
+// CardioSession summarises one workout's rounds of a cardio exercise. Pace
+// is seconds per Unit and Speed is Unit per hour; both are omitted when the
+// rounds were measured in calories.
+type CardioSession struct {
+	WorkoutID     string              `json:"workoutId"`
+	Date          string              `json:"date"`
+	Rounds        int                 `json:"rounds"`
+	Distance      float64             `json:"distance"`
+	DistanceUnit  string              `json:"distanceUnit"`
+	Time          int                 `json:"time"`
+	BestRoundTime int                 `json:"bestRoundTime,omitempty"`
+	Pace          float64             `json:"pace,omitempty"`
+	Speed         float64             `json:"speed,omitempty"`
+	RPM           float64             `json:"rpm,omitempty"`
+	Machine       *MachineValue       `json:"machine,omitempty"`
+	Intervals     *IntervalComparison `json:"intervals,omitempty"`
+}
+
+// CardioStats is the analysed history of one cardio exercise name, oldest
+// session first. RPMTrend buckets the average RPM of each session.
+type CardioStats struct {
+	Name     string          `json:"name"`
+	Unit     string          `json:"unit"`
+	Bucket   string          `json:"bucket"`
+	Sessions []CardioSession `json:"sessions"`
+	RPMTrend []StatsPoint    `json:"rpmTrend"`
+}
+
+// CardioQuery selects how cardio history is reported. Unit is km or mi (km
+// by default) and Bucket groups the RPM trend (week by default).
+type CardioQuery struct {
+	Unit   string
+	Bucket string
+	From   string
+	To     string
+}
+
+// Normalize fills in defaults and canonicalises Unit.
+func (q *CardioQuery) Normalize() {
+	if q.Unit == "" {
+		q.Unit = DistanceKm
+	} else if unit := NormalizeDistanceUnit(q.Unit); unit != "" {
+		q.Unit = unit
+	}
+	if q.Bucket == "" {
+		q.Bucket = BucketWeek
+	}
+}
+
+func (q *CardioQuery) Validate() error {
+	verr := &ValidationError{Kind: ErrInvalidStatsQuery}
+	if q.Unit != DistanceKm && q.Unit != DistanceMiles {
+		verr.add("unit", "must be km or mi")
+	}
+	switch q.Bucket {
+	case BucketDay, BucketWeek, BucketMonth:
+	default:
+		verr.add("bucket", "must be one of day, week, month")
+	}
+	return verr.err()
+}
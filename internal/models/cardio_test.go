@@ -0,0 +1,33 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeDistanceUnit(t *testing.T) {
+	tests := map[string]string{
+		"KM":       DistanceKm,
+		"Miles":    DistanceMiles,
+		" metres ": DistanceMeters,
+		"kcal":     DistanceCalories,
+		"yards":    "",
+	}
+	for in, want := range tests {
+		if got := NormalizeDistanceUnit(in); got != want {
+			t.Errorf("NormalizeDistanceUnit(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDistanceInMeters(t *testing.T) {
+	if m, ok := DistanceInMeters(2, "mile"); !ok || math.Abs(m-3218.688) > 1e-9 {
+		t.Errorf("2 miles = %v m (%v), want 3218.688", m, ok)
+	}
+	if _, ok := DistanceInMeters(20, "cal"); ok {
+		t.Error("calories should not convert to metres")
+	}
+	if got := MetersIn(1609.344, DistanceMiles); got != 1 {
+		t.Errorf("MetersIn(1609.344, mi) = %v, want 1", got)
+	}
+}
@@ -4,14 +4,35 @@ import "errors"
 
 // Error definitions for the application
 var (
-	ErrWorkoutNotFound       = errors.New("workout not found")
-	ErrExerciseNotFound      = errors.New("exercise not found")
-	ErrWorkoutAlreadyExists  = errors.New("workout already exists")
-	ErrExerciseAlreadyExists = errors.New("exercise already exists")
-	ErrInvalidWorkout        = errors.New("invalid workout data")
-	ErrInvalidExercise       = errors.New("invalid exercise data")
-	ErrUnauthorized          = errors.New("unauthorized access")
-	ErrInternalServerError   = errors.New("internal server error")
+	ErrWorkoutNotFound          = errors.New("workout not found")
+	ErrExerciseNotFound         = errors.New("exercise not found")
+	ErrWorkoutAlreadyExists     = errors.New("workout already exists")
+	ErrExerciseAlreadyExists    = errors.New("exercise already exists")
+	ErrInvalidWorkout           = errors.New("invalid workout data")
+	ErrInvalidExercise          = errors.New("invalid exercise data")
+	ErrUnauthorized             = errors.New("unauthorized access")
+	ErrInternalServerError      = errors.New("internal server error")
+	ErrInvalidCursor            = errors.New("invalid pagination cursor")
+	ErrTemplateNotFound         = errors.New("template not found")
+	ErrTemplateAlreadyExists    = errors.New("template already exists")
+	ErrInvalidTemplate          = errors.New("invalid template data")
+	ErrProgramNotFound          = errors.New("program not found")
+	ErrProgramAlreadyExists     = errors.New("program already exists")
+	ErrInvalidProgram           = errors.New("invalid program data")
+	ErrProgramConflict          = errors.New("program was changed concurrently")
+	ErrNotEnrolled              = errors.New("not enrolled in program")
+	ErrNoSessionScheduled       = errors.New("no session scheduled")
+	ErrSessionAlreadyStarted    = errors.New("session already started")
+	ErrRecordAlreadyExists      = errors.New("record already exists")
+	ErrInvalidStatsQuery        = errors.New("invalid stats query")
+	ErrProfileNotFound          = errors.New("profile not found")
+	ErrProfileAlreadyExists     = errors.New("profile already exists")
+	ErrInvalidProfile           = errors.New("invalid profile data")
+	ErrMeasurementNotFound      = errors.New("measurement not found")
+	ErrMeasurementAlreadyExists = errors.New("measurement already exists")
+	ErrInvalidMeasurement       = errors.New("invalid measurement data")
+	ErrWorkoutGroupNotFound     = errors.New("workout group not found")
+	ErrInvalidTransition        = errors.New("invalid workout status transition")
 )
 // ErrUserNotFound is returned when a user is not found in the system
 var ErrUserNotFound = errors.New("user not found")
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Workout event types.
+const (
+	// EventWorkout carries the workout as it stands; it opens every stream.
+	EventWorkout        = "workout"
+	EventWorkoutUpdated = "workout_updated" // renamed, reordered or regrouped
+	EventWorkoutDeleted = "workout_deleted"
+	EventExerciseAdded  = "exercise_added"
+	// EventExerciseRemoved is published when an exercise leaves a workout,
+	// and without a WorkoutID when the exercise itself is deleted.
+	EventExerciseRemoved = "exercise_removed"
+	EventExerciseUpdated = "exercise_updated"
+	// EventSetLogged follows EventExerciseUpdated when the update added a
+	// set or completed one.
+	EventSetLogged     = "set_logged"
+	EventStatusChanged = "status_changed"
+)
+
+// WorkoutEvent is a change to a workout or to one of its exercises, pushed to
+// clients following the workout live. Exercises do not know which workouts
+// hold them, so exercise updates carry no WorkoutID.
+type WorkoutEvent struct {
+	Type       string    `json:"type"`
+	UserID     string    `json:"-"`
+	WorkoutID  string    `json:"workoutId,omitempty"`
+	ExerciseID string    `json:"exerciseId,omitempty"`
+	Status     string    `json:"status,omitempty"` // the new status, for status_changed
+	At         time.Time `json:"at"`
+	// The changed workout or exercise, filled in when the event is streamed.
+	Workout  *Workout  `json:"workout,omitempty"`
+	Exercise *Exercise `json:"exercise,omitempty"`
+}
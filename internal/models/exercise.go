@@ -1,8 +1,10 @@
 package models
 
 import (
-	"errors"
-	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Valid ExerciseType values.
@@ -20,38 +22,125 @@ var validExerciseTypes = map[string]bool{
 	ExerciseTypeOther:      true,
 }
 
+// IsValidExerciseType reports whether t is one of the ExerciseType constants.
+func IsValidExerciseType(t string) bool {
+	return validExerciseTypes[t]
+}
+
+// Valid SetType values. An empty SetType is a working set.
+const (
+	SetTypeWarmup  = "warmup"
+	SetTypeWorking = "working"
+	SetTypeDrop    = "drop"
+	SetTypeFailure = "failure"
+)
+
+var validSetTypes = map[string]bool{
+	SetTypeWarmup:  true,
+	SetTypeWorking: true,
+	SetTypeDrop:    true,
+	SetTypeFailure: true,
+}
+
+// tempoPattern matches eccentric-pause-concentric-pause tempos, either as four
+// digits or X's ("31X0") or as four dash-separated numbers or X's ("3-1-2-0").
+var tempoPattern = regexp.MustCompile(`^(?i:[0-9x]{4}|([0-9]+|x)(-([0-9]+|x)){3})$`)
+
 type WeightItem struct {
-	Weight   float64 `json:"weight"`
-	Unit     string  `json:"unit"`
-	Reps     int     `json:"reps,omitempty"`
-	Duration int     `json:"duration,omitempty"` // seconds; for timed sets (e.g. plank)
+	Weight       float64 `json:"weight"`
+	Unit         string  `json:"unit"`                   // kg or lb once normalized
+	OriginalUnit string  `json:"originalUnit,omitempty"` // as sent, when it differed from Unit
+	Reps         int     `json:"reps,omitempty"`
+	Duration     int     `json:"duration,omitempty"` // seconds; for timed sets (e.g. plank)
+	SetType      string  `json:"setType,omitempty"`  // one of the SetType constants; empty means working
+	// RPE (1-10, in half steps) and RIR (reps in reserve) are alternative
+	// effort ratings; a set carries at most one. RIR is nil when not rated,
+	// since zero means the set went to failure.
+	RPE         float64 `json:"rpe,omitempty"`
+	RIR         *int    `json:"rir,omitempty"`
+	Tempo       string  `json:"tempo,omitempty"`
+	RestSeconds int     `json:"restSeconds,omitempty"` // rest taken after the set
+	Completed   bool    `json:"completed,omitempty"`
+}
+
+// IsWarmup reports whether the set is a warm-up. Warm-ups are excluded from
+// volume and personal records.
+func (s WeightItem) IsWarmup() bool {
+	return s.SetType == SetTypeWarmup
 }
 
 type Exercise struct {
-	ExerciseID   string       `json:"exerciseId" dynamodbav:"ExerciseID" validate:"required"`
-	Name         string       `json:"name"`
-	ExerciseType string       `json:"exerciseType" dynamodbav:"ExerciseType" validate:"required"`
-	Time         int          `json:"time,omitempty"`
-	Distance     float64      `json:"distance,omitempty"`
-	DistanceUnit string       `json:"distanceUnit,omitempty"`
-	Level        float64      `json:"level,omitempty"`
-	Reps         int          `json:"reps,omitempty"`
-	Sets         []WeightItem `json:"sets,omitempty"`
-	RPM          float64      `json:"rpm,omitempty"`
+	ExerciseID   string  `json:"exerciseId" dynamodbav:"ExerciseID" validate:"required"`
+	Name         string  `json:"name"`
+	ExerciseType string  `json:"exerciseType" dynamodbav:"ExerciseType" validate:"required"`
+	Time         int     `json:"time,omitempty"`
+	Distance     float64 `json:"distance,omitempty"`
+	DistanceUnit string  `json:"distanceUnit,omitempty"`
+	// OriginalDistanceUnit is DistanceUnit as sent, when it differed.
+	OriginalDistanceUnit string       `json:"originalDistanceUnit,omitempty"`
+	Level                float64      `json:"level,omitempty"`
+	Reps                 int          `json:"reps,omitempty"`
+	Sets                 []WeightItem `json:"sets,omitempty"`
+	RPM                  float64      `json:"rpm,omitempty"`
+	// NewRecords lists the personal records set by the last create or update.
+	// It is returned to the client but never stored.
+	NewRecords []*PersonalRecord `json:"newRecords,omitempty" dynamodbav:"-"`
+	// CreatedAt is set when the exercise is first stored and kept by updates.
+	CreatedAt time.Time `json:"createdAt"`
+	// DeletedAt is set while the exercise is in the trash.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// NormalizeName folds an exercise name for case- and whitespace-insensitive
+// lookups, so "Bench  Press " and "bench press" share the key "bench press".
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
 }
 
 func (e *Exercise) Validate() error {
+	verr := &ValidationError{Kind: ErrInvalidExercise}
 	if e.ExerciseID == "" {
-		return errors.New("ExerciseID is required")
+		verr.add("exerciseId", "is required")
 	}
-	if e.Name == "" {
-		return errors.New("name is required")
+	if NormalizeName(e.Name) == "" {
+		verr.add("name", "is required")
 	}
 	if e.ExerciseType == "" {
-		return errors.New("ExerciseType is required")
+		verr.add("exerciseType", "is required")
+	} else if !validExerciseTypes[e.ExerciseType] {
+		verr.add("exerciseType", "must be one of weights, cardio, body_weight, other")
+	}
+	for i, set := range e.Sets {
+		set.validate(verr, "sets["+strconv.Itoa(i)+"]")
+	}
+	if e.DistanceUnit != "" && NormalizeDistanceUnit(e.DistanceUnit) == "" {
+		verr.add("distanceUnit", "must be one of km, mi, m, cal")
+	}
+	return verr.err()
+}
+
+func (s WeightItem) validate(verr *ValidationError, prefix string) {
+	if s.Unit != "" && NormalizeWeightUnit(s.Unit) == "" {
+		verr.add(prefix+".unit", "must be kg or lb")
+	}
+	if s.SetType != "" && !validSetTypes[s.SetType] {
+		verr.add(prefix+".setType", "must be one of warmup, working, drop, failure")
+	}
+	if s.RPE != 0 && (s.RPE < 1 || s.RPE > 10 || s.RPE*2 != float64(int(s.RPE*2))) {
+		verr.add(prefix+".rpe", "must be between 1 and 10 in steps of 0.5")
+	}
+	if s.RIR != nil {
+		if *s.RIR < 0 || *s.RIR > 10 {
+			verr.add(prefix+".rir", "must be between 0 and 10")
+		}
+		if s.RPE != 0 {
+			verr.add(prefix+".rir", "cannot be combined with rpe")
+		}
+	}
+	if s.Tempo != "" && !tempoPattern.MatchString(s.Tempo) {
+		verr.add(prefix+".tempo", "must be four phases such as 31X0 or 3-1-2-0")
 	}
-	if !validExerciseTypes[e.ExerciseType] {
-		return fmt.Errorf("invalid exerciseType %q: must be one of weights, cardio, body_weight, other", e.ExerciseType)
+	if s.RestSeconds < 0 || s.RestSeconds > maxRestSeconds {
+		verr.add(prefix+".restSeconds", "must be between 0 and 3600")
 	}
-	return nil
 }
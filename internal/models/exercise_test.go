@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Bench Press", "bench press"},
+		{"bench press", "bench press"},
+		{"  Bench   Press ", "bench press"},
+		{"BENCH\tPRESS", "bench press"},
+		{"Squat", "squat"},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeName(tt.input); got != tt.want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExerciseValidate_FieldErrors(t *testing.T) {
+	err := (&Exercise{Name: "  ", ExerciseType: "yoga"}).Validate()
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want *ValidationError", err)
+	}
+	if !errors.Is(err, ErrInvalidExercise) {
+		t.Errorf("err = %v, want it to wrap ErrInvalidExercise", err)
+	}
+	want := []string{"exerciseId", "name", "exerciseType"}
+	if len(verr.Fields) != len(want) {
+		t.Fatalf("Fields = %+v, want %v", verr.Fields, want)
+	}
+	for i, field := range want {
+		if verr.Fields[i].Field != field {
+			t.Errorf("Fields[%d] = %q, want %q", i, verr.Fields[i].Field, field)
+		}
+	}
+
+	valid := &Exercise{ExerciseID: "ex-1", Name: "Squat", ExerciseType: ExerciseTypeWeights}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid exercise: err = %v", err)
+	}
+}
+
+func TestExerciseValidate_SetDetail(t *testing.T) {
+	zero, eleven := 0, 11
+	tests := []struct {
+		name  string
+		set   WeightItem
+		field string // "" when the set is valid
+	}{
+		{"plain", WeightItem{Weight: 100, Unit: "kg", Reps: 5}, ""},
+		{"full detail", WeightItem{Weight: 100, Unit: "kg", Reps: 5, SetType: SetTypeDrop, RPE: 8.5, Tempo: "3-1-2-0", RestSeconds: 180, Completed: true}, ""},
+		{"rir to failure", WeightItem{Reps: 12, SetType: SetTypeFailure, RIR: &zero, Tempo: "31x0"}, ""},
+		{"unknown set type", WeightItem{SetType: "cluster"}, "sets[0].setType"},
+		{"rpe out of range", WeightItem{RPE: 10.5}, "sets[0].rpe"},
+		{"rpe not a half step", WeightItem{RPE: 7.3}, "sets[0].rpe"},
+		{"rir out of range", WeightItem{RIR: &eleven}, "sets[0].rir"},
+		{"rpe and rir", WeightItem{RPE: 8, RIR: &zero}, "sets[0].rir"},
+		{"bad tempo", WeightItem{Tempo: "slow"}, "sets[0].tempo"},
+		{"three phase tempo", WeightItem{Tempo: "3-1-2"}, "sets[0].tempo"},
+		{"negative rest", WeightItem{RestSeconds: -1}, "sets[0].restSeconds"},
+		{"rest too long", WeightItem{RestSeconds: 3601}, "sets[0].restSeconds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Exercise{ExerciseID: "ex-1", Name: "Squat", ExerciseType: ExerciseTypeWeights, Sets: []WeightItem{tt.set}}
+			err := e.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) || len(verr.Fields) != 1 || verr.Fields[0].Field != tt.field {
+				t.Errorf("Validate = %v, want one error on %s", err, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Length units for circumference measurements.
+const (
+	LengthCm = "cm"
+	LengthIn = "in"
+)
+
+// NormalizeLengthUnit returns LengthCm or LengthIn for the common spellings of
+// each, or "" for anything else.
+func NormalizeLengthUnit(unit string) string {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "cm", "cms", "centimeter", "centimeters", "centimetre", "centimetres":
+		return LengthCm
+	case "in", "inch", "inches":
+		return LengthIn
+	}
+	return ""
+}
+
+// Measurement is one day's body measurements. Any field may be left out, but
+// at least one must be recorded. Circumferences share LengthUnit.
+type Measurement struct {
+	UserID         string    `json:"userId" dynamodbav:"UserID"`
+	MeasurementID  string    `json:"measurementId" dynamodbav:"MeasurementID"`
+	Date           string    `json:"date"` // YYYY-MM-DD
+	Bodyweight     float64   `json:"bodyweight,omitempty"`
+	WeightUnit     string    `json:"weightUnit,omitempty"`
+	BodyFatPercent float64   `json:"bodyFatPercent,omitempty"`
+	Chest          float64   `json:"chest,omitempty"`
+	Waist          float64   `json:"waist,omitempty"`
+	Arms           float64   `json:"arms,omitempty"`
+	Thighs         float64   `json:"thighs,omitempty"`
+	LengthUnit     string    `json:"lengthUnit,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// Normalize canonicalises the units, leaving unknown ones for Validate.
+func (m *Measurement) Normalize() {
+	if u := NormalizeWeightUnit(m.WeightUnit); u != "" {
+		m.WeightUnit = u
+	}
+	if u := NormalizeLengthUnit(m.LengthUnit); u != "" {
+		m.LengthUnit = u
+	}
+}
+
+func (m *Measurement) Validate() error {
+	verr := &ValidationError{Kind: ErrInvalidMeasurement}
+	if m.UserID == "" {
+		verr.add("userId", "is required")
+	}
+	if m.MeasurementID == "" {
+		verr.add("measurementId", "is required")
+	}
+	if _, err := time.Parse(DateLayout, m.Date); err != nil {
+		verr.add("date", "must be YYYY-MM-DD")
+	}
+
+	if m.Bodyweight < 0 {
+		verr.add("bodyweight", "must not be negative")
+	}
+	if m.Bodyweight > 0 && m.WeightUnit != UnitKg && m.WeightUnit != UnitLb {
+		verr.add("weightUnit", "must be kg or lb")
+	}
+	if m.BodyFatPercent < 0 || m.BodyFatPercent >= 100 {
+		verr.add("bodyFatPercent", "must be between 0 and 100")
+	}
+	circumferences := false
+	for _, c := range []struct {
+		field string
+		value float64
+	}{{"chest", m.Chest}, {"waist", m.Waist}, {"arms", m.Arms}, {"thighs", m.Thighs}} {
+		if c.value < 0 {
+			verr.add(c.field, "must not be negative")
+		}
+		circumferences = circumferences || c.value > 0
+	}
+	if circumferences && m.LengthUnit != LengthCm && m.LengthUnit != LengthIn {
+		verr.add("lengthUnit", "must be cm or in")
+	}
+	if m.Bodyweight <= 0 && m.BodyFatPercent <= 0 && !circumferences {
+		verr.add("measurement", "must record bodyweight, bodyFatPercent or a circumference")
+	}
+	return verr.err()
+}
+
+// ConvertUnits converts the bodyweight to units.Weight, in place.
+func (m *Measurement) ConvertUnits(units UnitPreferences) {
+	if m.Bodyweight <= 0 || m.WeightUnit == units.Weight {
+		return
+	}
+	m.Bodyweight = roundTo(ConvertWeight(m.Bodyweight, m.WeightUnit, units.Weight), 2)
+	m.WeightUnit = units.Weight
+}
+
+// BodyweightHistory answers "what did the user weigh on this date" from the
+// measurements that recorded a bodyweight.
+type BodyweightHistory struct {
+	entries []*Measurement // by date, oldest first
+}
+
+// NewBodyweightHistory indexes the measurements that have a bodyweight.
+func NewBodyweightHistory(measurements []*Measurement) *BodyweightHistory {
+	h := &BodyweightHistory{}
+	for _, m := range measurements {
+		if m.Bodyweight > 0 {
+			h.entries = append(h.entries, m)
+		}
+	}
+	sort.SliceStable(h.entries, func(i, j int) bool {
+		return h.entries[i].Date < h.entries[j].Date
+	})
+	return h
+}
+
+// On returns the latest bodyweight recorded on or before date, in unit. It
+// reports false when nothing was recorded by then.
+func (h *BodyweightHistory) On(date, unit string) (float64, bool) {
+	if h == nil {
+		return 0, false
+	}
+	// Timestamps compare by their date part.
+	if len(date) > len(DateLayout) {
+		date = date[:len(DateLayout)]
+	}
+	i := sort.Search(len(h.entries), func(i int) bool {
+		return h.entries[i].Date > date
+	})
+	if i == 0 {
+		return 0, false
+	}
+	m := h.entries[i-1]
+	return ConvertWeight(m.Bodyweight, m.WeightUnit, unit), true
+}
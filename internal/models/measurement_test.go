@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMeasurement_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Measurement
+		ok   bool
+	}{
+		{"bodyweight only", Measurement{Bodyweight: 80, WeightUnit: UnitKg}, true},
+		{"body fat only", Measurement{BodyFatPercent: 15}, true},
+		{"circumference", Measurement{Waist: 32, LengthUnit: LengthIn}, true},
+		{"nothing recorded", Measurement{}, false},
+		{"bodyweight without unit", Measurement{Bodyweight: 80}, false},
+		{"circumference without unit", Measurement{Chest: 100}, false},
+		{"negative circumference", Measurement{Arms: -1, Waist: 80, LengthUnit: LengthCm}, false},
+		{"body fat out of range", Measurement{BodyFatPercent: 100}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.m
+			m.UserID, m.MeasurementID, m.Date = "user-1", "m-1", "2024-03-05"
+			err := m.Validate()
+			if tt.ok && err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidMeasurement) {
+				t.Errorf("Validate = %v, want ErrInvalidMeasurement", err)
+			}
+		})
+	}
+}
+
+func TestMeasurement_ConvertUnits(t *testing.T) {
+	m := &Measurement{Bodyweight: 80, WeightUnit: UnitKg, Waist: 84, LengthUnit: LengthCm}
+	m.ConvertUnits(UnitPreferences{Weight: UnitLb, Distance: DistanceMiles})
+	if m.Bodyweight != 176.37 || m.WeightUnit != UnitLb || m.Waist != 84 || m.LengthUnit != LengthCm {
+		t.Errorf("converted = %+v, want 176.37 lb and circumferences untouched", m)
+	}
+}
+
+func TestBodyweightHistory_On(t *testing.T) {
+	h := NewBodyweightHistory([]*Measurement{
+		{Date: "2024-03-10", Bodyweight: 176.37, WeightUnit: UnitLb},
+		{Date: "2024-03-01", Bodyweight: 82, WeightUnit: UnitKg},
+		{Date: "2024-03-05", Waist: 84, LengthUnit: LengthCm},
+	})
+
+	tests := []struct {
+		date string
+		want float64
+		ok   bool
+	}{
+		{"2024-02-28", 0, false},
+		{"2024-03-01", 82, true},
+		{"2024-03-09T23:00:00Z", 82, true},
+		{"2024-03-10", 80, true},
+		{"2024-12-31", 80, true},
+	}
+	for _, tt := range tests {
+		got, ok := h.On(tt.date, UnitKg)
+		if ok != tt.ok || roundTo(got, 2) != tt.want {
+			t.Errorf("On(%s) = %v, %t, want %v, %t", tt.date, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	var empty *BodyweightHistory
+	if _, ok := empty.On("2024-03-01", UnitKg); ok {
+		t.Error("nil history should report no bodyweight")
+	}
+}
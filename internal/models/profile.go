@@ -0,0 +1,151 @@
+package models
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the timezone database so profile timezones resolve on hosts
+	// without zoneinfo, such as the Lambda runtime.
+	_ "time/tzdata"
+)
+
+// Profile defaults for new users.
+const (
+	DefaultTimezone    = "UTC"
+	DefaultWeekStart   = "monday"
+	DefaultRestSeconds = 90
+
+	maxDisplayName = 100
+	maxRestSeconds = 3600
+)
+
+// BodyweightEntry is the user's bodyweight on a date.
+type BodyweightEntry struct {
+	Date   string  `json:"date"`
+	Weight float64 `json:"weight"`
+	Unit   string  `json:"unit"`
+}
+
+// UserProfile holds a user's settings. Cognito owns identity; the profile only
+// records preferences, keyed by the Cognito subject.
+type UserProfile struct {
+	UserID      string            `json:"userId" dynamodbav:"UserID"`
+	DisplayName string            `json:"displayName"`
+	Bodyweight  []BodyweightEntry `json:"bodyweight"` // oldest first, one entry per date
+	Units       UnitPreferences   `json:"units"`
+	// Timezone is an IANA name such as "Europe/London". It decides which
+	// calendar day "today" is for the user.
+	Timezone string `json:"timezone"`
+	// WeekStart is the lower-case weekday the user's week begins on.
+	WeekStart          string    `json:"weekStart"`
+	DefaultRestSeconds int       `json:"defaultRestSeconds"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+// NewUserProfile returns a profile with default settings.
+func NewUserProfile(userID string) *UserProfile {
+	return &UserProfile{
+		UserID:             userID,
+		Bodyweight:         []BodyweightEntry{},
+		Units:              DefaultUnits,
+		Timezone:           DefaultTimezone,
+		WeekStart:          DefaultWeekStart,
+		DefaultRestSeconds: DefaultRestSeconds,
+	}
+}
+
+// Normalize trims and canonicalises the settings, fills empty units, timezone
+// and week start with their defaults, and orders the bodyweight history.
+func (p *UserProfile) Normalize() {
+	p.DisplayName = strings.TrimSpace(p.DisplayName)
+	if p.Units.Weight == "" {
+		p.Units.Weight = DefaultUnits.Weight
+	}
+	if p.Units.Distance == "" {
+		p.Units.Distance = DefaultUnits.Distance
+	}
+	p.Units.Normalize()
+	p.Timezone = strings.TrimSpace(p.Timezone)
+	if p.Timezone == "" {
+		p.Timezone = DefaultTimezone
+	}
+	p.WeekStart = strings.ToLower(strings.TrimSpace(p.WeekStart))
+	if p.WeekStart == "" {
+		p.WeekStart = DefaultWeekStart
+	}
+	if p.Bodyweight == nil {
+		p.Bodyweight = []BodyweightEntry{}
+	}
+	for i := range p.Bodyweight {
+		if u := NormalizeWeightUnit(p.Bodyweight[i].Unit); u != "" {
+			p.Bodyweight[i].Unit = u
+		}
+	}
+	sort.SliceStable(p.Bodyweight, func(i, j int) bool {
+		return p.Bodyweight[i].Date < p.Bodyweight[j].Date
+	})
+}
+
+func (p *UserProfile) Validate() error {
+	verr := &ValidationError{Kind: ErrInvalidProfile}
+	if p.UserID == "" {
+		verr.add("userId", "is required")
+	}
+	if len(p.DisplayName) > maxDisplayName {
+		verr.add("displayName", "must be at most 100 characters")
+	}
+	p.Units.validate(verr, "units.")
+	if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "" || p.Timezone == "Local" {
+		verr.add("timezone", "must be an IANA timezone such as Europe/London")
+	}
+	if _, ok := weekdays[p.WeekStart]; !ok {
+		verr.add("weekStart", "must be a day of the week, e.g. monday")
+	}
+	if p.DefaultRestSeconds < 0 || p.DefaultRestSeconds > maxRestSeconds {
+		verr.add("defaultRestSeconds", "must be between 0 and 3600")
+	}
+	seen := make(map[string]bool, len(p.Bodyweight))
+	for i, entry := range p.Bodyweight {
+		field := "bodyweight[" + strconv.Itoa(i) + "]."
+		if _, err := time.Parse(DateLayout, entry.Date); err != nil {
+			verr.add(field+"date", "must be YYYY-MM-DD")
+		} else if seen[entry.Date] {
+			verr.add(field+"date", "must not repeat another entry's date")
+		}
+		seen[entry.Date] = true
+		if entry.Weight <= 0 {
+			verr.add(field+"weight", "must be positive")
+		}
+		if entry.Unit != UnitKg && entry.Unit != UnitLb {
+			verr.add(field+"unit", "must be kg or lb")
+		}
+	}
+	return verr.err()
+}
+
+// Location returns the profile's timezone, or UTC if it does not resolve.
+func (p *UserProfile) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Today returns the date it is at now in the profile's timezone.
+func (p *UserProfile) Today(now time.Time) string {
+	return now.In(p.Location()).Format(DateLayout)
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUserProfile_NormalizeFillsDefaults(t *testing.T) {
+	p := &UserProfile{
+		UserID:      "user-1",
+		DisplayName: "  Sam ",
+		WeekStart:   "Sunday",
+		Bodyweight: []BodyweightEntry{
+			{Date: "2024-02-01", Weight: 80, Unit: "kgs"},
+			{Date: "2024-01-01", Weight: 81, Unit: "kg"},
+		},
+	}
+	p.Normalize()
+
+	if p.DisplayName != "Sam" || p.WeekStart != "sunday" || p.Timezone != DefaultTimezone || p.Units != DefaultUnits {
+		t.Errorf("normalized profile = %+v", p)
+	}
+	if p.Bodyweight[0].Date != "2024-01-01" || p.Bodyweight[1].Unit != UnitKg {
+		t.Errorf("bodyweight = %+v, want sorted with canonical units", p.Bodyweight)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestUserProfile_ValidateRejectsBadSettings(t *testing.T) {
+	p := NewUserProfile("user-1")
+	p.Timezone = "Mars/Olympus_Mons"
+	p.WeekStart = "someday"
+	p.DefaultRestSeconds = -1
+	p.Bodyweight = []BodyweightEntry{
+		{Date: "2024-01-01", Weight: 80, Unit: UnitKg},
+		{Date: "2024-01-01", Weight: 0, Unit: "stone"},
+	}
+
+	err := p.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidProfile) {
+		t.Fatalf("Validate() = %v, want an ErrInvalidProfile ValidationError", err)
+	}
+	want := []string{"timezone", "weekStart", "defaultRestSeconds", "bodyweight[1].date", "bodyweight[1].weight", "bodyweight[1].unit"}
+	fields := map[string]bool{}
+	for _, f := range verr.Fields {
+		fields[f.Field] = true
+	}
+	for _, field := range want {
+		if !fields[field] {
+			t.Errorf("expected an error for %s, got %+v", field, verr.Fields)
+		}
+	}
+}
+
+func TestUserProfile_TodayUsesTimezone(t *testing.T) {
+	p := NewUserProfile("user-1")
+	now := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+
+	if got := p.Today(now); got != "2024-03-01" {
+		t.Errorf("UTC today = %s, want 2024-03-01", got)
+	}
+	p.Timezone = "America/Los_Angeles"
+	if got := p.Today(now); got != "2024-02-29" {
+		t.Errorf("Los Angeles today = %s, want 2024-02-29", got)
+	}
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// ProgramDay schedules a template on one day of a program week. Day counts
+// from 1, the weekday of the enrolment start date, to 7.
+type ProgramDay struct {
+	Day        int    `json:"day"`
+	TemplateID string `json:"templateId"`
+}
+
+// ProgramWeek lists the sessions of one week. Deload weeks scale target
+// weights down by Progression.DeloadPercent.
+type ProgramWeek struct {
+	Days   []ProgramDay `json:"days"`
+	Deload bool         `json:"deload,omitempty"`
+}
+
+// Progression adjusts template target weights as a program advances.
+// WeeklyIncrement is added once per week after the first, in each
+// prescription's own unit; in deload weeks the progressed weight is reduced to
+// DeloadPercent of itself (e.g. 60 for 60%).
+type Progression struct {
+	WeeklyIncrement float64 `json:"weeklyIncrement,omitempty"`
+	DeloadPercent   float64 `json:"deloadPercent,omitempty"`
+}
+
+// Apply returns p with its target weight progressed for week (1-based).
+// Prescriptions without a target weight are returned unchanged.
+func (g Progression) Apply(p ExercisePrescription, week int, deload bool) ExercisePrescription {
+	if p.TargetWeight <= 0 {
+		return p
+	}
+	weight := p.TargetWeight + g.WeeklyIncrement*float64(week-1)
+	if deload && g.DeloadPercent > 0 {
+		weight = weight * g.DeloadPercent / 100
+	}
+	p.TargetWeight = math.Round(weight*100) / 100
+	return p
+}
+
+// ProgramSession records a scheduled session that was started as a workout.
+type ProgramSession struct {
+	Week      int    `json:"week"`
+	Day       int    `json:"day"`
+	Date      string `json:"date"`
+	WorkoutID string `json:"workoutId"`
+}
+
+// Enrollment is the user's run through a program, starting on StartDate.
+type Enrollment struct {
+	StartDate  string           `json:"startDate"`
+	EnrolledAt time.Time        `json:"enrolledAt"`
+	Sessions   []ProgramSession `json:"sessions"`
+}
+
+// Program is a multi-week training block. Week i of Weeks is the i+1th week
+// after the enrolment start date. Version counts the updates stored so far;
+// repositories refuse an update made from an older copy.
+type Program struct {
+	UserID      string        `json:"userId" dynamodbav:"UserID"`
+	ProgramID   string        `json:"programId" dynamodbav:"ProgramID"`
+	Name        string        `json:"name"`
+	Weeks       []ProgramWeek `json:"weeks"`
+	Progression Progression   `json:"progression"`
+	Enrollment  *Enrollment   `json:"enrollment,omitempty"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	Version     int           `json:"-" dynamodbav:"version"`
+}
+
+// Session returns the session started for week and day, if any.
+func (p *Program) Session(week, day int) (ProgramSession, bool) {
+	if p.Enrollment != nil {
+		for _, s := range p.Enrollment.Sessions {
+			if s.Week == week && s.Day == day {
+				return s, true
+			}
+		}
+	}
+	return ProgramSession{}, false
+}
+
+// SessionCount is the number of scheduled sessions across all weeks.
+func (p *Program) SessionCount() int {
+	n := 0
+	for _, w := range p.Weeks {
+		n += len(w.Days)
+	}
+	return n
+}
+
+func (p *Program) Validate() error {
+	verr := &ValidationError{Kind: ErrInvalidProgram}
+	if p.UserID == "" {
+		verr.add("userId", "is required")
+	}
+	if p.ProgramID == "" {
+		verr.add("programId", "is required")
+	}
+	if NormalizeName(p.Name) == "" {
+		verr.add("name", "is required")
+	}
+	if len(p.Weeks) == 0 {
+		verr.add("weeks", "must contain at least one week")
+	}
+	for i, week := range p.Weeks {
+		seen := make(map[int]bool, len(week.Days))
+		for j, day := range week.Days {
+			field := "weeks[" + strconv.Itoa(i) + "].days[" + strconv.Itoa(j) + "]."
+			if day.Day < 1 || day.Day > 7 {
+				verr.add(field+"day", "must be between 1 and 7")
+			} else if seen[day.Day] {
+				verr.add(field+"day", "is scheduled more than once in the week")
+			}
+			seen[day.Day] = true
+			if day.TemplateID == "" {
+				verr.add(field+"templateId", "is required")
+			}
+		}
+	}
+	if p.Progression.WeeklyIncrement < 0 {
+		verr.add("progression.weeklyIncrement", "must not be negative")
+	}
+	if p.Progression.DeloadPercent < 0 || p.Progression.DeloadPercent > 100 {
+		verr.add("progression.deloadPercent", "must be between 0 and 100")
+	}
+	if p.Enrollment != nil {
+		if _, err := time.Parse(DateLayout, p.Enrollment.StartDate); err != nil {
+			verr.add("enrollment.startDate", "must be YYYY-MM-DD")
+		}
+	}
+	return verr.err()
+}
+
+// Program progress states.
+const (
+	ProgramStatusUpcoming = "upcoming"
+	ProgramStatusActive   = "active"
+	ProgramStatusFinished = "finished"
+)
+
+// ScheduledSession is the session a program schedules on Date, with the
+// template's prescriptions already progressed for Week. WorkoutID is set once
+// the session has been started.
+type ScheduledSession struct {
+	Week       int                    `json:"week"`
+	Day        int                    `json:"day"`
+	Date       string                 `json:"date"`
+	Deload     bool                   `json:"deload,omitempty"`
+	TemplateID string                 `json:"templateId"`
+	Name       string                 `json:"name"`
+	Exercises  []ExercisePrescription `json:"exercises"`
+	WorkoutID  string                 `json:"workoutId,omitempty"`
+}
+
+// ProgramProgress summarises how far an enrolment has got on a given date.
+// CurrentWeek is 0 before the start date. SessionsCompleted counts sessions
+// whose workout has been finished, not those merely started.
+type ProgramProgress struct {
+	Status            string  `json:"status"`
+	StartDate         string  `json:"startDate"`
+	EndDate           string  `json:"endDate"`
+	CurrentWeek       int     `json:"currentWeek"`
+	TotalWeeks        int     `json:"totalWeeks"`
+	SessionsCompleted int     `json:"sessionsCompleted"`
+	SessionsTotal     int     `json:"sessionsTotal"`
+	PercentComplete   float64 `json:"percentComplete"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProgressionApply(t *testing.T) {
+	g := Progression{WeeklyIncrement: 2.5, DeloadPercent: 60}
+	bench := ExercisePrescription{Name: "Bench Press", TargetSets: 3, TargetReps: 5, TargetWeight: 80, Unit: "kg"}
+
+	tests := []struct {
+		week   int
+		deload bool
+		want   float64
+	}{
+		{1, false, 80},
+		{2, false, 82.5},
+		{3, false, 85},
+		{4, true, 52.5},
+	}
+	for _, tt := range tests {
+		if got := g.Apply(bench, tt.week, tt.deload).TargetWeight; got != tt.want {
+			t.Errorf("week %d: target weight = %v, want %v", tt.week, got, tt.want)
+		}
+	}
+
+	plank := ExercisePrescription{Name: "Plank", TargetSets: 3}
+	if got := g.Apply(plank, 3, false); got != plank {
+		t.Errorf("unweighted prescription changed: %+v", got)
+	}
+}
+
+func TestProgramValidate_FieldErrors(t *testing.T) {
+	p := Program{
+		UserID:    "u",
+		ProgramID: "p",
+		Name:      "5/3/1",
+		Weeks: []ProgramWeek{
+			{Days: []ProgramDay{{Day: 1, TemplateID: "t"}, {Day: 1, TemplateID: "t"}, {Day: 9}}},
+		},
+		Progression: Progression{DeloadPercent: 150},
+		Enrollment:  &Enrollment{StartDate: "soon"},
+	}
+
+	var verr *ValidationError
+	if err := p.Validate(); !errors.As(err, &verr) || !errors.Is(err, ErrInvalidProgram) {
+		t.Fatalf("err = %v, want *ValidationError wrapping ErrInvalidProgram", err)
+	}
+	want := []string{
+		"weeks[0].days[1].day",
+		"weeks[0].days[2].day",
+		"weeks[0].days[2].templateId",
+		"progression.deloadPercent",
+		"enrollment.startDate",
+	}
+	if len(verr.Fields) != len(want) {
+		t.Fatalf("Fields = %+v, want %v", verr.Fields, want)
+	}
+	for i, field := range want {
+		if verr.Fields[i].Field != field {
+			t.Errorf("Fields[%d] = %q, want %q", i, verr.Fields[i].Field, field)
+		}
+	}
+}
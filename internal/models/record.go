@@ -0,0 +1,74 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// PersonalRecord kinds.
+const (
+	RecordHeaviestWeight  = "heaviest_weight"  // heaviest set weight
+	RecordRepsAtWeight    = "reps_at_weight"   // most reps in one set at Qualifier weight
+	RecordEstimated1RM    = "estimated_1rm"    // best estimated one-rep max of any set
+	RecordLongestDuration = "longest_duration" // longest timed set, in seconds
+	RecordFastestTime     = "fastest_time"     // shortest Time over Qualifier distance, in seconds
+	RecordHighestRPM      = "highest_rpm"      // highest cardio RPM
+)
+
+// LowerIsBetter reports whether smaller values beat larger ones for kind.
+func LowerIsBetter(kind string) bool {
+	return kind == RecordFastestTime
+}
+
+// PersonalRecord is a best performance for an exercise name. Records are never
+// overwritten: each new best is stored alongside the ones it beat, so the
+// records for a name form its PR history.
+type PersonalRecord struct {
+	UserID       string    `json:"userId" dynamodbav:"UserID"`
+	RecordID     string    `json:"recordId" dynamodbav:"RecordID"`
+	ExerciseName string    `json:"exerciseName"`
+	ExerciseID   string    `json:"exerciseId"`
+	Kind         string    `json:"kind"`
+	Qualifier    string    `json:"qualifier,omitempty"` // e.g. "100 kg" or "5 km"
+	Value        float64   `json:"value"`
+	Unit         string    `json:"unit"`
+	Previous     float64   `json:"previous,omitempty"` // the value this record beat, if any
+	AchievedAt   time.Time `json:"achievedAt"`
+}
+
+// Key identifies what a record measures; records with the same key compete.
+func (r *PersonalRecord) Key() string {
+	return r.Kind + "|" + r.Qualifier + "|" + r.Unit
+}
+
+// Beats reports whether r is a better performance than other of the same key.
+func (r *PersonalRecord) Beats(other *PersonalRecord) bool {
+	if LowerIsBetter(r.Kind) {
+		return r.Value < other.Value
+	}
+	return r.Value > other.Value
+}
+
+// ExerciseRecords groups the records for one exercise name: the current best
+// for each key and the full history, oldest first.
+type ExerciseRecords struct {
+	Name    string            `json:"name"`
+	Current []*PersonalRecord `json:"current"`
+	History []*PersonalRecord `json:"history"`
+}
+
+// EstimatedOneRepMax estimates a one-rep max from a set using the Epley
+// formula; see OneRepMax.
+func EstimatedOneRepMax(weight float64, reps int) float64 {
+	return OneRepMax(FormulaEpley, weight, reps)
+}
+
+// SortRecords orders records oldest first, breaking ties by RecordID.
+func SortRecords(records []*PersonalRecord) {
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].AchievedAt.Equal(records[j].AchievedAt) {
+			return records[i].AchievedAt.Before(records[j].AchievedAt)
+		}
+		return records[i].RecordID < records[j].RecordID
+	})
+}
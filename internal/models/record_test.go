@@ -0,0 +1,41 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimatedOneRepMax(t *testing.T) {
+	tests := []struct {
+		weight float64
+		reps   int
+		want   float64
+	}{
+		{100, 1, 100},
+		{100, 5, 116.6667},
+		{100, 0, 0},
+		{0, 10, 0},
+	}
+	for _, tt := range tests {
+		if got := EstimatedOneRepMax(tt.weight, tt.reps); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("EstimatedOneRepMax(%v, %d) = %v, want %v", tt.weight, tt.reps, got, tt.want)
+		}
+	}
+}
+
+func TestPersonalRecordBeats(t *testing.T) {
+	heavy := &PersonalRecord{Kind: RecordHeaviestWeight, Value: 120}
+	light := &PersonalRecord{Kind: RecordHeaviestWeight, Value: 100}
+	if !heavy.Beats(light) || light.Beats(heavy) {
+		t.Error("a heavier weight should beat a lighter one")
+	}
+
+	fast := &PersonalRecord{Kind: RecordFastestTime, Value: 1200}
+	slow := &PersonalRecord{Kind: RecordFastestTime, Value: 1500}
+	if !fast.Beats(slow) || slow.Beats(fast) {
+		t.Error("a shorter time should beat a longer one")
+	}
+	if fast.Beats(fast) {
+		t.Error("a record should not beat an equal one")
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import "time"
+
+// One-rep max formulas.
+const (
+	FormulaEpley   = "epley"
+	FormulaBrzycki = "brzycki"
+)
+
+// OneRepMax estimates a one-rep max from a set with formula. A single rep is
+// its own max; sets without reps or weight, and Brzycki sets of 37 reps or
+// more, have none.
+func OneRepMax(formula string, weight float64, reps int) float64 {
+	switch {
+	case reps <= 0 || weight <= 0:
+		return 0
+	case reps == 1:
+		return weight
+	case formula == FormulaBrzycki:
+		if reps >= 37 {
+			return 0
+		}
+		return weight * 36 / float64(37-reps)
+	default:
+		return weight * (1 + float64(reps)/30)
+	}
+}
+
+// Stats metrics.
+const (
+	MetricE1RM    = "e1rm"    // best estimated one-rep max in the bucket
+	MetricTonnage = "tonnage" // sum of weight × reps
+	MetricReps    = "reps"    // total reps
+	MetricSets    = "sets"    // number of sets
+	MetricDensity = "density" // tonnage per minute of measured workout time
+)
+
+// Stats buckets.
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week" // ISO weeks, starting on Monday
+	BucketMonth = "month"
+)
+
+// BucketStart returns the first day of the bucket containing t.
+func BucketStart(bucket string, t time.Time) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case BucketWeek:
+		return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return t.AddDate(0, 0, 1-t.Day())
+	}
+	return t
+}
+
+// StatsQuery selects the series returned for an exercise. Empty fields take
+// the defaults applied by Normalize.
+type StatsQuery struct {
+	Metric  string
+	Bucket  string
+	Formula string
+	Unit    string
+	From    string
+	To      string
+}
+
+// Normalize fills in defaults: e1rm, weekly buckets, Epley and kilograms.
+func (q *StatsQuery) Normalize() {
+	if q.Metric == "" {
+		q.Metric = MetricE1RM
+	}
+	if q.Bucket == "" {
+		q.Bucket = BucketWeek
+	}
+	if q.Formula == "" {
+		q.Formula = FormulaEpley
+	}
+	if q.Unit == "" {
+		q.Unit = UnitKg
+	} else if unit := NormalizeWeightUnit(q.Unit); unit != "" {
+		q.Unit = unit
+	}
+}
+
+func (q *StatsQuery) Validate() error {
+	verr := &ValidationError{Kind: ErrInvalidStatsQuery}
+	switch q.Metric {
+	case MetricE1RM, MetricTonnage, MetricReps, MetricSets, MetricDensity:
+	default:
+		verr.add("metric", "must be one of e1rm, tonnage, reps, sets, density")
+	}
+	switch q.Bucket {
+	case BucketDay, BucketWeek, BucketMonth:
+	default:
+		verr.add("bucket", "must be one of day, week, month")
+	}
+	if q.Formula != FormulaEpley && q.Formula != FormulaBrzycki {
+		verr.add("formula", "must be epley or brzycki")
+	}
+	if q.Unit != UnitKg && q.Unit != UnitLb {
+		verr.add("unit", "must be kg or lb")
+	}
+	return verr.err()
+}
+
+// StatsPoint is one bucket of a series. Period is the bucket's first day.
+type StatsPoint struct {
+	Period string  `json:"period"`
+	Value  float64 `json:"value"`
+}
+
+// ExerciseStats is a time series of one metric for an exercise name.
+type ExerciseStats struct {
+	Name    string       `json:"name"`
+	Metric  string       `json:"metric"`
+	Bucket  string       `json:"bucket"`
+	Formula string       `json:"formula,omitempty"`
+	Unit    string       `json:"unit,omitempty"`
+	Points  []StatsPoint `json:"points"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestOneRepMax_Formulas(t *testing.T) {
+	tests := []struct {
+		formula string
+		weight  float64
+		reps    int
+		want    float64
+	}{
+		{FormulaEpley, 100, 10, 133.333},
+		{FormulaBrzycki, 100, 10, 133.333},
+		{FormulaBrzycki, 100, 5, 112.5},
+		{FormulaBrzycki, 100, 1, 100},
+		{FormulaBrzycki, 100, 37, 0},
+	}
+	for _, tt := range tests {
+		if got := OneRepMax(tt.formula, tt.weight, tt.reps); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("OneRepMax(%s, %v, %d) = %v, want %v", tt.formula, tt.weight, tt.reps, got, tt.want)
+		}
+	}
+}
+
+func TestBucketStart(t *testing.T) {
+	thursday := time.Date(2024, 2, 29, 18, 30, 0, 0, time.UTC)
+	tests := map[string]string{
+		BucketDay:   "2024-02-29",
+		BucketWeek:  "2024-02-26",
+		BucketMonth: "2024-02-01",
+	}
+	for bucket, want := range tests {
+		if got := BucketStart(bucket, thursday).Format(DateLayout); got != want {
+			t.Errorf("BucketStart(%s) = %s, want %s", bucket, got, want)
+		}
+	}
+	sunday := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+	if got := BucketStart(BucketWeek, sunday).Format(DateLayout); got != "2024-02-26" {
+		t.Errorf("Sunday belongs to week %s, want 2024-02-26", got)
+	}
+}
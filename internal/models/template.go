@@ -0,0 +1,68 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// ExercisePrescription is one planned exercise in a Template: what to do and
+// the targets to aim for. Starting the template turns it into an Exercise with
+// TargetSets sets of TargetReps at TargetWeight.
+type ExercisePrescription struct {
+	Name         string  `json:"name"`
+	ExerciseType string  `json:"exerciseType"`
+	TargetSets   int     `json:"targetSets,omitempty"`
+	TargetReps   int     `json:"targetReps,omitempty"`
+	TargetWeight float64 `json:"targetWeight,omitempty"`
+	Unit         string  `json:"unit,omitempty"`
+}
+
+// Template is a reusable routine, e.g. "Push Day A", whose exercises are
+// listed in the order they should be performed.
+type Template struct {
+	UserID     string                 `json:"userId" dynamodbav:"UserID"`
+	TemplateID string                 `json:"templateId" dynamodbav:"TemplateID"`
+	Name       string                 `json:"name"`
+	Exercises  []ExercisePrescription `json:"exercises"`
+	CreatedAt  time.Time              `json:"createdAt"`
+}
+
+func (t *Template) Validate() error {
+	verr := &ValidationError{Kind: ErrInvalidTemplate}
+	if t.UserID == "" {
+		verr.add("userId", "is required")
+	}
+	if t.TemplateID == "" {
+		verr.add("templateId", "is required")
+	}
+	if NormalizeName(t.Name) == "" {
+		verr.add("name", "is required")
+	}
+	if len(t.Exercises) == 0 {
+		verr.add("exercises", "must contain at least one exercise")
+	}
+	for i, p := range t.Exercises {
+		field := "exercises[" + strconv.Itoa(i) + "]."
+		if NormalizeName(p.Name) == "" {
+			verr.add(field+"name", "is required")
+		}
+		if p.ExerciseType == "" {
+			verr.add(field+"exerciseType", "is required")
+		} else if !validExerciseTypes[p.ExerciseType] {
+			verr.add(field+"exerciseType", "must be one of weights, cardio, body_weight, other")
+		}
+		if p.TargetSets < 0 {
+			verr.add(field+"targetSets", "must not be negative")
+		}
+		if p.TargetReps < 0 {
+			verr.add(field+"targetReps", "must not be negative")
+		}
+		if p.TargetWeight < 0 {
+			verr.add(field+"targetWeight", "must not be negative")
+		}
+		if p.Unit != "" && NormalizeWeightUnit(p.Unit) == "" {
+			verr.add(field+"unit", "must be kg or lb")
+		}
+	}
+	return verr.err()
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTemplateValidate_FieldErrors(t *testing.T) {
+	squat := ExercisePrescription{Name: "Squat", ExerciseType: ExerciseTypeWeights, TargetSets: 3, TargetReps: 5, TargetWeight: 100, Unit: "kg"}
+	tests := []struct {
+		name     string
+		template Template
+		fields   []string
+	}{
+		{"valid", Template{UserID: "u", TemplateID: "t", Name: "Legs", Exercises: []ExercisePrescription{squat}}, nil},
+		{"empty", Template{}, []string{"userId", "templateId", "name", "exercises"}},
+		{"bad prescription", Template{UserID: "u", TemplateID: "t", Name: "Legs", Exercises: []ExercisePrescription{
+			squat,
+			{Name: " ", ExerciseType: "yoga", TargetSets: -1},
+		}}, []string{"exercises[1].name", "exercises[1].exerciseType", "exercises[1].targetSets"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidTemplate) {
+				t.Fatalf("err = %v, want *ValidationError wrapping ErrInvalidTemplate", err)
+			}
+			if len(verr.Fields) != len(tt.fields) {
+				t.Fatalf("Fields = %+v, want %v", verr.Fields, tt.fields)
+			}
+			for i, field := range tt.fields {
+				if verr.Fields[i].Field != field {
+					t.Errorf("Fields[%d] = %q, want %q", i, verr.Fields[i].Field, field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Weight units.
+const (
+	UnitKg = "kg"
+	UnitLb = "lb"
+)
+
+const poundsPerKg = 2.20462262185
+
+// NormalizeWeightUnit maps the spellings clients send to UnitKg or UnitLb.
+// Unknown units, including the empty string, return "".
+func NormalizeWeightUnit(unit string) string {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "kg", "kgs", "kilo", "kilos", "kilogram", "kilograms":
+		return UnitKg
+	case "lb", "lbs", "pound", "pounds":
+		return UnitLb
+	}
+	return ""
+}
+
+// ConvertWeight converts value from one weight unit to another. Units that
+// are not recognised are assumed to be kilograms.
+func ConvertWeight(value float64, from, to string) float64 {
+	from, to = NormalizeWeightUnit(from), NormalizeWeightUnit(to)
+	switch {
+	case from == UnitLb && to != UnitLb:
+		return value / poundsPerKg
+	case from != UnitLb && to == UnitLb:
+		return value * poundsPerKg
+	}
+	return value
+}
+
+// UnitPreferences are the units a user wants weights and distances in.
+// Weight is kg or lb; Distance is km or mi.
+type UnitPreferences struct {
+	Weight   string `json:"weight"`
+	Distance string `json:"distance"`
+}
+
+// DefaultUnits apply to users who have not chosen units.
+var DefaultUnits = UnitPreferences{Weight: UnitKg, Distance: DistanceKm}
+
+// Normalize canonicalises both units, leaving unknown spellings as they are
+// so Validate can report them.
+func (p *UnitPreferences) Normalize() {
+	if u := NormalizeWeightUnit(p.Weight); u != "" {
+		p.Weight = u
+	}
+	if u := NormalizeDistanceUnit(p.Distance); u != "" {
+		p.Distance = u
+	}
+}
+
+// validate adds field errors under prefix (e.g. "units.") to verr.
+func (p UnitPreferences) validate(verr *ValidationError, prefix string) {
+	if p.Weight != UnitKg && p.Weight != UnitLb {
+		verr.add(prefix+"weight", "must be kg or lb")
+	}
+	if p.Distance != DistanceKm && p.Distance != DistanceMiles {
+		verr.add(prefix+"distance", "must be km or mi")
+	}
+}
+
+// ParseUnits reads a units request such as "preferred", "lb" or "lb,mi".
+// Explicit units override the corresponding preference; "preferred" alone
+// keeps both. Any other token is an error.
+func ParseUnits(spec string, preferred UnitPreferences) (UnitPreferences, error) {
+	units := preferred
+	for _, token := range strings.Split(spec, ",") {
+		token = strings.TrimSpace(token)
+		switch {
+		case token == "" || strings.EqualFold(token, "preferred"):
+		case NormalizeWeightUnit(token) != "":
+			units.Weight = NormalizeWeightUnit(token)
+		case NormalizeDistanceUnit(token) == DistanceKm || NormalizeDistanceUnit(token) == DistanceMiles:
+			units.Distance = NormalizeDistanceUnit(token)
+		default:
+			return units, fmt.Errorf("unknown unit %q", token)
+		}
+	}
+	return units, nil
+}
+
+// NormalizeUnits rewrites set and distance units to their canonical spelling.
+// When that changes what the client sent, the original is kept alongside.
+// Unknown units are left for Validate to reject.
+func (e *Exercise) NormalizeUnits() {
+	for i := range e.Sets {
+		set := &e.Sets[i]
+		if u := NormalizeWeightUnit(set.Unit); u != "" && u != set.Unit {
+			set.OriginalUnit = set.Unit
+			set.Unit = u
+		}
+	}
+	if u := NormalizeDistanceUnit(e.DistanceUnit); u != "" && u != e.DistanceUnit {
+		e.OriginalDistanceUnit = e.DistanceUnit
+		e.DistanceUnit = u
+	}
+}
+
+// ConvertUnits converts set weights and the distance into units, in place.
+// Calories and unknown units are left untouched.
+func (e *Exercise) ConvertUnits(units UnitPreferences) {
+	for i := range e.Sets {
+		set := &e.Sets[i]
+		if NormalizeWeightUnit(set.Unit) == "" || NormalizeWeightUnit(set.Unit) == units.Weight {
+			continue
+		}
+		set.Weight = roundTo(ConvertWeight(set.Weight, set.Unit, units.Weight), 2)
+		set.Unit = units.Weight
+	}
+	if meters, ok := DistanceInMeters(e.Distance, e.DistanceUnit); ok && NormalizeDistanceUnit(e.DistanceUnit) != units.Distance {
+		e.Distance = roundTo(MetersIn(meters, units.Distance), 3)
+		e.DistanceUnit = units.Distance
+	}
+}
+
+// roundTo rounds v to the given number of decimal places.
+func roundTo(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvertWeight(t *testing.T) {
+	if got := ConvertWeight(100, "kg", "lb"); math.Abs(got-220.462) > 1e-3 {
+		t.Errorf("100 kg = %v lb, want 220.462", got)
+	}
+	if got := ConvertWeight(220.462262185, "Pounds", "kg"); math.Abs(got-100) > 1e-6 {
+		t.Errorf("220.46 lb = %v kg, want 100", got)
+	}
+	if got := ConvertWeight(50, "", "kg"); got != 50 {
+		t.Errorf("unknown unit converted to %v, want it treated as kg", got)
+	}
+}
+
+func TestExercise_NormalizeUnitsKeepsOriginal(t *testing.T) {
+	e := &Exercise{
+		Sets:         []WeightItem{{Weight: 225, Unit: "Pounds", Reps: 5}, {Weight: 100, Unit: "kg", Reps: 3}},
+		Distance:     3,
+		DistanceUnit: "miles",
+	}
+	e.NormalizeUnits()
+
+	if e.Sets[0].Unit != UnitLb || e.Sets[0].OriginalUnit != "Pounds" {
+		t.Errorf("set 0 = %+v, want lb with original Pounds", e.Sets[0])
+	}
+	if e.Sets[1].OriginalUnit != "" {
+		t.Errorf("canonical unit kept an original: %+v", e.Sets[1])
+	}
+	if e.DistanceUnit != DistanceMiles || e.OriginalDistanceUnit != "miles" {
+		t.Errorf("distance unit = %q (original %q), want mi (miles)", e.DistanceUnit, e.OriginalDistanceUnit)
+	}
+}
+
+func TestExercise_ValidateRejectsUnknownUnits(t *testing.T) {
+	e := &Exercise{
+		Name:         "Bench Press",
+		ExerciseType: ExerciseTypeWeights,
+		Sets:         []WeightItem{{Weight: 100, Unit: "stone", Reps: 5}},
+		DistanceUnit: "furlongs",
+	}
+	err := e.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate() = %v, want a ValidationError", err)
+	}
+	fields := map[string]bool{}
+	for _, f := range verr.Fields {
+		fields[f.Field] = true
+	}
+	if !fields["sets[0].unit"] || !fields["distanceUnit"] {
+		t.Errorf("invalid fields = %+v, want sets[0].unit and distanceUnit", verr.Fields)
+	}
+}
+
+func TestExercise_ConvertUnits(t *testing.T) {
+	e := &Exercise{
+		Sets:         []WeightItem{{Weight: 100, Unit: UnitKg, Reps: 5}, {Weight: 45, Unit: UnitLb, Reps: 5}, {Reps: 10}},
+		Distance:     5,
+		DistanceUnit: DistanceKm,
+	}
+	e.ConvertUnits(UnitPreferences{Weight: UnitLb, Distance: DistanceMiles})
+
+	if e.Sets[0].Weight != 220.46 || e.Sets[0].Unit != UnitLb {
+		t.Errorf("set 0 = %+v, want 220.46 lb", e.Sets[0])
+	}
+	if e.Sets[1].Weight != 45 {
+		t.Errorf("set already in lb changed: %+v", e.Sets[1])
+	}
+	if e.Sets[2].Unit != "" {
+		t.Errorf("set without a unit was converted: %+v", e.Sets[2])
+	}
+	if math.Abs(e.Distance-3.107) > 1e-9 || e.DistanceUnit != DistanceMiles {
+		t.Errorf("distance = %v %s, want 3.107 mi", e.Distance, e.DistanceUnit)
+	}
+
+	calories := &Exercise{Distance: 50, DistanceUnit: DistanceCalories}
+	calories.ConvertUnits(DefaultUnits)
+	if calories.Distance != 50 || calories.DistanceUnit != DistanceCalories {
+		t.Errorf("calories were converted: %v %s", calories.Distance, calories.DistanceUnit)
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	preferred := UnitPreferences{Weight: UnitLb, Distance: DistanceMiles}
+	tests := map[string]UnitPreferences{
+		"preferred":    preferred,
+		"kg":           {Weight: UnitKg, Distance: DistanceMiles},
+		"kg, km":       {Weight: UnitKg, Distance: DistanceKm},
+		"preferred,km": {Weight: UnitLb, Distance: DistanceKm},
+	}
+	for spec, want := range tests {
+		got, err := ParseUnits(spec, preferred)
+		if err != nil || got != want {
+			t.Errorf("ParseUnits(%q) = %+v, %v; want %+v", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"stone", "m", "cal"} {
+		if _, err := ParseUnits(spec, preferred); err == nil {
+			t.Errorf("ParseUnits(%q) should fail", spec)
+		}
+	}
+}
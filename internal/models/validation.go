@@ -0,0 +1,46 @@
+package models
+
+import "strings"
+
+// FieldError describes one invalid field, named as it appears in JSON.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every invalid field of a request body. It unwraps to
+// Kind (e.g. ErrInvalidWorkout) so callers can use errors.Is.
+type ValidationError struct {
+	Kind   error
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + ": " + f.Message
+	}
+	return e.Kind.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Kind
+}
+
+// NewValidationError returns a ValidationError of kind for a single field.
+func NewValidationError(kind error, field, message string) *ValidationError {
+	return &ValidationError{Kind: kind, Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+// add records an invalid field.
+func (e *ValidationError) add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// err returns e if any field was invalid, nil otherwise.
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
@@ -1,31 +1,65 @@
 package models
 
 import (
-	"errors"
+	"fmt"
 	"time"
 )
 
+// DateLayout is the ISO-8601 calendar date format used for Workout.Date.
+const DateLayout = "2006-01-02"
+
+// ParseDate parses an ISO-8601 date (2024-01-15) or RFC 3339 timestamp
+// (2024-01-15T18:30:00Z).
+func ParseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(DateLayout, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q: must be YYYY-MM-DD or an RFC 3339 timestamp", value)
+}
+
 type Workout struct {
 	UserID    string     	`json:"userId" dynamodbav:"UserID" validate:"required"`
 	WorkoutID string     	`json:"workoutId" dynamodbav:"WorkoutID" validate:"required"`
 	Name      string     	`json:"name" validate:"required, min=1,max=100"`
 	Exercises []string 		`json:"exercises"`
+	// Groups arranges Exercises into singles, supersets and circuits. When
+	// present it is authoritative and Exercises is its flattened order.
+	Groups    []ExerciseGroup	`json:"groups,omitempty"`
 	Date		 	string  		`json:"date"`
 	CreatedAt time.Time  	`json:"createdAt"`
+	// DeletedAt is set while the workout is in the trash.
+	DeletedAt *time.Time	`json:"deletedAt,omitempty"`
+	WorkoutSession
+}
+
+// WorkoutDetail is a workout with its exercise IDs resolved to full exercises,
+// in workout order. IDs that no longer resolve are listed in MissingExerciseIDs.
+type WorkoutDetail struct {
+	Workout
+	Exercises          []*Exercise `json:"exercises"`
+	MissingExerciseIDs []string    `json:"missingExerciseIds,omitempty"`
 }
 
-func(w *Workout) Validate() error {
+func (w *Workout) Validate() error {
+	verr := &ValidationError{Kind: ErrInvalidWorkout}
 	if w.UserID == "" {
-		return errors.New("userID is required")
+		verr.add("userId", "is required")
 	}
 	if w.WorkoutID == "" {
-		return errors.New("workoutID is required")
+		verr.add("workoutId", "is required")
 	}
 	if w.Name == "" {
-		return errors.New("name is required")
+		verr.add("name", "is required")
 	}
 	if w.Date == "" {
-		return errors.New("date is required")
+		verr.add("date", "is required")
+	} else if _, err := ParseDate(w.Date); err != nil {
+		verr.add("date", "must be YYYY-MM-DD or an RFC 3339 timestamp")
 	}
-	return nil
+	w.validateGroups(verr)
+	w.WorkoutSession.validate(verr)
+	return verr.err()
 }
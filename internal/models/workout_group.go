@@ -0,0 +1,286 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Valid ExerciseGroup kinds.
+const (
+	GroupSingle   = "single"
+	GroupSuperset = "superset"
+	GroupCircuit  = "circuit"
+	GroupEMOM     = "emom"
+	GroupAMRAP    = "amrap"
+)
+
+// minGroupSize is the fewest exercises a group of each kind holds.
+var minGroupSize = map[string]int{
+	GroupSingle:   1,
+	GroupSuperset: 2,
+	GroupCircuit:  2,
+	GroupEMOM:     1,
+	GroupAMRAP:    1,
+}
+
+const maxGroupRounds = 100
+
+// ExerciseGroup is a run of exercises performed together, in order, for
+// Rounds rounds with RestSeconds of rest between rounds.
+type ExerciseGroup struct {
+	GroupID     string   `json:"groupId"`
+	Kind        string   `json:"kind"`
+	ExerciseIDs []string `json:"exerciseIds"`
+	Rounds      int      `json:"rounds,omitempty"`
+	RestSeconds int      `json:"restSeconds,omitempty"`
+}
+
+// SingleGroup wraps one exercise in a group of its own. The group takes the
+// exercise's ID, so groups derived from a plain exercise list are stable
+// across reads.
+func SingleGroup(exerciseID string) ExerciseGroup {
+	return ExerciseGroup{GroupID: exerciseID, Kind: GroupSingle, ExerciseIDs: []string{exerciseID}}
+}
+
+// NormalizeGroups makes Groups and Exercises agree. A workout without groups,
+// including one stored before groups existed, gets a single group per
+// exercise; an exercise listed more than once keeps its first place, as
+// AddExercise would have. Otherwise Exercises is rebuilt from the groups in
+// order.
+func (w *Workout) NormalizeGroups() {
+	if len(w.Groups) == 0 {
+		seen := make(map[string]bool, len(w.Exercises))
+		exercises := []string{}
+		groups := []ExerciseGroup{}
+		for _, id := range w.Exercises {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			exercises = append(exercises, id)
+			groups = append(groups, SingleGroup(id))
+		}
+		w.Exercises, w.Groups = exercises, groups
+		return
+	}
+
+	for i := range w.Groups {
+		w.Groups[i].Kind = strings.ToLower(strings.TrimSpace(w.Groups[i].Kind))
+	}
+	w.flatten()
+}
+
+// flatten rebuilds Exercises from Groups after the groups change.
+func (w *Workout) flatten() {
+	exercises := []string{}
+	for _, g := range w.Groups {
+		exercises = append(exercises, g.ExerciseIDs...)
+	}
+	w.Exercises = exercises
+}
+
+func (w *Workout) validateGroups(verr *ValidationError) {
+	groupIDs := make(map[string]bool, len(w.Groups))
+	exerciseIDs := make(map[string]bool, len(w.Exercises))
+	for i, g := range w.Groups {
+		prefix := "groups[" + strconv.Itoa(i) + "]"
+		if g.GroupID == "" {
+			verr.add(prefix+".groupId", "is required")
+		} else if groupIDs[g.GroupID] {
+			verr.add(prefix+".groupId", "must be unique")
+		}
+		groupIDs[g.GroupID] = true
+
+		if min, ok := minGroupSize[g.Kind]; !ok {
+			verr.add(prefix+".kind", "must be one of single, superset, circuit, emom, amrap")
+		} else if g.Kind == GroupSingle && len(g.ExerciseIDs) != 1 {
+			verr.add(prefix+".exerciseIds", "must hold exactly one exercise")
+		} else if len(g.ExerciseIDs) < min {
+			verr.add(prefix+".exerciseIds", fmt.Sprintf("must hold at least %d exercises", min))
+		}
+		for _, id := range g.ExerciseIDs {
+			if id == "" {
+				verr.add(prefix+".exerciseIds", "must not contain empty IDs")
+			} else if exerciseIDs[id] {
+				verr.add(prefix+".exerciseIds", "must not repeat exercise "+id)
+			}
+			exerciseIDs[id] = true
+		}
+
+		if g.Rounds < 0 || g.Rounds > maxGroupRounds {
+			verr.add(prefix+".rounds", "must be between 0 and 100")
+		}
+		if g.RestSeconds < 0 || g.RestSeconds > maxRestSeconds {
+			verr.add(prefix+".restSeconds", "must be between 0 and 3600")
+		}
+	}
+}
+
+// groupOf returns the index of the group holding exerciseID, or -1.
+func (w *Workout) groupOf(exerciseID string) int {
+	for i, g := range w.Groups {
+		for _, id := range g.ExerciseIDs {
+			if id == exerciseID {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// AddExercise appends exerciseID to the workout in a group of its own and
+// reports whether it did. An exercise already in the workout stays where it
+// is.
+func (w *Workout) AddExercise(exerciseID string) bool {
+	w.NormalizeGroups()
+	if w.groupOf(exerciseID) >= 0 {
+		return false
+	}
+	w.Groups = append(w.Groups, SingleGroup(exerciseID))
+	w.flatten()
+	return true
+}
+
+// RemoveExercise takes exerciseID out of the workout and reports whether it
+// was there. A group left empty is dropped, and one left too small for its
+// kind becomes a single.
+func (w *Workout) RemoveExercise(exerciseID string) bool {
+	w.NormalizeGroups()
+	i := w.groupOf(exerciseID)
+	if i < 0 {
+		return false
+	}
+	w.detach(i, exerciseID)
+	w.flatten()
+	return true
+}
+
+// detach removes exerciseID from group i, dropping or demoting the group as
+// RemoveExercise describes.
+func (w *Workout) detach(i int, exerciseID string) {
+	g := &w.Groups[i]
+	ids := make([]string, 0, len(g.ExerciseIDs))
+	for _, id := range g.ExerciseIDs {
+		if id != exerciseID {
+			ids = append(ids, id)
+		}
+	}
+	g.ExerciseIDs = ids
+	switch {
+	case len(ids) == 0:
+		w.Groups = append(w.Groups[:i], w.Groups[i+1:]...)
+	case len(ids) < minGroupSize[g.Kind]:
+		g.Kind = GroupSingle
+	}
+}
+
+// Reorder rearranges the workout to follow exerciseIDs, which must list every
+// exercise exactly once. Members of a group must stay next to each other;
+// groups move as a whole and their members take the order given.
+func (w *Workout) Reorder(exerciseIDs []string) error {
+	w.NormalizeGroups()
+	verr := &ValidationError{Kind: ErrInvalidWorkout}
+	if len(exerciseIDs) != len(w.Exercises) {
+		verr.add("exerciseIds", "must list every exercise in the workout exactly once")
+		return verr.err()
+	}
+
+	order := make([]ExerciseGroup, 0, len(w.Groups))
+	placed := make(map[int]bool, len(w.Groups))
+	seen := make(map[string]bool, len(exerciseIDs))
+	for pos := 0; pos < len(exerciseIDs); {
+		id := exerciseIDs[pos]
+		i := w.groupOf(id)
+		if i < 0 || seen[id] {
+			verr.add("exerciseIds", "must list every exercise in the workout exactly once")
+			return verr.err()
+		}
+		if placed[i] {
+			verr.add("exerciseIds", "must keep the exercises of group "+w.Groups[i].GroupID+" together")
+			return verr.err()
+		}
+		placed[i] = true
+
+		g := w.Groups[i]
+		members := make(map[string]bool, len(g.ExerciseIDs))
+		for _, member := range g.ExerciseIDs {
+			members[member] = true
+		}
+		g.ExerciseIDs = make([]string, 0, len(members))
+		for ; pos < len(exerciseIDs) && members[exerciseIDs[pos]]; pos++ {
+			if seen[exerciseIDs[pos]] {
+				verr.add("exerciseIds", "must list every exercise in the workout exactly once")
+				return verr.err()
+			}
+			seen[exerciseIDs[pos]] = true
+			g.ExerciseIDs = append(g.ExerciseIDs, exerciseIDs[pos])
+		}
+		if len(g.ExerciseIDs) != len(members) {
+			verr.add("exerciseIds", "must keep the exercises of group "+g.GroupID+" together")
+			return verr.err()
+		}
+		order = append(order, g)
+	}
+
+	w.Groups = order
+	w.flatten()
+	return nil
+}
+
+// Group gathers group.ExerciseIDs, which must already be in the workout, into
+// group, in the order given. The new group takes the place of the first
+// exercise listed; the groups it drew from shrink as RemoveExercise
+// describes.
+func (w *Workout) Group(group ExerciseGroup) error {
+	w.NormalizeGroups()
+	verr := &ValidationError{Kind: ErrInvalidWorkout}
+	if len(group.ExerciseIDs) == 0 {
+		verr.add("exerciseIds", "is required")
+		return verr.err()
+	}
+	for _, id := range group.ExerciseIDs {
+		if w.groupOf(id) < 0 {
+			verr.add("exerciseIds", "exercise "+id+" is not in the workout")
+		}
+	}
+	if err := verr.err(); err != nil {
+		return err
+	}
+
+	// Mark the slot with a placeholder so it survives the groups it came from
+	// being dropped.
+	first := w.groupOf(group.ExerciseIDs[0])
+	w.Groups = append(w.Groups[:first], append([]ExerciseGroup{{}}, w.Groups[first:]...)...)
+	for _, id := range group.ExerciseIDs {
+		if i := w.groupOf(id); i >= 0 {
+			w.detach(i, id)
+		}
+	}
+	for i := range w.Groups {
+		if w.Groups[i].GroupID == "" && w.Groups[i].ExerciseIDs == nil {
+			w.Groups[i] = group
+			break
+		}
+	}
+	w.flatten()
+	return nil
+}
+
+// Ungroup splits the group groupID into singles, in place.
+func (w *Workout) Ungroup(groupID string) error {
+	w.NormalizeGroups()
+	for i, g := range w.Groups {
+		if g.GroupID != groupID {
+			continue
+		}
+		singles := make([]ExerciseGroup, len(g.ExerciseIDs))
+		for j, id := range g.ExerciseIDs {
+			singles[j] = SingleGroup(id)
+		}
+		w.Groups = append(w.Groups[:i], append(singles, w.Groups[i+1:]...)...)
+		w.flatten()
+		return nil
+	}
+	return ErrWorkoutGroupNotFound
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func groupedWorkout() *Workout {
+	return &Workout{
+		UserID: "user-1", WorkoutID: "w-1", Name: "Push", Date: "2024-03-05",
+		Groups: []ExerciseGroup{
+			SingleGroup("a"),
+			{GroupID: "g-1", Kind: GroupSuperset, ExerciseIDs: []string{"b", "c"}, Rounds: 3, RestSeconds: 90},
+			SingleGroup("d"),
+		},
+	}
+}
+
+func groupIDs(w *Workout) []string {
+	ids := make([]string, len(w.Groups))
+	for i, g := range w.Groups {
+		ids[i] = g.GroupID
+	}
+	return ids
+}
+
+func TestNormalizeGroups_MigratesPlainList(t *testing.T) {
+	w := &Workout{Exercises: []string{"a", "b", "a"}}
+	w.NormalizeGroups()
+
+	want := []ExerciseGroup{SingleGroup("a"), SingleGroup("b")}
+	if !reflect.DeepEqual(w.Groups, want) || !reflect.DeepEqual(w.Exercises, []string{"a", "b"}) {
+		t.Errorf("groups = %+v, exercises = %v", w.Groups, w.Exercises)
+	}
+}
+
+func TestNormalizeGroups_FlattensGroups(t *testing.T) {
+	w := groupedWorkout()
+	w.Exercises = []string{"stale"}
+	w.Groups[1].Kind = " Superset "
+	w.NormalizeGroups()
+
+	if !reflect.DeepEqual(w.Exercises, []string{"a", "b", "c", "d"}) || w.Groups[1].Kind != GroupSuperset {
+		t.Errorf("exercises = %v, kind = %q", w.Exercises, w.Groups[1].Kind)
+	}
+	if err := w.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestWorkoutValidate_Groups(t *testing.T) {
+	tests := []struct {
+		name  string
+		group ExerciseGroup
+		field string
+	}{
+		{"unknown kind", ExerciseGroup{GroupID: "g", Kind: "tabata", ExerciseIDs: []string{"x"}}, "groups[3].kind"},
+		{"superset of one", ExerciseGroup{GroupID: "g", Kind: GroupSuperset, ExerciseIDs: []string{"x"}}, "groups[3].exerciseIds"},
+		{"single of two", ExerciseGroup{GroupID: "g", Kind: GroupSingle, ExerciseIDs: []string{"x", "y"}}, "groups[3].exerciseIds"},
+		{"repeated exercise", ExerciseGroup{GroupID: "g", Kind: GroupEMOM, ExerciseIDs: []string{"a"}}, "groups[3].exerciseIds"},
+		{"repeated group", ExerciseGroup{GroupID: "g-1", Kind: GroupAMRAP, ExerciseIDs: []string{"x"}}, "groups[3].groupId"},
+		{"too many rounds", ExerciseGroup{GroupID: "g", Kind: GroupCircuit, ExerciseIDs: []string{"x", "y"}, Rounds: 101}, "groups[3].rounds"},
+		{"negative rest", ExerciseGroup{GroupID: "g", Kind: GroupCircuit, ExerciseIDs: []string{"x", "y"}, RestSeconds: -5}, "groups[3].restSeconds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := groupedWorkout()
+			w.Groups = append(w.Groups, tt.group)
+			w.NormalizeGroups()
+			var verr *ValidationError
+			if err := w.Validate(); !errors.As(err, &verr) || len(verr.Fields) != 1 || verr.Fields[0].Field != tt.field {
+				t.Errorf("Validate = %v, want one error on %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestWorkout_AddAndRemoveKeepGroups(t *testing.T) {
+	w := groupedWorkout()
+	if !w.AddExercise("e") {
+		t.Fatal("AddExercise(e) = false")
+	}
+	if got := groupIDs(w); !reflect.DeepEqual(got, []string{"a", "g-1", "d", "e"}) {
+		t.Fatalf("after add groups = %v", got)
+	}
+	if w.AddExercise("b") || !reflect.DeepEqual(groupIDs(w), []string{"a", "g-1", "d", "e"}) {
+		t.Errorf("adding b again changed groups to %v", groupIDs(w))
+	}
+
+	if !w.RemoveExercise("c") {
+		t.Fatal("RemoveExercise(c) = false")
+	}
+	// A superset left with one exercise becomes a single, keeping its ID.
+	if g := w.Groups[1]; g.GroupID != "g-1" || g.Kind != GroupSingle || !reflect.DeepEqual(g.ExerciseIDs, []string{"b"}) {
+		t.Errorf("shrunk group = %+v", g)
+	}
+	if !w.RemoveExercise("b") || !reflect.DeepEqual(groupIDs(w), []string{"a", "d", "e"}) {
+		t.Errorf("after removing b groups = %v", groupIDs(w))
+	}
+	if w.RemoveExercise("missing") {
+		t.Error("RemoveExercise(missing) = true")
+	}
+	if !reflect.DeepEqual(w.Exercises, []string{"a", "d", "e"}) {
+		t.Errorf("exercises = %v", w.Exercises)
+	}
+
+	for _, id := range []string{"a", "d", "e"} {
+		w.RemoveExercise(id)
+	}
+	if len(w.Groups) != 0 || len(w.Exercises) != 0 {
+		t.Errorf("after removing everything: groups = %+v, exercises = %v", w.Groups, w.Exercises)
+	}
+}
+
+func TestWorkout_Reorder(t *testing.T) {
+	w := groupedWorkout()
+	if err := w.Reorder([]string{"c", "b", "d", "a"}); err != nil {
+		t.Fatalf("Reorder: %v", err)
+	}
+	if !reflect.DeepEqual(w.Exercises, []string{"c", "b", "d", "a"}) || !reflect.DeepEqual(groupIDs(w), []string{"g-1", "d", "a"}) {
+		t.Errorf("exercises = %v, groups = %v", w.Exercises, groupIDs(w))
+	}
+	if w.Groups[0].Rounds != 3 {
+		t.Errorf("group settings lost: %+v", w.Groups[0])
+	}
+
+	for _, order := range [][]string{
+		{"c", "d", "b", "a"},      // splits the superset
+		{"a", "b", "c"},           // leaves one out
+		{"a", "b", "c", "c"},      // repeats one
+		{"a", "b", "c", "x"},      // names an unknown exercise
+		{"a", "b", "c", "d", "e"}, // too many
+	} {
+		if err := groupedWorkout().Reorder(order); !errors.Is(err, ErrInvalidWorkout) {
+			t.Errorf("Reorder(%v) = %v, want ErrInvalidWorkout", order, err)
+		}
+	}
+}
+
+func TestWorkout_GroupAndUngroup(t *testing.T) {
+	w := groupedWorkout()
+	err := w.Group(ExerciseGroup{GroupID: "g-2", Kind: GroupCircuit, ExerciseIDs: []string{"d", "c", "a"}, Rounds: 4})
+	if err != nil {
+		t.Fatalf("Group: %v", err)
+	}
+	// The circuit takes d's place; the superset it drew c from shrinks to a single.
+	if !reflect.DeepEqual(groupIDs(w), []string{"g-1", "g-2"}) || !reflect.DeepEqual(w.Exercises, []string{"b", "d", "c", "a"}) {
+		t.Errorf("groups = %v, exercises = %v", groupIDs(w), w.Exercises)
+	}
+	if w.Groups[0].Kind != GroupSingle {
+		t.Errorf("shrunk superset kind = %q", w.Groups[0].Kind)
+	}
+	if err := w.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	if err := w.Group(ExerciseGroup{GroupID: "g-3", Kind: GroupSuperset, ExerciseIDs: []string{"a", "x"}}); !errors.Is(err, ErrInvalidWorkout) {
+		t.Errorf("Group with unknown exercise = %v, want ErrInvalidWorkout", err)
+	}
+
+	if err := w.Ungroup("g-2"); err != nil {
+		t.Fatalf("Ungroup: %v", err)
+	}
+	if !reflect.DeepEqual(groupIDs(w), []string{"g-1", "d", "c", "a"}) {
+		t.Errorf("after ungroup groups = %v", groupIDs(w))
+	}
+	if err := w.Ungroup("g-2"); !errors.Is(err, ErrWorkoutGroupNotFound) {
+		t.Errorf("Ungroup missing = %v, want ErrWorkoutGroupNotFound", err)
+	}
+}
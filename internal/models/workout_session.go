@@ -0,0 +1,144 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Workout statuses. A workout without a status is planned.
+const (
+	WorkoutPlanned    = "planned"
+	WorkoutInProgress = "in_progress"
+	WorkoutCompleted  = "completed"
+	WorkoutAbandoned  = "abandoned"
+)
+
+var validWorkoutStatuses = map[string]bool{
+	WorkoutPlanned:    true,
+	WorkoutInProgress: true,
+	WorkoutCompleted:  true,
+	WorkoutAbandoned:  true,
+}
+
+// WorkoutSession records when a workout was actually performed. Its fields
+// are managed by the session transitions below, never set by clients.
+type WorkoutSession struct {
+	Status     string     `json:"status,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	PausedAt   *time.Time `json:"pausedAt,omitempty"` // set while paused
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// PausedSeconds is the time spent paused in earlier pauses.
+	PausedSeconds int `json:"pausedSeconds,omitempty"`
+	// DurationSeconds is the active (unpaused) time, fixed when the workout
+	// is finished or abandoned.
+	DurationSeconds int `json:"durationSeconds,omitempty"`
+}
+
+// CurrentStatus returns the session status, planned when none is recorded.
+func (s WorkoutSession) CurrentStatus() string {
+	if s.Status == "" {
+		return WorkoutPlanned
+	}
+	return s.Status
+}
+
+// ActiveSeconds returns the time spent working out as of now: the recorded
+// duration once the session has ended, otherwise the time since it started
+// less any pauses.
+func (s WorkoutSession) ActiveSeconds(now time.Time) int {
+	if s.StartedAt == nil {
+		return 0
+	}
+	if s.FinishedAt != nil {
+		return s.DurationSeconds
+	}
+	end := now
+	if s.PausedAt != nil {
+		end = *s.PausedAt
+	}
+	active := int(end.Sub(*s.StartedAt).Seconds()) - s.PausedSeconds
+	if active < 0 {
+		return 0
+	}
+	return active
+}
+
+func (s WorkoutSession) transitionError(action string) error {
+	status := s.CurrentStatus()
+	if s.PausedAt != nil {
+		status = "paused"
+	}
+	return fmt.Errorf("%w: cannot %s a %s workout", ErrInvalidTransition, action, status)
+}
+
+// Start begins a planned workout.
+func (s *WorkoutSession) Start(now time.Time) error {
+	if s.CurrentStatus() != WorkoutPlanned {
+		return s.transitionError("start")
+	}
+	*s = WorkoutSession{Status: WorkoutInProgress, StartedAt: &now}
+	return nil
+}
+
+// Pause stops the clock on a running workout.
+func (s *WorkoutSession) Pause(now time.Time) error {
+	if s.CurrentStatus() != WorkoutInProgress || s.PausedAt != nil {
+		return s.transitionError("pause")
+	}
+	s.PausedAt = &now
+	return nil
+}
+
+// Resume restarts the clock on a paused workout.
+func (s *WorkoutSession) Resume(now time.Time) error {
+	if s.CurrentStatus() != WorkoutInProgress || s.PausedAt == nil {
+		return s.transitionError("resume")
+	}
+	s.resume(now)
+	return nil
+}
+
+func (s *WorkoutSession) resume(now time.Time) {
+	if paused := int(now.Sub(*s.PausedAt).Seconds()); paused > 0 {
+		s.PausedSeconds += paused
+	}
+	s.PausedAt = nil
+}
+
+// Finish completes a running or paused workout, fixing its duration. A
+// paused workout's pause ends when it is finished.
+func (s *WorkoutSession) Finish(now time.Time) error {
+	if s.CurrentStatus() != WorkoutInProgress {
+		return s.transitionError("finish")
+	}
+	s.end(WorkoutCompleted, now)
+	return nil
+}
+
+// Abandon gives up on a planned or running workout.
+func (s *WorkoutSession) Abandon(now time.Time) error {
+	switch s.CurrentStatus() {
+	case WorkoutPlanned:
+		*s = WorkoutSession{Status: WorkoutAbandoned, FinishedAt: &now}
+	case WorkoutInProgress:
+		s.end(WorkoutAbandoned, now)
+	default:
+		return s.transitionError("abandon")
+	}
+	return nil
+}
+
+func (s *WorkoutSession) end(status string, now time.Time) {
+	if s.PausedAt != nil {
+		s.resume(now)
+	}
+	s.DurationSeconds = s.ActiveSeconds(now)
+	s.FinishedAt = &now
+	s.Status = status
+}
+
+func (s WorkoutSession) validate(verr *ValidationError) {
+	if s.Status != "" && !validWorkoutStatuses[s.Status] {
+		verr.add("status", "must be one of planned, in_progress, completed, abandoned")
+	}
+}
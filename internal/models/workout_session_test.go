@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWorkoutSession_MeasuresActiveTime(t *testing.T) {
+	start := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	var s WorkoutSession
+	if s.CurrentStatus() != WorkoutPlanned {
+		t.Fatalf("new session status = %q, want planned", s.CurrentStatus())
+	}
+
+	steps := []struct {
+		name string
+		at   time.Duration
+		do   func(*WorkoutSession, time.Time) error
+	}{
+		{"start", 0, (*WorkoutSession).Start},
+		{"pause", 20 * time.Minute, (*WorkoutSession).Pause},
+		{"resume", 25 * time.Minute, (*WorkoutSession).Resume},
+		{"pause", 50 * time.Minute, (*WorkoutSession).Pause},
+		{"finish", 60 * time.Minute, (*WorkoutSession).Finish},
+	}
+	for _, step := range steps {
+		if err := step.do(&s, start.Add(step.at)); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+
+	if s.Status != WorkoutCompleted || s.PausedAt != nil || s.FinishedAt == nil || !s.FinishedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("finished session = %+v", s)
+	}
+	// 60 minutes less 5 and 10 minutes paused.
+	if s.DurationSeconds != 45*60 || s.PausedSeconds != 15*60 {
+		t.Errorf("duration = %d, paused = %d, want 2700 and 900", s.DurationSeconds, s.PausedSeconds)
+	}
+	if got := s.ActiveSeconds(start.Add(2 * time.Hour)); got != 45*60 {
+		t.Errorf("ActiveSeconds after finish = %d, want 2700", got)
+	}
+}
+
+func TestWorkoutSession_ActiveSecondsWhilePaused(t *testing.T) {
+	start := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	var s WorkoutSession
+	_ = s.Start(start)
+	_ = s.Pause(start.Add(10 * time.Minute))
+
+	if got := s.ActiveSeconds(start.Add(30 * time.Minute)); got != 600 {
+		t.Errorf("ActiveSeconds while paused = %d, want 600", got)
+	}
+}
+
+func TestWorkoutSession_RejectsInvalidTransitions(t *testing.T) {
+	now := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	running := WorkoutSession{Status: WorkoutInProgress, StartedAt: &now}
+	paused := WorkoutSession{Status: WorkoutInProgress, StartedAt: &now, PausedAt: &now}
+	completed := WorkoutSession{Status: WorkoutCompleted, StartedAt: &now, FinishedAt: &now}
+
+	cases := []struct {
+		name    string
+		session WorkoutSession
+		do      func(*WorkoutSession, time.Time) error
+	}{
+		{"pause planned", WorkoutSession{}, (*WorkoutSession).Pause},
+		{"finish planned", WorkoutSession{}, (*WorkoutSession).Finish},
+		{"start running", running, (*WorkoutSession).Start},
+		{"resume running", running, (*WorkoutSession).Resume},
+		{"pause paused", paused, (*WorkoutSession).Pause},
+		{"start completed", completed, (*WorkoutSession).Start},
+		{"abandon completed", completed, (*WorkoutSession).Abandon},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.session
+			if err := tc.do(&s, now); !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("err = %v, want ErrInvalidTransition", err)
+			}
+		})
+	}
+}
+
+func TestWorkoutSession_AbandonPlanned(t *testing.T) {
+	now := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	var s WorkoutSession
+	if err := s.Abandon(now); err != nil {
+		t.Fatalf("Abandon: %v", err)
+	}
+	if s.Status != WorkoutAbandoned || s.StartedAt != nil || s.DurationSeconds != 0 {
+		t.Errorf("abandoned session = %+v", s)
+	}
+}
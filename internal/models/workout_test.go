@@ -0,0 +1,43 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWorkoutValidate_FieldErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		workout Workout
+		fields  []string
+	}{
+		{"valid", Workout{UserID: "u", WorkoutID: "w", Name: "Push", Date: "2024-01-15"}, nil},
+		{"empty", Workout{}, []string{"userId", "workoutId", "name", "date"}},
+		{"bad date", Workout{UserID: "u", WorkoutID: "w", Name: "Push", Date: "15/01/2024"}, []string{"date"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.workout.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidWorkout) {
+				t.Fatalf("err = %v, want *ValidationError wrapping ErrInvalidWorkout", err)
+			}
+			if len(verr.Fields) != len(tt.fields) {
+				t.Fatalf("Fields = %+v, want %v", verr.Fields, tt.fields)
+			}
+			for i, field := range tt.fields {
+				if verr.Fields[i].Field != field {
+					t.Errorf("Fields[%d] = %q, want %q", i, verr.Fields[i].Field, field)
+				}
+			}
+		})
+	}
+}
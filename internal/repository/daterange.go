@@ -0,0 +1,31 @@
+package repository
+
+// DateRange is an inclusive range of Workout.Date values. Either bound may be
+// empty to leave that side open. Dates are compared as strings, which orders
+// ISO-8601 values chronologically.
+type DateRange struct {
+	From string
+	To   string
+}
+
+// UpperBound returns the largest string still inside the range. A date-only To
+// bound such as 2024-05-31 must also match timestamps on that day
+// (2024-05-31T18:30:00Z), so "~" (sorting after every timestamp character) is
+// appended to it.
+func (d DateRange) UpperBound() string {
+	if d.To == "" {
+		return ""
+	}
+	return d.To + "~"
+}
+
+// Contains reports whether date falls within the range.
+func (d DateRange) Contains(date string) bool {
+	if d.From != "" && date < d.From {
+		return false
+	}
+	if d.To != "" && date > d.UpperBound() {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package db
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/repository/repotest"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// These tests run against DynamoDB Local (or any compatible stand-in) at
+// DYNAMODB_ENDPOINT, e.g.
+//
+//	docker run -p 8000:8000 amazon/dynamodb-local
+//	DYNAMODB_ENDPOINT=http://localhost:8000 go test ./internal/repository/db
+//
+// Each subtest gets its own tables, mirroring terraform/dynamodb.tf.
+func localDynamo(t *testing.T) *dynamodb.DynamoDB {
+	t.Helper()
+	endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DYNAMODB_ENDPOINT not set; skipping DynamoDB conformance tests")
+	}
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(endpoint),
+		Credentials: credentials.NewStaticCredentials("local", "local", ""),
+	}))
+	return dynamodb.New(sess)
+}
+
+func createTable(t *testing.T, client *dynamodb.DynamoDB, prefix string, input *dynamodb.CreateTableInput) string {
+	t.Helper()
+	suffix := make([]byte, 6)
+	rand.Read(suffix)
+	name := prefix + "-" + hex.EncodeToString(suffix)
+
+	input.TableName = aws.String(name)
+	input.BillingMode = aws.String(dynamodb.BillingModePayPerRequest)
+	if _, err := client.CreateTable(input); err != nil {
+		t.Fatalf("create table %s: %v", name, err)
+	}
+	if err := client.WaitUntilTableExists(&dynamodb.DescribeTableInput{TableName: aws.String(name)}); err != nil {
+		t.Fatalf("wait for table %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		client.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(name)})
+	})
+	return name
+}
+
+func stringAttrs(names ...string) []*dynamodb.AttributeDefinition {
+	attrs := make([]*dynamodb.AttributeDefinition, len(names))
+	for i, name := range names {
+		attrs[i] = &dynamodb.AttributeDefinition{AttributeName: aws.String(name), AttributeType: aws.String(dynamodb.ScalarAttributeTypeS)}
+	}
+	return attrs
+}
+
+func keySchema(hash, rang string) []*dynamodb.KeySchemaElement {
+	return []*dynamodb.KeySchemaElement{
+		{AttributeName: aws.String(hash), KeyType: aws.String(dynamodb.KeyTypeHash)},
+		{AttributeName: aws.String(rang), KeyType: aws.String(dynamodb.KeyTypeRange)},
+	}
+}
+
+func index(name, hash, rang string) *dynamodb.GlobalSecondaryIndex {
+	return &dynamodb.GlobalSecondaryIndex{
+		IndexName:  aws.String(name),
+		KeySchema:  keySchema(hash, rang),
+		Projection: &dynamodb.Projection{ProjectionType: aws.String(dynamodb.ProjectionTypeAll)},
+	}
+}
+
+func keysOnlyIndex(name, hash, rang string) *dynamodb.GlobalSecondaryIndex {
+	idx := index(name, hash, rang)
+	idx.Projection.ProjectionType = aws.String(dynamodb.ProjectionTypeKeysOnly)
+	return idx
+}
+
+func createWorkoutsTable(t *testing.T, client *dynamodb.DynamoDB) string {
+	return createTable(t, client, "Workouts", &dynamodb.CreateTableInput{
+		AttributeDefinitions:   stringAttrs("UserID", "WorkoutID", "date"),
+		KeySchema:              keySchema("UserID", "WorkoutID"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{index("WorkoutDateIndex", "UserID", "date")},
+	})
+}
+
+func createExercisesTable(t *testing.T, client *dynamodb.DynamoDB) string {
+	return createTable(t, client, "Exercises", &dynamodb.CreateTableInput{
+		AttributeDefinitions: stringAttrs("UserID", "ExerciseID", "UserType", "NameKey"),
+		KeySchema:            keySchema("UserID", "ExerciseID"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			index("UserExerciseTypeIndex", "UserType", "NameKey"),
+			index("ExerciseNameIndex", "UserID", "NameKey"),
+		},
+	})
+}
+
+func TestDynamoWorkoutRepository_Conformance(t *testing.T) {
+	client := localDynamo(t)
+	repotest.RunWorkoutRepositoryTests(t, func(t *testing.T) repository.WorkoutRepository {
+		return NewDynamoWorkoutRepository(client, createWorkoutsTable(t, client), time.Hour)
+	})
+}
+
+func TestDynamoExerciseRepository_Conformance(t *testing.T) {
+	client := localDynamo(t)
+	repotest.RunExerciseRepositoryTests(t, func(t *testing.T) repository.ExerciseRepository {
+		return NewDynamoExerciseRepository(client, createExercisesTable(t, client), time.Hour)
+	})
+}
+
+func TestDynamoTrashExpiry_Conformance(t *testing.T) {
+	client := localDynamo(t)
+	repotest.RunTrashExpiryTests(t,
+		func(t *testing.T, retention time.Duration) repository.WorkoutRepository {
+			return NewDynamoWorkoutRepository(client, createWorkoutsTable(t, client), retention)
+		},
+		func(t *testing.T, retention time.Duration) repository.ExerciseRepository {
+			return NewDynamoExerciseRepository(client, createExercisesTable(t, client), retention)
+		})
+}
+
+func TestDynamoTemplateRepository_Conformance(t *testing.T) {
+	client := localDynamo(t)
+	repotest.RunTemplateRepositoryTests(t, func(t *testing.T) repository.TemplateRepository {
+		table := createTable(t, client, "Templates", &dynamodb.CreateTableInput{
+			AttributeDefinitions: stringAttrs("UserID", "TemplateID"),
+			KeySchema:            keySchema("UserID", "TemplateID"),
+		})
+		return NewDynamoTemplateRepository(client, table)
+	})
+}
+
+func TestDynamoProgramRepository_Conformance(t *testing.T) {
+	client := localDynamo(t)
+	repotest.RunProgramRepositoryTests(t, func(t *testing.T) repository.ProgramRepository {
+		table := createTable(t, client, "Programs", &dynamodb.CreateTableInput{
+			AttributeDefinitions: stringAttrs("UserID", "ProgramID"),
+			KeySchema:            keySchema("UserID", "ProgramID"),
+		})
+		return NewDynamoProgramRepository(client, table)
+	})
+}
+
+func TestDynamoRecordRepository_Conformance(t *testing.T) {
+	client := localDynamo(t)
+	repotest.RunRecordRepositoryTests(t, func(t *testing.T) repository.RecordRepository {
+		table := createTable(t, client, "Records", &dynamodb.CreateTableInput{
+			AttributeDefinitions: stringAttrs("UserID", "RecordID", "NameKey", "exerciseId"),
+			KeySchema:            keySchema("UserID", "RecordID"),
+			GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+				index("RecordNameIndex", "UserID", "NameKey"),
+				keysOnlyIndex("RecordExerciseIndex", "UserID", "exerciseId"),
+			},
+		})
+		return NewDynamoRecordRepository(client, table)
+	})
+}
+
+func TestDynamoProfileRepository_Conformance(t *testing.T) {
+	client := localDynamo(t)
+	repotest.RunProfileRepositoryTests(t, func(t *testing.T) repository.ProfileRepository {
+		table := createTable(t, client, "Profiles", &dynamodb.CreateTableInput{
+			AttributeDefinitions: stringAttrs("UserID"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("UserID"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+		})
+		return NewDynamoProfileRepository(client, table)
+	})
+}
+
+func TestDynamoMeasurementRepository_Conformance(t *testing.T) {
+	client := localDynamo(t)
+	repotest.RunMeasurementRepositoryTests(t, func(t *testing.T) repository.MeasurementRepository {
+		table := createTable(t, client, "Measurements", &dynamodb.CreateTableInput{
+			AttributeDefinitions:   stringAttrs("UserID", "MeasurementID", "date"),
+			KeySchema:              keySchema("UserID", "MeasurementID"),
+			GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{index("MeasurementDateIndex", "UserID", "date")},
+		})
+		return NewDynamoMeasurementRepository(client, table)
+	})
+}
@@ -0,0 +1,15 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// isConditionalCheckFailed reports whether a write was rejected by its
+// ConditionExpression, i.e. the item did (or did not) already exist.
+func isConditionalCheckFailed(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+}
@@ -2,8 +2,10 @@ package db
 
 import (
 	"fmt"
+	"time"
 
 	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
@@ -13,33 +15,30 @@ import (
 type DynamoExerciseRepository struct {
 	db        *dynamodb.DynamoDB
 	tableName string
+	retention time.Duration
 }
 
-func NewDynamoExerciseRepository(db *dynamodb.DynamoDB, tableName string) *DynamoExerciseRepository {
+// NewDynamoExerciseRepository returns a repository on tableName. Deleted
+// exercises expire through the table's TTL after retention.
+func NewDynamoExerciseRepository(db *dynamodb.DynamoDB, tableName string, retention time.Duration) *DynamoExerciseRepository {
 	return &DynamoExerciseRepository{
 		db:        db,
 		tableName: tableName,
+		retention: retention,
 	}
 }
 
 func (r *DynamoExerciseRepository) GetByID(userID, exerciseID string) (*models.Exercise, error) {
 	result, err := r.db.GetItem(&dynamodb.GetItemInput{
 		TableName: aws.String(r.tableName),
-		Key: map[string]*dynamodb.AttributeValue{
-			"UserID": {
-				S: aws.String(userID),
-			},
-			"ExerciseID": {
-				S: aws.String(exerciseID),
-			},
-		},
+		Key:       exerciseKey(userID, exerciseID),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exercise: %w", err)
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("exercise not found")
+		return nil, models.ErrExerciseNotFound
 	}
 
 	var exercise models.Exercise
@@ -47,12 +46,71 @@ func (r *DynamoExerciseRepository) GetByID(userID, exerciseID string) (*models.E
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal exercise: %w", err)
 	}
+	if exercise.DeletedAt != nil {
+		return nil, models.ErrExerciseNotFound
+	}
 
 	return &exercise, nil
 }
 
-func (r *DynamoExerciseRepository) ListByUserID(userID string) ([]*models.Exercise, error) {
-	result, err := r.db.Query(&dynamodb.QueryInput{
+// batchGetLimit is the maximum number of keys DynamoDB accepts per BatchGetItem call.
+const batchGetLimit = 100
+
+// GetMany fetches exercises with BatchGetItem, 100 keys at a time, retrying any
+// keys DynamoDB reports as unprocessed. IDs that do not exist, or are in the
+// trash, are skipped.
+func (r *DynamoExerciseRepository) GetMany(userID string, exerciseIDs []string) ([]*models.Exercise, error) {
+	seen := make(map[string]bool, len(exerciseIDs))
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(exerciseIDs))
+	for _, id := range exerciseIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		keys = append(keys, exerciseKey(userID, id))
+	}
+
+	exercises := make([]*models.Exercise, 0, len(keys))
+	for start := 0; start < len(keys); start += batchGetLimit {
+		end := start + batchGetLimit
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		request := map[string]*dynamodb.KeysAndAttributes{
+			r.tableName: {Keys: keys[start:end]},
+		}
+		for attempt := 0; len(request) > 0; attempt++ {
+			if attempt > 0 {
+				if attempt > 5 {
+					return nil, fmt.Errorf("failed to get exercises: unprocessed keys remain after %d attempts", attempt)
+				}
+				time.Sleep(time.Duration(1<<attempt) * 25 * time.Millisecond)
+			}
+
+			result, err := r.db.BatchGetItem(&dynamodb.BatchGetItemInput{RequestItems: request})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get exercises: %w", err)
+			}
+
+			var batch []*models.Exercise
+			if err := dynamodbattribute.UnmarshalListOfMaps(result.Responses[r.tableName], &batch); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal exercises: %w", err)
+			}
+			for _, exercise := range batch {
+				if exercise.DeletedAt == nil {
+					exercises = append(exercises, exercise)
+				}
+			}
+			request = result.UnprocessedKeys
+		}
+	}
+
+	return exercises, nil
+}
+
+func (r *DynamoExerciseRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	return r.queryPage(&dynamodb.QueryInput{
 		TableName:              aws.String(r.tableName),
 		KeyConditionExpression: aws.String("UserID = :userID"),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
@@ -60,34 +118,76 @@ func (r *DynamoExerciseRepository) ListByUserID(userID string) ([]*models.Exerci
 				S: aws.String(userID),
 			},
 		},
-	})
+		ScanIndexForward: aws.Bool(!opts.Descending),
+	}, userID, opts)
+}
+
+func (r *DynamoExerciseRepository) queryPage(input *dynamodb.QueryInput, userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	if err := applyListOptions(input, userID, opts); err != nil {
+		return nil, err
+	}
+	filterLive(input)
+
+	result, err := r.db.Query(input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list exercises: %w", err)
 	}
 
-	var exercises []*models.Exercise
+	exercises := make([]*models.Exercise, 0, len(result.Items))
 	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &exercises)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal exercises: %w", err)
 	}
 
-	return exercises, nil
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Page[*models.Exercise]{Items: exercises, NextCursor: nextCursor}, nil
 }
 
-func (r *DynamoExerciseRepository) Create(userID string, exercise *models.Exercise) error {
+// marshalExercise builds the stored item for an exercise, including the
+// derived index keys that are not part of the model. Only Delete moves
+// exercises to the trash, so a DeletedAt sent by the caller is dropped.
+func marshalExercise(userID string, exercise *models.Exercise) (map[string]*dynamodb.AttributeValue, error) {
 	av, err := dynamodbattribute.MarshalMap(exercise)
 	if err != nil {
-		return fmt.Errorf("failed to marshal exercise: %w", err)
+		return nil, fmt.Errorf("failed to marshal exercise: %w", err)
 	}
+	delete(av, "deletedAt")
 
 	av["UserID"] = &dynamodb.AttributeValue{
 		S: aws.String(userID),
 	}
+	av["NameKey"] = &dynamodb.AttributeValue{
+		S: aws.String(models.NormalizeName(exercise.Name)),
+	}
+	av["UserType"] = &dynamodb.AttributeValue{
+		S: aws.String(userTypeKey(userID, exercise.ExerciseType)),
+	}
+
+	return av, nil
+}
+
+func (r *DynamoExerciseRepository) Create(userID string, exercise *models.Exercise) error {
+	if exercise.CreatedAt.IsZero() {
+		exercise.CreatedAt = time.Now().UTC()
+	}
+
+	av, err := marshalExercise(userID, exercise)
+	if err != nil {
+		return err
+	}
 
 	_, err = r.db.PutItem(&dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      av,
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(UserID) AND attribute_not_exists(ExerciseID)"),
 	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrExerciseAlreadyExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create exercise: %w", err)
 	}
@@ -96,24 +196,19 @@ func (r *DynamoExerciseRepository) Create(userID string, exercise *models.Exerci
 }
 
 func (r *DynamoExerciseRepository) Update(userID string, exercise *models.Exercise) error {
-	_, err := r.GetByID(userID, exercise.ExerciseID)
-	if err != nil {
-		return fmt.Errorf("exercise not found: %w", err)
-	}
-
-	av, err := dynamodbattribute.MarshalMap(exercise)
+	av, err := marshalExercise(userID, exercise)
 	if err != nil {
-		return fmt.Errorf("failed to marshal exercise: %w", err)
-	}
-
-	av["UserID"] = &dynamodb.AttributeValue{
-		S: aws.String(userID),
+		return err
 	}
 
 	_, err = r.db.PutItem(&dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      av,
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_exists(UserID) AND attribute_exists(ExerciseID) AND " + liveFilter),
 	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrExerciseNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update exercise: %w", err)
 	}
@@ -121,23 +216,12 @@ func (r *DynamoExerciseRepository) Update(userID string, exercise *models.Exerci
 	return nil
 }
 
+// Delete moves the exercise to the trash.
 func (r *DynamoExerciseRepository) Delete(userID, exerciseID string) error {
-	_, err := r.GetByID(userID, exerciseID)
-	if err != nil {
-		return fmt.Errorf("exercise not found: %w", err)
+	_, err := r.db.UpdateItem(trashUpdate(r.tableName, exerciseKey(userID, exerciseID), r.retention))
+	if isConditionalCheckFailed(err) {
+		return models.ErrExerciseNotFound
 	}
-
-	_, err = r.db.DeleteItem(&dynamodb.DeleteItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]*dynamodb.AttributeValue{
-			"UserID": {
-				S: aws.String(userID),
-			},
-			"ExerciseID": {
-				S: aws.String(exerciseID),
-			},
-		},
-	})
 	if err != nil {
 		return fmt.Errorf("failed to delete exercise: %w", err)
 	}
@@ -145,62 +229,112 @@ func (r *DynamoExerciseRepository) Delete(userID, exerciseID string) error {
 	return nil
 }
 
-func (r *DynamoExerciseRepository) ListByType(userID, exerciseType string) ([]*models.Exercise, error) {
-	queryInput := &dynamodb.QueryInput{
+func (r *DynamoExerciseRepository) ListDeleted(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	return r.queryPage(&dynamodb.QueryInput{
 		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("ExerciseTypeIndex"),
-		KeyConditionExpression: aws.String("ExerciseType = :exerciseType"),
-		FilterExpression:       aws.String("UserID = :userID"),
+		KeyConditionExpression: aws.String("UserID = :userID"),
+		FilterExpression:       aws.String(deletedFilter),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":exerciseType": {
-				S: aws.String(exerciseType),
-			},
 			":userID": {
 				S: aws.String(userID),
 			},
+			":now": trashNow(),
 		},
-	}
+		ScanIndexForward: aws.Bool(!opts.Descending),
+	}, userID, opts)
+}
 
-	result, err := r.db.Query(queryInput)
+func (r *DynamoExerciseRepository) Restore(userID, exerciseID string) error {
+	_, err := r.db.UpdateItem(restoreUpdate(r.tableName, exerciseKey(userID, exerciseID)))
+	if isConditionalCheckFailed(err) {
+		return models.ErrExerciseNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to list exercises by exerciseType: %w", err)
+		return fmt.Errorf("failed to restore exercise: %w", err)
 	}
 
-	var exercises []*models.Exercise
-	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &exercises)
+	return nil
+}
+
+func (r *DynamoExerciseRepository) Purge(userID, exerciseID string) error {
+	_, err := r.db.DeleteItem(purgeDelete(r.tableName, exerciseKey(userID, exerciseID)))
+	if isConditionalCheckFailed(err) {
+		return models.ErrExerciseNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal exercises: %w", err)
+		return fmt.Errorf("failed to purge exercise: %w", err)
 	}
 
-	return exercises, nil
+	return nil
 }
 
-func (r *DynamoExerciseRepository) ListByName(userID, exerciseName string) ([]*models.Exercise, error) {
-	queryInput := &dynamodb.QueryInput{
+func exerciseKey(userID, exerciseID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"UserID": {
+			S: aws.String(userID),
+		},
+		"ExerciseID": {
+			S: aws.String(exerciseID),
+		},
+	}
+}
+
+// ListByType queries UserExerciseTypeIndex, whose partition key combines the
+// user and exercise type, so only the caller's items are read.
+func (r *DynamoExerciseRepository) ListByType(userID, exerciseType string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	return r.queryPage(&dynamodb.QueryInput{
 		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("ExerciseTypeIndex"),
-		KeyConditionExpression: aws.String("name = :name"),
-		FilterExpression:       aws.String("UserID = :userID"),
+		IndexName:              aws.String("UserExerciseTypeIndex"),
+		KeyConditionExpression: aws.String("UserType = :userType"),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":name": {
-				S: aws.String(exerciseName),
-			},
-			":userID": {
-				S: aws.String(userID),
+			":userType": {
+				S: aws.String(userTypeKey(userID, exerciseType)),
 			},
 		},
-	}
+		ScanIndexForward: aws.Bool(!opts.Descending),
+	}, userID, opts)
+}
 
-	result, err := r.db.Query(queryInput)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list exercises by exerciseType: %w", err)
-	}
+// userTypeKey is the UserExerciseTypeIndex partition key, e.g. "abc-123#cardio".
+func userTypeKey(userID, exerciseType string) string {
+	return userID + "#" + exerciseType
+}
 
-	var exercises []*models.Exercise
-	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &exercises)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal exercises: %w", err)
-	}
+// ListByName returns every exercise whose normalized name matches exerciseName,
+// using ExerciseNameIndex (UserID + NameKey).
+func (r *DynamoExerciseRepository) ListByName(userID, exerciseName string) ([]*models.Exercise, error) {
+	return repository.CollectAll(func(opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+		return r.queryPage(&dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String("ExerciseNameIndex"),
+			KeyConditionExpression: aws.String("UserID = :userID AND NameKey = :nameKey"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":userID": {
+					S: aws.String(userID),
+				},
+				":nameKey": {
+					S: aws.String(models.NormalizeName(exerciseName)),
+				},
+			},
+		}, userID, opts)
+	})
+}
 
-	return exercises, nil
+// ListByNamePrefix returns exercises whose normalized name starts with prefix,
+// ordered by name, for autocomplete.
+func (r *DynamoExerciseRepository) ListByNamePrefix(userID, prefix string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	return r.queryPage(&dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("ExerciseNameIndex"),
+		KeyConditionExpression: aws.String("UserID = :userID AND begins_with(NameKey, :prefix)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":userID": {
+				S: aws.String(userID),
+			},
+			":prefix": {
+				S: aws.String(models.NormalizeName(prefix)),
+			},
+		},
+		ScanIndexForward: aws.Bool(!opts.Descending),
+	}, userID, opts)
 }
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// DynamoMeasurementRepository stores each measurement as a single item keyed by
+// UserID and MeasurementID, with MeasurementDateIndex (UserID + date) for
+// date-ordered listing.
+type DynamoMeasurementRepository struct {
+	db        *dynamodb.DynamoDB
+	tableName string
+}
+
+func NewDynamoMeasurementRepository(db *dynamodb.DynamoDB, tableName string) *DynamoMeasurementRepository {
+	return &DynamoMeasurementRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+func (r *DynamoMeasurementRepository) key(userID, measurementID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"UserID": {
+			S: aws.String(userID),
+		},
+		"MeasurementID": {
+			S: aws.String(measurementID),
+		},
+	}
+}
+
+func (r *DynamoMeasurementRepository) GetByID(userID, measurementID string) (*models.Measurement, error) {
+	result, err := r.db.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       r.key(userID, measurementID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurement: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, models.ErrMeasurementNotFound
+	}
+
+	var measurement models.Measurement
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &measurement); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal measurement: %w", err)
+	}
+
+	return &measurement, nil
+}
+
+// ListByDateRange queries MeasurementDateIndex (UserID + date) so results come
+// back sorted by measurement date.
+func (r *DynamoMeasurementRepository) ListByDateRange(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Measurement], error) {
+	keyCondition := "UserID = :userID"
+	values := map[string]*dynamodb.AttributeValue{
+		":userID": {
+			S: aws.String(userID),
+		},
+	}
+
+	switch {
+	case dateRange.From != "" && dateRange.To != "":
+		keyCondition += " AND #date BETWEEN :from AND :to"
+	case dateRange.From != "":
+		keyCondition += " AND #date >= :from"
+	case dateRange.To != "":
+		keyCondition += " AND #date <= :to"
+	}
+	if dateRange.From != "" {
+		values[":from"] = &dynamodb.AttributeValue{S: aws.String(dateRange.From)}
+	}
+	if dateRange.To != "" {
+		values[":to"] = &dynamodb.AttributeValue{S: aws.String(dateRange.UpperBound())}
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String("MeasurementDateIndex"),
+		KeyConditionExpression:    aws.String(keyCondition),
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          aws.Bool(!opts.Descending),
+	}
+	// date is a DynamoDB reserved word.
+	if dateRange.From != "" || dateRange.To != "" {
+		input.ExpressionAttributeNames = map[string]*string{"#date": aws.String("date")}
+	}
+	if err := applyListOptions(input, userID, opts); err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.Query(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query measurements: %w", err)
+	}
+
+	measurements := make([]*models.Measurement, 0, len(result.Items))
+	if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, &measurements); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal measurements: %w", err)
+	}
+
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Page[*models.Measurement]{Items: measurements, NextCursor: nextCursor}, nil
+}
+
+func (r *DynamoMeasurementRepository) Create(measurement *models.Measurement) error {
+	if measurement.CreatedAt.IsZero() {
+		measurement.CreatedAt = time.Now()
+	}
+
+	item, err := dynamodbattribute.MarshalMap(measurement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal measurement: %w", err)
+	}
+
+	_, err = r.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(UserID) AND attribute_not_exists(MeasurementID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrMeasurementAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create measurement: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoMeasurementRepository) Update(measurement *models.Measurement) error {
+	item, err := dynamodbattribute.MarshalMap(measurement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal measurement: %w", err)
+	}
+
+	_, err = r.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(UserID) AND attribute_exists(MeasurementID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrMeasurementNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update measurement: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoMeasurementRepository) Delete(userID, measurementID string) error {
+	_, err := r.db.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 r.key(userID, measurementID),
+		ConditionExpression: aws.String("attribute_exists(UserID) AND attribute_exists(MeasurementID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrMeasurementNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete measurement: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// encodeCursor turns a query's LastEvaluatedKey into an opaque cursor string.
+func encodeCursor(lastKey map[string]*dynamodb.AttributeValue) (string, error) {
+	if len(lastKey) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(lastKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor turns a cursor back into an ExclusiveStartKey. The key must belong
+// to userID so a cursor cannot be replayed against another user's partition.
+func decodeCursor(cursor, userID string) (map[string]*dynamodb.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+	var key map[string]*dynamodb.AttributeValue
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+	if owner, ok := key["UserID"]; !ok || aws.StringValue(owner.S) != userID {
+		return nil, models.ErrInvalidCursor
+	}
+	return key, nil
+}
+
+// applyListOptions sets the page size and start key of a query.
+func applyListOptions(input *dynamodb.QueryInput, userID string, opts repository.ListOptions) error {
+	startKey, err := decodeCursor(opts.Cursor, userID)
+	if err != nil {
+		return err
+	}
+	input.ExclusiveStartKey = startKey
+	if opts.Limit > 0 {
+		input.Limit = aws.Int64(int64(opts.Limit))
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"gym-tracker-api/internal/models"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// DynamoProfileRepository stores one item per user, keyed by UserID alone.
+type DynamoProfileRepository struct {
+	db        *dynamodb.DynamoDB
+	tableName string
+}
+
+func NewDynamoProfileRepository(db *dynamodb.DynamoDB, tableName string) *DynamoProfileRepository {
+	return &DynamoProfileRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+func (r *DynamoProfileRepository) key(userID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"UserID": {
+			S: aws.String(userID),
+		},
+	}
+}
+
+func (r *DynamoProfileRepository) GetByUserID(userID string) (*models.UserProfile, error) {
+	result, err := r.db.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       r.key(userID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, models.ErrProfileNotFound
+	}
+
+	var profile models.UserProfile
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &profile); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+func (r *DynamoProfileRepository) Create(profile *models.UserProfile) error {
+	if profile.CreatedAt.IsZero() {
+		profile.CreatedAt = time.Now()
+	}
+	if profile.UpdatedAt.IsZero() {
+		profile.UpdatedAt = profile.CreatedAt
+	}
+
+	item, err := dynamodbattribute.MarshalMap(profile)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile: %w", err)
+	}
+
+	_, err = r.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(UserID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrProfileAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create profile: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoProfileRepository) Update(profile *models.UserProfile) error {
+	item, err := dynamodbattribute.MarshalMap(profile)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile: %w", err)
+	}
+
+	_, err = r.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(UserID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrProfileNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoProfileRepository) Delete(userID string) error {
+	_, err := r.db.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 r.key(userID),
+		ConditionExpression: aws.String("attribute_exists(UserID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrProfileNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+
+	return nil
+}
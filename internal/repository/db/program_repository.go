@@ -0,0 +1,171 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// DynamoProgramRepository stores each program, weeks and enrollment included, as a
+// single item keyed by UserID and ProgramID.
+type DynamoProgramRepository struct {
+	db        *dynamodb.DynamoDB
+	tableName string
+}
+
+func NewDynamoProgramRepository(db *dynamodb.DynamoDB, tableName string) *DynamoProgramRepository {
+	return &DynamoProgramRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+func (r *DynamoProgramRepository) key(userID, programID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"UserID": {
+			S: aws.String(userID),
+		},
+		"ProgramID": {
+			S: aws.String(programID),
+		},
+	}
+}
+
+func (r *DynamoProgramRepository) GetByID(userID, programID string) (*models.Program, error) {
+	result, err := r.db.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       r.key(userID, programID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, models.ErrProgramNotFound
+	}
+
+	var program models.Program
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &program); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal program: %w", err)
+	}
+
+	return &program, nil
+}
+
+func (r *DynamoProgramRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Program], error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("UserID = :userID"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":userID": {
+				S: aws.String(userID),
+			},
+		},
+		ScanIndexForward: aws.Bool(!opts.Descending),
+	}
+	if err := applyListOptions(input, userID, opts); err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.Query(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query programs: %w", err)
+	}
+
+	programs := make([]*models.Program, 0, len(result.Items))
+	if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, &programs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal programs: %w", err)
+	}
+
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Page[*models.Program]{Items: programs, NextCursor: nextCursor}, nil
+}
+
+func (r *DynamoProgramRepository) Create(program *models.Program) error {
+	if program.CreatedAt.IsZero() {
+		program.CreatedAt = time.Now()
+	}
+
+	item, err := dynamodbattribute.MarshalMap(program)
+	if err != nil {
+		return fmt.Errorf("failed to marshal program: %w", err)
+	}
+
+	_, err = r.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(UserID) AND attribute_not_exists(ProgramID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrProgramAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create program: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the program only while its stored version matches, so two
+// requests that read the same program cannot both write it. Items stored
+// before versions existed count as version 0.
+func (r *DynamoProgramRepository) Update(program *models.Program) error {
+	next := *program
+	next.Version++
+	item, err := dynamodbattribute.MarshalMap(&next)
+	if err != nil {
+		return fmt.Errorf("failed to marshal program: %w", err)
+	}
+
+	condition := "attribute_exists(UserID) AND attribute_exists(ProgramID) AND version = :version"
+	if program.Version == 0 {
+		condition = "attribute_exists(UserID) AND attribute_exists(ProgramID) AND (attribute_not_exists(version) OR version = :version)"
+	}
+	_, err = r.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String(condition),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":version": {N: aws.String(strconv.Itoa(program.Version))},
+		},
+	})
+	if isConditionalCheckFailed(err) {
+		if _, err := r.GetByID(program.UserID, program.ProgramID); err != nil {
+			return err
+		}
+		return models.ErrProgramConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update program: %w", err)
+	}
+
+	program.Version = next.Version
+	return nil
+}
+
+func (r *DynamoProgramRepository) Delete(userID, programID string) error {
+	_, err := r.db.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 r.key(userID, programID),
+		ConditionExpression: aws.String("attribute_exists(UserID) AND attribute_exists(ProgramID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrProgramNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete program: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"fmt"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// DynamoRecordRepository stores one item per personal record, keyed by
+// UserID and RecordID. RecordNameIndex (UserID + NameKey) serves the
+// per-exercise history and RecordExerciseIndex (UserID + exerciseId) finds the
+// records one exercise set.
+type DynamoRecordRepository struct {
+	db        *dynamodb.DynamoDB
+	tableName string
+}
+
+func NewDynamoRecordRepository(db *dynamodb.DynamoDB, tableName string) *DynamoRecordRepository {
+	return &DynamoRecordRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+func (r *DynamoRecordRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.PersonalRecord], error) {
+	return r.queryPage(&dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("UserID = :userID"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":userID": {
+				S: aws.String(userID),
+			},
+		},
+		ScanIndexForward: aws.Bool(!opts.Descending),
+	}, userID, opts)
+}
+
+func (r *DynamoRecordRepository) ListByName(userID, exerciseName string) ([]*models.PersonalRecord, error) {
+	records, err := repository.CollectAll(func(opts repository.ListOptions) (*repository.Page[*models.PersonalRecord], error) {
+		return r.queryPage(&dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String("RecordNameIndex"),
+			KeyConditionExpression: aws.String("UserID = :userID AND NameKey = :nameKey"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":userID": {
+					S: aws.String(userID),
+				},
+				":nameKey": {
+					S: aws.String(models.NormalizeName(exerciseName)),
+				},
+			},
+		}, userID, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	models.SortRecords(records)
+	return records, nil
+}
+
+func (r *DynamoRecordRepository) queryPage(input *dynamodb.QueryInput, userID string, opts repository.ListOptions) (*repository.Page[*models.PersonalRecord], error) {
+	if err := applyListOptions(input, userID, opts); err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.Query(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query records: %w", err)
+	}
+
+	records := make([]*models.PersonalRecord, 0, len(result.Items))
+	if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, &records); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal records: %w", err)
+	}
+
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Page[*models.PersonalRecord]{Items: records, NextCursor: nextCursor}, nil
+}
+
+func (r *DynamoRecordRepository) Create(record *models.PersonalRecord) error {
+	item, err := dynamodbattribute.MarshalMap(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	item["NameKey"] = &dynamodb.AttributeValue{
+		S: aws.String(models.NormalizeName(record.ExerciseName)),
+	}
+
+	_, err = r.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(UserID) AND attribute_not_exists(RecordID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrRecordAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create record: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByExercise looks up the exercise's records in RecordExerciseIndex,
+// which projects only keys, and deletes each one. It reads only the records
+// being deleted, however many the user holds.
+func (r *DynamoRecordRepository) DeleteByExercise(userID, exerciseID string) error {
+	records, err := repository.CollectAll(func(opts repository.ListOptions) (*repository.Page[*models.PersonalRecord], error) {
+		return r.queryPage(&dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String("RecordExerciseIndex"),
+			KeyConditionExpression: aws.String("UserID = :userID AND exerciseId = :exerciseID"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":userID": {
+					S: aws.String(userID),
+				},
+				":exerciseID": {
+					S: aws.String(exerciseID),
+				},
+			},
+		}, userID, opts)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		_, err := r.db.DeleteItem(&dynamodb.DeleteItemInput{
+			TableName: aws.String(r.tableName),
+			Key: map[string]*dynamodb.AttributeValue{
+				"UserID": {
+					S: aws.String(userID),
+				},
+				"RecordID": {
+					S: aws.String(record.RecordID),
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete record: %w", err)
+		}
+	}
+
+	return nil
+}
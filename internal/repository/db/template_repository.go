@@ -0,0 +1,154 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// DynamoTemplateRepository stores each template, prescriptions included, as a
+// single item keyed by UserID and TemplateID.
+type DynamoTemplateRepository struct {
+	db        *dynamodb.DynamoDB
+	tableName string
+}
+
+func NewDynamoTemplateRepository(db *dynamodb.DynamoDB, tableName string) *DynamoTemplateRepository {
+	return &DynamoTemplateRepository{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+func (r *DynamoTemplateRepository) key(userID, templateID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"UserID": {
+			S: aws.String(userID),
+		},
+		"TemplateID": {
+			S: aws.String(templateID),
+		},
+	}
+}
+
+func (r *DynamoTemplateRepository) GetByID(userID, templateID string) (*models.Template, error) {
+	result, err := r.db.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       r.key(userID, templateID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, models.ErrTemplateNotFound
+	}
+
+	var template models.Template
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &template); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal template: %w", err)
+	}
+
+	return &template, nil
+}
+
+func (r *DynamoTemplateRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Template], error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("UserID = :userID"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":userID": {
+				S: aws.String(userID),
+			},
+		},
+		ScanIndexForward: aws.Bool(!opts.Descending),
+	}
+	if err := applyListOptions(input, userID, opts); err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.Query(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+
+	templates := make([]*models.Template, 0, len(result.Items))
+	if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, &templates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal templates: %w", err)
+	}
+
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Page[*models.Template]{Items: templates, NextCursor: nextCursor}, nil
+}
+
+func (r *DynamoTemplateRepository) Create(template *models.Template) error {
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = time.Now()
+	}
+
+	item, err := dynamodbattribute.MarshalMap(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	_, err = r.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(UserID) AND attribute_not_exists(TemplateID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrTemplateAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoTemplateRepository) Update(template *models.Template) error {
+	item, err := dynamodbattribute.MarshalMap(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	_, err = r.db.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(UserID) AND attribute_exists(TemplateID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrTemplateNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoTemplateRepository) Delete(userID, templateID string) error {
+	_, err := r.db.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 r.key(userID, templateID),
+		ConditionExpression: aws.String("attribute_exists(UserID) AND attribute_exists(TemplateID)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrTemplateNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Deleted workouts and exercises stay in their tables with deletedAt set (the
+// models' DeletedAt) until restored or purged. expiresAt, in epoch seconds, is
+// the tables' TTL attribute: DynamoDB removes the item on its own once the
+// retention has passed, so these repositories need no sweeper. TTL deletion
+// can lag expiry by days, so deletedFilter also skips expired items; it needs
+// :now, from trashNow.
+const (
+	liveFilter    = "attribute_not_exists(deletedAt)"
+	deletedFilter = "attribute_exists(deletedAt) AND (attribute_not_exists(expiresAt) OR expiresAt > :now)"
+)
+
+// trashNow is the :now value deletedFilter compares expiresAt with.
+func trashNow() *dynamodb.AttributeValue {
+	return &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(time.Now().Unix(), 10))}
+}
+
+// trashUpdate builds the UpdateItem that moves the item at key to the trash.
+// The condition fails if the item is missing or already deleted. A zero
+// retention keeps the item until it is purged.
+func trashUpdate(tableName string, key map[string]*dynamodb.AttributeValue, retention time.Duration) *dynamodb.UpdateItemInput {
+	now := time.Now().UTC()
+	update := "SET deletedAt = :now"
+	values := map[string]*dynamodb.AttributeValue{
+		":now": {S: aws.String(now.Format(time.RFC3339Nano))},
+	}
+	if retention > 0 {
+		update += ", expiresAt = :expires"
+		values[":expires"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(now.Add(retention).Unix(), 10))}
+	}
+	return &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(tableName),
+		Key:                       key,
+		UpdateExpression:          aws.String(update),
+		ConditionExpression:       aws.String("attribute_exists(UserID) AND " + liveFilter),
+		ExpressionAttributeValues: values,
+	}
+}
+
+// restoreUpdate builds the UpdateItem that takes the item at key out of the
+// trash. The condition fails unless the item is in the trash and unexpired.
+func restoreUpdate(tableName string, key map[string]*dynamodb.AttributeValue) *dynamodb.UpdateItemInput {
+	return &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(tableName),
+		Key:                       key,
+		UpdateExpression:          aws.String("REMOVE deletedAt, expiresAt"),
+		ConditionExpression:       aws.String(deletedFilter),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":now": trashNow()},
+	}
+}
+
+// purgeDelete builds the DeleteItem that removes the item at key for good. The
+// condition fails unless the item is in the trash and unexpired.
+func purgeDelete(tableName string, key map[string]*dynamodb.AttributeValue) *dynamodb.DeleteItemInput {
+	return &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(tableName),
+		Key:                       key,
+		ConditionExpression:       aws.String(deletedFilter),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":now": trashNow()},
+	}
+}
+
+// filterLive hides deleted items from a query that does not already filter.
+// Like any filter it is applied after Limit, so a page can hold fewer items
+// than asked for while a NextCursor still follows.
+func filterLive(input *dynamodb.QueryInput) {
+	if input.FilterExpression == nil {
+		input.FilterExpression = aws.String(liveFilter)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
@@ -14,33 +15,30 @@ import (
 type DynamoWorkoutRepository struct {
 	db        *dynamodb.DynamoDB
 	tableName string
+	retention time.Duration
 }
 
-func NewDynamoWorkoutRepository(db *dynamodb.DynamoDB, tableName string) *DynamoWorkoutRepository {
+// NewDynamoWorkoutRepository returns a repository on tableName. Deleted
+// workouts expire through the table's TTL after retention.
+func NewDynamoWorkoutRepository(db *dynamodb.DynamoDB, tableName string, retention time.Duration) *DynamoWorkoutRepository {
 	return &DynamoWorkoutRepository{
 		db:        db,
 		tableName: tableName,
+		retention: retention,
 	}
 }
 
 func (r *DynamoWorkoutRepository) GetByID(userID, workoutID string) (*models.Workout, error) {
 	result, err := r.db.GetItem(&dynamodb.GetItemInput{
 		TableName: aws.String(r.tableName),
-		Key: map[string]*dynamodb.AttributeValue{
-			"UserID": {
-				S: aws.String(userID),
-			},
-			"WorkoutID": {
-				S: aws.String(workoutID),
-			},
-		},
+		Key:       workoutKey(userID, workoutID),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workout: %w", err)
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("workout not found")
+		return nil, models.ErrWorkoutNotFound
 	}
 
 	var workout models.Workout
@@ -48,12 +46,15 @@ func (r *DynamoWorkoutRepository) GetByID(userID, workoutID string) (*models.Wor
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal workout: %w", err)
 	}
+	if workout.DeletedAt != nil {
+		return nil, models.ErrWorkoutNotFound
+	}
 
 	return &workout, nil
 }
 
-func (r *DynamoWorkoutRepository) ListByUserID(userID string) ([]*models.Workout, error) {
-	result, err := r.db.Query(&dynamodb.QueryInput{
+func (r *DynamoWorkoutRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	return r.queryPage(&dynamodb.QueryInput{
 		TableName:              aws.String(r.tableName),
 		KeyConditionExpression: aws.String("UserID = :userID"),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
@@ -61,12 +62,62 @@ func (r *DynamoWorkoutRepository) ListByUserID(userID string) ([]*models.Workout
 				S: aws.String(userID),
 			},
 		},
-	})
+		ScanIndexForward: aws.Bool(!opts.Descending),
+	}, userID, opts)
+}
+
+// ListByDateRange queries WorkoutDateIndex (UserID + date) so results come back
+// sorted by workout date.
+func (r *DynamoWorkoutRepository) ListByDateRange(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	keyCondition := "UserID = :userID"
+	values := map[string]*dynamodb.AttributeValue{
+		":userID": {
+			S: aws.String(userID),
+		},
+	}
+
+	switch {
+	case dateRange.From != "" && dateRange.To != "":
+		keyCondition += " AND #date BETWEEN :from AND :to"
+	case dateRange.From != "":
+		keyCondition += " AND #date >= :from"
+	case dateRange.To != "":
+		keyCondition += " AND #date <= :to"
+	}
+	if dateRange.From != "" {
+		values[":from"] = &dynamodb.AttributeValue{S: aws.String(dateRange.From)}
+	}
+	if dateRange.To != "" {
+		values[":to"] = &dynamodb.AttributeValue{S: aws.String(dateRange.UpperBound())}
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String("WorkoutDateIndex"),
+		KeyConditionExpression:    aws.String(keyCondition),
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          aws.Bool(!opts.Descending),
+	}
+	// date is a DynamoDB reserved word.
+	if dateRange.From != "" || dateRange.To != "" {
+		input.ExpressionAttributeNames = map[string]*string{"#date": aws.String("date")}
+	}
+
+	return r.queryPage(input, userID, opts)
+}
+
+func (r *DynamoWorkoutRepository) queryPage(input *dynamodb.QueryInput, userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	if err := applyListOptions(input, userID, opts); err != nil {
+		return nil, err
+	}
+	filterLive(input)
+
+	result, err := r.db.Query(input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query workouts: %w", err)
 	}
 
-	var workouts []*models.Workout
+	workouts := make([]*models.Workout, 0, len(result.Items))
 	for _, item := range result.Items {
 		var workout models.Workout
 		err = dynamodbattribute.UnmarshalMap(item, &workout)
@@ -76,7 +127,12 @@ func (r *DynamoWorkoutRepository) ListByUserID(userID string) ([]*models.Workout
 		workouts = append(workouts, &workout)
 	}
 
-	return workouts, nil
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Page[*models.Workout]{Items: workouts, NextCursor: nextCursor}, nil
 }
 
 func (r *DynamoWorkoutRepository) Create(workout *models.Workout) error {
@@ -84,9 +140,9 @@ func (r *DynamoWorkoutRepository) Create(workout *models.Workout) error {
 		workout.CreatedAt = time.Now()
 	}
 
-	item, err := dynamodbattribute.MarshalMap(workout)
+	item, err := marshalWorkout(workout)
 	if err != nil {
-		return fmt.Errorf("failed to marshal workout: %w", err)
+		return err
 	}
 
 	_, err = r.db.PutItem(&dynamodb.PutItemInput{
@@ -94,6 +150,9 @@ func (r *DynamoWorkoutRepository) Create(workout *models.Workout) error {
 		Item:      item,
 		ConditionExpression: aws.String("attribute_not_exists(UserID) AND attribute_not_exists(WorkoutID)"),
 	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrWorkoutAlreadyExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create workout: %w", err)
 	}
@@ -102,16 +161,19 @@ func (r *DynamoWorkoutRepository) Create(workout *models.Workout) error {
 }
 
 func (r *DynamoWorkoutRepository) Update(workout *models.Workout) error {
-	item, err := dynamodbattribute.MarshalMap(workout)
+	item, err := marshalWorkout(workout)
 	if err != nil {
-		return fmt.Errorf("failed to marshal workout: %w", err)
+		return err
 	}
 
 	_, err = r.db.PutItem(&dynamodb.PutItemInput{
 		TableName: aws.String(r.tableName),
 		Item:      item,
-		ConditionExpression: aws.String("attribute_exists(UserID) AND attribute_exists(WorkoutID)"),
+		ConditionExpression: aws.String("attribute_exists(UserID) AND attribute_exists(WorkoutID) AND " + liveFilter),
 	})
+	if isConditionalCheckFailed(err) {
+		return models.ErrWorkoutNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update workout: %w", err)
 	}
@@ -119,22 +181,76 @@ func (r *DynamoWorkoutRepository) Update(workout *models.Workout) error {
 	return nil
 }
 
+// Delete moves the workout to the trash.
 func (r *DynamoWorkoutRepository) Delete(workoutID string, userID string) error {
-	_, err := r.db.DeleteItem(&dynamodb.DeleteItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]*dynamodb.AttributeValue{
-			"UserID": {
+	_, err := r.db.UpdateItem(trashUpdate(r.tableName, workoutKey(userID, workoutID), r.retention))
+	if isConditionalCheckFailed(err) {
+		return models.ErrWorkoutNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete workout: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoWorkoutRepository) ListDeleted(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	return r.queryPage(&dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("UserID = :userID"),
+		FilterExpression:       aws.String(deletedFilter),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":userID": {
 				S: aws.String(userID),
 			},
-			"WorkoutID": {
-				S: aws.String(workoutID),
-			},
+			":now": trashNow(),
 		},
-		ConditionExpression: aws.String("attribute_exists(UserID) AND attribute_exists(WorkoutID)"),
-	})
+		ScanIndexForward: aws.Bool(!opts.Descending),
+	}, userID, opts)
+}
+
+func (r *DynamoWorkoutRepository) Restore(userID, workoutID string) error {
+	_, err := r.db.UpdateItem(restoreUpdate(r.tableName, workoutKey(userID, workoutID)))
+	if isConditionalCheckFailed(err) {
+		return models.ErrWorkoutNotFound
+	}
 	if err != nil {
-		return fmt.Errorf("failed to delete workout: %w", err)
+		return fmt.Errorf("failed to restore workout: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoWorkoutRepository) Purge(userID, workoutID string) error {
+	_, err := r.db.DeleteItem(purgeDelete(r.tableName, workoutKey(userID, workoutID)))
+	if isConditionalCheckFailed(err) {
+		return models.ErrWorkoutNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to purge workout: %w", err)
 	}
 
 	return nil
 }
+
+// marshalWorkout builds the stored item for a workout. Only Delete moves
+// workouts to the trash, so a DeletedAt sent by the caller is dropped.
+func marshalWorkout(workout *models.Workout) (map[string]*dynamodb.AttributeValue, error) {
+	item, err := dynamodbattribute.MarshalMap(workout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal workout: %w", err)
+	}
+	delete(item, "deletedAt")
+	return item, nil
+}
+
+func workoutKey(userID, workoutID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"UserID": {
+			S: aws.String(userID),
+		},
+		"WorkoutID": {
+			S: aws.String(workoutID),
+		},
+	}
+}
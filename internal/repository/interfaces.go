@@ -1,21 +1,99 @@
 package repository
 
-import "gym-tracker-api/internal/models"
+import (
+	"time"
+
+	"gym-tracker-api/internal/models"
+)
+
+// Deleted workouts and exercises are kept in a trash until they are restored
+// or purged. Only ListDeleted, Restore and Purge see them; to every other
+// method they do not exist, although their IDs stay taken. Once a backend's
+// retention has passed they are gone to those three as well, whether or not
+// the TTL or sweeper has removed them yet.
 
 type WorkoutRepository interface {
 	GetByID(userID, workoutID string) (*models.Workout, error)
-	ListByUserID(userID string) ([]*models.Workout, error)
+	ListByUserID(userID string, opts ListOptions) (*Page[*models.Workout], error)
+	ListByDateRange(userID string, dateRange DateRange, opts ListOptions) (*Page[*models.Workout], error)
 	Create(workout *models.Workout) error
 	Update(workout *models.Workout) error
+	// Delete moves the workout to the trash.
 	Delete(workoutID string, userID string) error
+	// ListDeleted returns the user's trashed workouts, with DeletedAt set.
+	ListDeleted(userID string, opts ListOptions) (*Page[*models.Workout], error)
+	// Restore takes a workout out of the trash.
+	Restore(userID, workoutID string) error
+	// Purge permanently deletes a trashed workout.
+	Purge(userID, workoutID string) error
 }
 
 type ExerciseRepository interface {
 	GetByID(userID, exerciseID string) (*models.Exercise, error)
-	ListByUserID(userID string) ([]*models.Exercise, error)
-	ListByType(userID, exerciseType string) ([]*models.Exercise, error)
+	// GetMany returns the exercises that exist among exerciseIDs, in no particular order.
+	GetMany(userID string, exerciseIDs []string) ([]*models.Exercise, error)
+	ListByUserID(userID string, opts ListOptions) (*Page[*models.Exercise], error)
+	ListByType(userID, exerciseType string, opts ListOptions) (*Page[*models.Exercise], error)
 	ListByName(userID, exerciseName string) ([]*models.Exercise, error)
+	ListByNamePrefix(userID, prefix string, opts ListOptions) (*Page[*models.Exercise], error)
 	Create(userID string, exercise *models.Exercise) error
 	Update(userID string, exercise *models.Exercise) error
+	// Delete moves the exercise to the trash.
 	Delete(userID string, exerciseID string) error
+	// ListDeleted returns the user's trashed exercises, with DeletedAt set.
+	ListDeleted(userID string, opts ListOptions) (*Page[*models.Exercise], error)
+	// Restore takes an exercise out of the trash.
+	Restore(userID, exerciseID string) error
+	// Purge permanently deletes a trashed exercise.
+	Purge(userID, exerciseID string) error
+}
+
+// TrashSweeper purges every user's workouts or exercises trashed before
+// cutoff and returns how many it removed. Backends that expire trashed items
+// themselves, like DynamoDB with TTL, do not implement it.
+type TrashSweeper interface {
+	PurgeDeletedBefore(cutoff time.Time) (int, error)
+}
+
+type TemplateRepository interface {
+	GetByID(userID, templateID string) (*models.Template, error)
+	ListByUserID(userID string, opts ListOptions) (*Page[*models.Template], error)
+	Create(template *models.Template) error
+	Update(template *models.Template) error
+	Delete(userID, templateID string) error
+}
+
+type ProgramRepository interface {
+	GetByID(userID, programID string) (*models.Program, error)
+	ListByUserID(userID string, opts ListOptions) (*Page[*models.Program], error)
+	Create(program *models.Program) error
+	// Update stores program and increments its Version. It fails with
+	// ErrProgramConflict if the stored program has moved past program.Version.
+	Update(program *models.Program) error
+	Delete(userID, programID string) error
+}
+
+type RecordRepository interface {
+	ListByUserID(userID string, opts ListOptions) (*Page[*models.PersonalRecord], error)
+	// ListByName returns the records for a normalized exercise name, oldest first.
+	ListByName(userID, exerciseName string) ([]*models.PersonalRecord, error)
+	Create(record *models.PersonalRecord) error
+	// DeleteByExercise removes the records set by one exercise.
+	DeleteByExercise(userID, exerciseID string) error
+}
+
+type ProfileRepository interface {
+	GetByUserID(userID string) (*models.UserProfile, error)
+	Create(profile *models.UserProfile) error
+	Update(profile *models.UserProfile) error
+	Delete(userID string) error
+}
+
+type MeasurementRepository interface {
+	GetByID(userID, measurementID string) (*models.Measurement, error)
+	// ListByDateRange returns measurements ordered by date, then ID.
+	ListByDateRange(userID string, dateRange DateRange, opts ListOptions) (*Page[*models.Measurement], error)
+	Create(measurement *models.Measurement) error
+	Update(measurement *models.Measurement) error
+	Delete(userID, measurementID string) error
 }
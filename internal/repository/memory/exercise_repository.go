@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// ExerciseRepository is a thread-safe, in-process implementation of
+// repository.ExerciseRepository for local development and tests.
+type ExerciseRepository struct {
+	mu        sync.RWMutex
+	exercises map[string]map[string]*models.Exercise // UserID -> ExerciseID -> exercise
+	retention time.Duration
+}
+
+func NewExerciseRepository() *ExerciseRepository {
+	return &ExerciseRepository{
+		exercises: make(map[string]map[string]*models.Exercise),
+	}
+}
+
+// WithRetention makes deleted exercises expire from the trash retention after
+// they were deleted, as the DynamoDB repository does. Without it they stay
+// until purged.
+func (r *ExerciseRepository) WithRetention(retention time.Duration) *ExerciseRepository {
+	r.retention = retention
+	return r
+}
+
+func (r *ExerciseRepository) GetByID(userID, exerciseID string) (*models.Exercise, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	exercise, ok := r.exercises[userID][exerciseID]
+	if !ok || exercise.DeletedAt != nil {
+		return nil, models.ErrExerciseNotFound
+	}
+	return cloneExercise(exercise), nil
+}
+
+func (r *ExerciseRepository) GetMany(userID string, exerciseIDs []string) ([]*models.Exercise, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool, len(exerciseIDs))
+	exercises := make([]*models.Exercise, 0, len(exerciseIDs))
+	for _, id := range exerciseIDs {
+		exercise, ok := r.exercises[userID][id]
+		if !ok || exercise.DeletedAt != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		exercises = append(exercises, cloneExercise(exercise))
+	}
+	return exercises, nil
+}
+
+func (r *ExerciseRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	return paginate(r.userExercises(userID, nil), exerciseIDOrder, opts)
+}
+
+func (r *ExerciseRepository) ListByType(userID, exerciseType string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	matching := r.userExercises(userID, func(e *models.Exercise) bool {
+		return e.ExerciseType == exerciseType
+	})
+	return paginate(matching, nameOrder, opts)
+}
+
+func (r *ExerciseRepository) ListByName(userID, exerciseName string) ([]*models.Exercise, error) {
+	nameKey := models.NormalizeName(exerciseName)
+	matching := r.userExercises(userID, func(e *models.Exercise) bool {
+		return models.NormalizeName(e.Name) == nameKey
+	})
+	page, err := paginate(matching, nameOrder, repository.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+func (r *ExerciseRepository) ListByNamePrefix(userID, prefix string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	prefix = models.NormalizeName(prefix)
+	matching := r.userExercises(userID, func(e *models.Exercise) bool {
+		return strings.HasPrefix(models.NormalizeName(e.Name), prefix)
+	})
+	return paginate(matching, nameOrder, opts)
+}
+
+func (r *ExerciseRepository) Create(userID string, exercise *models.Exercise) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.exercises[userID][exercise.ExerciseID]; exists {
+		return models.ErrExerciseAlreadyExists
+	}
+	if r.exercises[userID] == nil {
+		r.exercises[userID] = make(map[string]*models.Exercise)
+	}
+	if exercise.CreatedAt.IsZero() {
+		exercise.CreatedAt = time.Now().UTC()
+	}
+	r.exercises[userID][exercise.ExerciseID] = liveExercise(exercise)
+	return nil
+}
+
+func (r *ExerciseRepository) Update(userID string, exercise *models.Exercise) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.live(userID, exercise.ExerciseID) {
+		return models.ErrExerciseNotFound
+	}
+	r.exercises[userID][exercise.ExerciseID] = liveExercise(exercise)
+	return nil
+}
+
+func (r *ExerciseRepository) Delete(userID string, exerciseID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.live(userID, exerciseID) {
+		return models.ErrExerciseNotFound
+	}
+	now := time.Now().UTC()
+	r.exercises[userID][exerciseID].DeletedAt = &now
+	return nil
+}
+
+func (r *ExerciseRepository) ListDeleted(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	r.mu.RLock()
+	var deleted []*models.Exercise
+	for _, exercise := range r.exercises[userID] {
+		if restorable(exercise.DeletedAt, r.retention) {
+			deleted = append(deleted, cloneExercise(exercise))
+		}
+	}
+	r.mu.RUnlock()
+	return paginate(deleted, exerciseIDOrder, opts)
+}
+
+func (r *ExerciseRepository) Restore(userID, exerciseID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exercise, ok := r.exercises[userID][exerciseID]
+	if !ok || !restorable(exercise.DeletedAt, r.retention) {
+		return models.ErrExerciseNotFound
+	}
+	exercise.DeletedAt = nil
+	return nil
+}
+
+func (r *ExerciseRepository) Purge(userID, exerciseID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exercise, ok := r.exercises[userID][exerciseID]
+	if !ok || !restorable(exercise.DeletedAt, r.retention) {
+		return models.ErrExerciseNotFound
+	}
+	delete(r.exercises[userID], exerciseID)
+	return nil
+}
+
+func (r *ExerciseRepository) PurgeDeletedBefore(cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for _, exercises := range r.exercises {
+		for id, exercise := range exercises {
+			if exercise.DeletedAt != nil && exercise.DeletedAt.Before(cutoff) {
+				delete(exercises, id)
+				purged++
+			}
+		}
+	}
+	return purged, nil
+}
+
+// live reports whether the exercise exists and is not in the trash. The
+// caller must hold r.mu.
+func (r *ExerciseRepository) live(userID, exerciseID string) bool {
+	exercise, ok := r.exercises[userID][exerciseID]
+	return ok && exercise.DeletedAt == nil
+}
+
+// userExercises returns copies of the user's exercises outside the trash
+// accepted by keep (all if nil).
+func (r *ExerciseRepository) userExercises(userID string, keep func(*models.Exercise) bool) []*models.Exercise {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	exercises := make([]*models.Exercise, 0, len(r.exercises[userID]))
+	for _, exercise := range r.exercises[userID] {
+		if exercise.DeletedAt == nil && (keep == nil || keep(exercise)) {
+			exercises = append(exercises, cloneExercise(exercise))
+		}
+	}
+	return exercises
+}
+
+func exerciseIDOrder(e *models.Exercise) string {
+	return e.ExerciseID
+}
+
+// nameOrder sorts exercises by normalized name, matching the Dynamo name indexes.
+func nameOrder(e *models.Exercise) string {
+	return compositeKey(models.NormalizeName(e.Name), e.ExerciseID)
+}
+
+// cloneExercise copies an exercise so callers cannot mutate stored state.
+func cloneExercise(e *models.Exercise) *models.Exercise {
+	c := *e
+	if e.Sets != nil {
+		c.Sets = append([]models.WeightItem{}, e.Sets...)
+		for i, set := range c.Sets {
+			if set.RIR != nil {
+				rir := *set.RIR
+				c.Sets[i].RIR = &rir
+			}
+		}
+	}
+	c.DeletedAt = cloneTime(e.DeletedAt)
+	return &c
+}
+
+// liveExercise copies an exercise for storage, dropping any DeletedAt sent by
+// the caller: only Delete moves exercises to the trash.
+func liveExercise(e *models.Exercise) *models.Exercise {
+	c := cloneExercise(e)
+	c.DeletedAt = nil
+	return c
+}
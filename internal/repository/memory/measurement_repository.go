@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// MeasurementRepository is a thread-safe, in-process implementation of
+// repository.MeasurementRepository for local development and tests.
+type MeasurementRepository struct {
+	mu           sync.RWMutex
+	measurements map[string]map[string]*models.Measurement // UserID -> MeasurementID -> measurement
+}
+
+func NewMeasurementRepository() *MeasurementRepository {
+	return &MeasurementRepository{
+		measurements: make(map[string]map[string]*models.Measurement),
+	}
+}
+
+func (r *MeasurementRepository) GetByID(userID, measurementID string) (*models.Measurement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	measurement, ok := r.measurements[userID][measurementID]
+	if !ok {
+		return nil, models.ErrMeasurementNotFound
+	}
+	return cloneMeasurement(measurement), nil
+}
+
+func (r *MeasurementRepository) ListByDateRange(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Measurement], error) {
+	r.mu.RLock()
+	var matching []*models.Measurement
+	for _, measurement := range r.measurements[userID] {
+		if dateRange.Contains(measurement.Date) {
+			matching = append(matching, cloneMeasurement(measurement))
+		}
+	}
+	r.mu.RUnlock()
+
+	return paginate(matching, func(m *models.Measurement) string {
+		return compositeKey(m.Date, m.MeasurementID)
+	}, opts)
+}
+
+func (r *MeasurementRepository) Create(measurement *models.Measurement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.measurements[measurement.UserID][measurement.MeasurementID]; exists {
+		return models.ErrMeasurementAlreadyExists
+	}
+	if measurement.CreatedAt.IsZero() {
+		measurement.CreatedAt = time.Now()
+	}
+	if r.measurements[measurement.UserID] == nil {
+		r.measurements[measurement.UserID] = make(map[string]*models.Measurement)
+	}
+	r.measurements[measurement.UserID][measurement.MeasurementID] = cloneMeasurement(measurement)
+	return nil
+}
+
+func (r *MeasurementRepository) Update(measurement *models.Measurement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.measurements[measurement.UserID][measurement.MeasurementID]; !exists {
+		return models.ErrMeasurementNotFound
+	}
+	r.measurements[measurement.UserID][measurement.MeasurementID] = cloneMeasurement(measurement)
+	return nil
+}
+
+func (r *MeasurementRepository) Delete(userID, measurementID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.measurements[userID][measurementID]; !exists {
+		return models.ErrMeasurementNotFound
+	}
+	delete(r.measurements[userID], measurementID)
+	return nil
+}
+
+// cloneMeasurement copies a measurement so callers cannot mutate stored state.
+func cloneMeasurement(m *models.Measurement) *models.Measurement {
+	c := *m
+	return &c
+}
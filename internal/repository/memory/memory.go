@@ -0,0 +1,16 @@
+// Package memory provides in-process implementations of the repository
+// interfaces. Data lives only as long as the process, which makes these
+// backends suitable for local development (STORAGE=memory) and tests.
+package memory
+
+import "gym-tracker-api/internal/repository"
+
+var (
+	_ repository.WorkoutRepository     = (*WorkoutRepository)(nil)
+	_ repository.ExerciseRepository    = (*ExerciseRepository)(nil)
+	_ repository.TemplateRepository    = (*TemplateRepository)(nil)
+	_ repository.ProgramRepository     = (*ProgramRepository)(nil)
+	_ repository.RecordRepository      = (*RecordRepository)(nil)
+	_ repository.ProfileRepository     = (*ProfileRepository)(nil)
+	_ repository.MeasurementRepository = (*MeasurementRepository)(nil)
+)
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/repository/repotest"
+)
+
+func newWorkout(userID, workoutID, date string) *models.Workout {
+	return &models.Workout{
+		UserID:    userID,
+		WorkoutID: workoutID,
+		Name:      "Push Day",
+		Date:      date,
+		Exercises: []string{"ex-1"},
+	}
+}
+
+func newExercise(id, name, exerciseType string) *models.Exercise {
+	return &models.Exercise{
+		ExerciseID:   id,
+		Name:         name,
+		ExerciseType: exerciseType,
+		Sets:         []models.WeightItem{{Weight: 60, Unit: "kg", Reps: 8}},
+	}
+}
+
+func TestWorkoutRepository_Conformance(t *testing.T) {
+	repotest.RunWorkoutRepositoryTests(t, func(t *testing.T) repository.WorkoutRepository {
+		return NewWorkoutRepository()
+	})
+}
+
+func TestExerciseRepository_Conformance(t *testing.T) {
+	repotest.RunExerciseRepositoryTests(t, func(t *testing.T) repository.ExerciseRepository {
+		return NewExerciseRepository()
+	})
+}
+
+func TestTrashExpiry_Conformance(t *testing.T) {
+	repotest.RunTrashExpiryTests(t,
+		func(t *testing.T, retention time.Duration) repository.WorkoutRepository {
+			return NewWorkoutRepository().WithRetention(retention)
+		},
+		func(t *testing.T, retention time.Duration) repository.ExerciseRepository {
+			return NewExerciseRepository().WithRetention(retention)
+		})
+}
+
+func TestTemplateRepository_Conformance(t *testing.T) {
+	repotest.RunTemplateRepositoryTests(t, func(t *testing.T) repository.TemplateRepository {
+		return NewTemplateRepository()
+	})
+}
+
+func TestProgramRepository_Conformance(t *testing.T) {
+	repotest.RunProgramRepositoryTests(t, func(t *testing.T) repository.ProgramRepository {
+		return NewProgramRepository()
+	})
+}
+
+func TestRecordRepository_Conformance(t *testing.T) {
+	repotest.RunRecordRepositoryTests(t, func(t *testing.T) repository.RecordRepository {
+		return NewRecordRepository()
+	})
+}
+
+func TestWorkoutRepository_ReturnsCopies(t *testing.T) {
+	repo := NewWorkoutRepository()
+	w := newWorkout("user-1", "w-1", "2024-01-15")
+	if err := repo.Create(w); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	w.Exercises[0] = "mutated"
+	got, _ := repo.GetByID("user-1", "w-1")
+	got.Name = "mutated"
+
+	again, _ := repo.GetByID("user-1", "w-1")
+	if again.Exercises[0] != "ex-1" || again.Name != "Push Day" {
+		t.Errorf("stored workout was mutated through a caller's pointer: %+v", again)
+	}
+}
+
+func TestRepositories_ConcurrentAccess(t *testing.T) {
+	workouts := NewWorkoutRepository()
+	exercises := NewExerciseRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("id-%d", i)
+			workouts.Create(newWorkout("user-1", id, "2024-01-15"))
+			exercises.Create("user-1", newExercise(id, "Squat", models.ExerciseTypeWeights))
+			workouts.ListByUserID("user-1", repository.ListOptions{})
+			exercises.ListByName("user-1", "squat")
+		}(i)
+	}
+	wg.Wait()
+
+	page, _ := workouts.ListByUserID("user-1", repository.ListOptions{})
+	if len(page.Items) != 20 {
+		t.Errorf("expected 20 workouts, got %d", len(page.Items))
+	}
+}
+
+func TestProfileRepository_Conformance(t *testing.T) {
+	repotest.RunProfileRepositoryTests(t, func(t *testing.T) repository.ProfileRepository {
+		return NewProfileRepository()
+	})
+}
+
+func TestMeasurementRepository_Conformance(t *testing.T) {
+	repotest.RunMeasurementRepositoryTests(t, func(t *testing.T) repository.MeasurementRepository {
+		return NewMeasurementRepository()
+	})
+}
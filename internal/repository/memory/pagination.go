@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// paginate returns one page of items ordered by sortKey, which must be unique
+// per item. The cursor is the encoded sort key of the last item returned.
+func paginate[T any](items []T, sortKey func(T) string, opts repository.ListOptions) (*repository.Page[T], error) {
+	sort.Slice(items, func(i, j int) bool {
+		if opts.Descending {
+			return sortKey(items[i]) > sortKey(items[j])
+		}
+		return sortKey(items[i]) < sortKey(items[j])
+	})
+
+	start := 0
+	if opts.Cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(opts.Cursor)
+		if err != nil {
+			return nil, models.ErrInvalidCursor
+		}
+		after := string(decoded)
+		start = sort.Search(len(items), func(i int) bool {
+			if opts.Descending {
+				return sortKey(items[i]) < after
+			}
+			return sortKey(items[i]) > after
+		})
+	}
+
+	end := len(items)
+	if opts.Limit > 0 && start+opts.Limit < end {
+		end = start + opts.Limit
+	}
+
+	page := &repository.Page[T]{Items: append([]T{}, items[start:end]...)}
+	if end < len(items) {
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(sortKey(items[end-1])))
+	}
+	return page, nil
+}
+
+// compositeKey joins sort fields so that ties on the first field are broken by
+// the item's ID.
+func compositeKey(parts ...string) string {
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"gym-tracker-api/internal/models"
+)
+
+// ProfileRepository is a thread-safe, in-process implementation of
+// repository.ProfileRepository for local development and tests.
+type ProfileRepository struct {
+	mu       sync.RWMutex
+	profiles map[string]*models.UserProfile // UserID -> profile
+}
+
+func NewProfileRepository() *ProfileRepository {
+	return &ProfileRepository{
+		profiles: make(map[string]*models.UserProfile),
+	}
+}
+
+func (r *ProfileRepository) GetByUserID(userID string) (*models.UserProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profile, ok := r.profiles[userID]
+	if !ok {
+		return nil, models.ErrProfileNotFound
+	}
+	return cloneProfile(profile), nil
+}
+
+func (r *ProfileRepository) Create(profile *models.UserProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[profile.UserID]; exists {
+		return models.ErrProfileAlreadyExists
+	}
+	if profile.CreatedAt.IsZero() {
+		profile.CreatedAt = time.Now()
+	}
+	if profile.UpdatedAt.IsZero() {
+		profile.UpdatedAt = profile.CreatedAt
+	}
+	r.profiles[profile.UserID] = cloneProfile(profile)
+	return nil
+}
+
+func (r *ProfileRepository) Update(profile *models.UserProfile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[profile.UserID]; !exists {
+		return models.ErrProfileNotFound
+	}
+	r.profiles[profile.UserID] = cloneProfile(profile)
+	return nil
+}
+
+func (r *ProfileRepository) Delete(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[userID]; !exists {
+		return models.ErrProfileNotFound
+	}
+	delete(r.profiles, userID)
+	return nil
+}
+
+// cloneProfile copies a profile so callers cannot mutate stored state.
+func cloneProfile(p *models.UserProfile) *models.UserProfile {
+	c := *p
+	if p.Bodyweight != nil {
+		c.Bodyweight = append([]models.BodyweightEntry{}, p.Bodyweight...)
+	}
+	return &c
+}
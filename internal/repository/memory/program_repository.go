@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// ProgramRepository is a thread-safe, in-process implementation of
+// repository.ProgramRepository for local development and tests.
+type ProgramRepository struct {
+	mu       sync.RWMutex
+	programs map[string]map[string]*models.Program // UserID -> ProgramID -> program
+}
+
+func NewProgramRepository() *ProgramRepository {
+	return &ProgramRepository{
+		programs: make(map[string]map[string]*models.Program),
+	}
+}
+
+func (r *ProgramRepository) GetByID(userID, programID string) (*models.Program, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	program, ok := r.programs[userID][programID]
+	if !ok {
+		return nil, models.ErrProgramNotFound
+	}
+	return cloneProgram(program), nil
+}
+
+func (r *ProgramRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Program], error) {
+	r.mu.RLock()
+	programs := make([]*models.Program, 0, len(r.programs[userID]))
+	for _, program := range r.programs[userID] {
+		programs = append(programs, cloneProgram(program))
+	}
+	r.mu.RUnlock()
+
+	return paginate(programs, func(p *models.Program) string {
+		return p.ProgramID
+	}, opts)
+}
+
+func (r *ProgramRepository) Create(program *models.Program) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.programs[program.UserID][program.ProgramID]; exists {
+		return models.ErrProgramAlreadyExists
+	}
+	if program.CreatedAt.IsZero() {
+		program.CreatedAt = time.Now()
+	}
+	if r.programs[program.UserID] == nil {
+		r.programs[program.UserID] = make(map[string]*models.Program)
+	}
+	r.programs[program.UserID][program.ProgramID] = cloneProgram(program)
+	return nil
+}
+
+func (r *ProgramRepository) Update(program *models.Program) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.programs[program.UserID][program.ProgramID]
+	if !exists {
+		return models.ErrProgramNotFound
+	}
+	if stored.Version != program.Version {
+		return models.ErrProgramConflict
+	}
+	program.Version++
+	r.programs[program.UserID][program.ProgramID] = cloneProgram(program)
+	return nil
+}
+
+func (r *ProgramRepository) Delete(userID, programID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.programs[userID][programID]; !exists {
+		return models.ErrProgramNotFound
+	}
+	delete(r.programs[userID], programID)
+	return nil
+}
+
+// cloneProgram deep-copies a program so callers cannot mutate stored state.
+func cloneProgram(p *models.Program) *models.Program {
+	c := *p
+	if p.Weeks != nil {
+		c.Weeks = make([]models.ProgramWeek, len(p.Weeks))
+		for i, week := range p.Weeks {
+			c.Weeks[i] = week
+			c.Weeks[i].Days = append([]models.ProgramDay(nil), week.Days...)
+		}
+	}
+	if p.Enrollment != nil {
+		e := *p.Enrollment
+		e.Sessions = append([]models.ProgramSession(nil), p.Enrollment.Sessions...)
+		c.Enrollment = &e
+	}
+	return &c
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"sync"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// RecordRepository is a thread-safe, in-process implementation of
+// repository.RecordRepository for local development and tests.
+type RecordRepository struct {
+	mu      sync.RWMutex
+	records map[string]map[string]*models.PersonalRecord // UserID -> RecordID -> record
+}
+
+func NewRecordRepository() *RecordRepository {
+	return &RecordRepository{
+		records: make(map[string]map[string]*models.PersonalRecord),
+	}
+}
+
+func (r *RecordRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.PersonalRecord], error) {
+	r.mu.RLock()
+	records := make([]*models.PersonalRecord, 0, len(r.records[userID]))
+	for _, record := range r.records[userID] {
+		records = append(records, cloneRecord(record))
+	}
+	r.mu.RUnlock()
+
+	return paginate(records, func(rec *models.PersonalRecord) string {
+		return rec.RecordID
+	}, opts)
+}
+
+func (r *RecordRepository) ListByName(userID, exerciseName string) ([]*models.PersonalRecord, error) {
+	key := models.NormalizeName(exerciseName)
+
+	r.mu.RLock()
+	var records []*models.PersonalRecord
+	for _, record := range r.records[userID] {
+		if models.NormalizeName(record.ExerciseName) == key {
+			records = append(records, cloneRecord(record))
+		}
+	}
+	r.mu.RUnlock()
+
+	models.SortRecords(records)
+	return records, nil
+}
+
+func (r *RecordRepository) Create(record *models.PersonalRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.records[record.UserID][record.RecordID]; exists {
+		return models.ErrRecordAlreadyExists
+	}
+	if r.records[record.UserID] == nil {
+		r.records[record.UserID] = make(map[string]*models.PersonalRecord)
+	}
+	r.records[record.UserID][record.RecordID] = cloneRecord(record)
+	return nil
+}
+
+func (r *RecordRepository) DeleteByExercise(userID, exerciseID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, record := range r.records[userID] {
+		if record.ExerciseID == exerciseID {
+			delete(r.records[userID], id)
+		}
+	}
+	return nil
+}
+
+func cloneRecord(r *models.PersonalRecord) *models.PersonalRecord {
+	c := *r
+	return &c
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// TemplateRepository is a thread-safe, in-process implementation of
+// repository.TemplateRepository for local development and tests.
+type TemplateRepository struct {
+	mu        sync.RWMutex
+	templates map[string]map[string]*models.Template // UserID -> TemplateID -> template
+}
+
+func NewTemplateRepository() *TemplateRepository {
+	return &TemplateRepository{
+		templates: make(map[string]map[string]*models.Template),
+	}
+}
+
+func (r *TemplateRepository) GetByID(userID, templateID string) (*models.Template, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	template, ok := r.templates[userID][templateID]
+	if !ok {
+		return nil, models.ErrTemplateNotFound
+	}
+	return cloneTemplate(template), nil
+}
+
+func (r *TemplateRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Template], error) {
+	r.mu.RLock()
+	templates := make([]*models.Template, 0, len(r.templates[userID]))
+	for _, template := range r.templates[userID] {
+		templates = append(templates, cloneTemplate(template))
+	}
+	r.mu.RUnlock()
+
+	return paginate(templates, func(t *models.Template) string {
+		return t.TemplateID
+	}, opts)
+}
+
+func (r *TemplateRepository) Create(template *models.Template) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[template.UserID][template.TemplateID]; exists {
+		return models.ErrTemplateAlreadyExists
+	}
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = time.Now()
+	}
+	if r.templates[template.UserID] == nil {
+		r.templates[template.UserID] = make(map[string]*models.Template)
+	}
+	r.templates[template.UserID][template.TemplateID] = cloneTemplate(template)
+	return nil
+}
+
+func (r *TemplateRepository) Update(template *models.Template) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[template.UserID][template.TemplateID]; !exists {
+		return models.ErrTemplateNotFound
+	}
+	r.templates[template.UserID][template.TemplateID] = cloneTemplate(template)
+	return nil
+}
+
+func (r *TemplateRepository) Delete(userID, templateID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[userID][templateID]; !exists {
+		return models.ErrTemplateNotFound
+	}
+	delete(r.templates[userID], templateID)
+	return nil
+}
+
+// cloneTemplate copies a template so callers cannot mutate stored state.
+func cloneTemplate(t *models.Template) *models.Template {
+	c := *t
+	if t.Exercises != nil {
+		c.Exercises = append([]models.ExercisePrescription{}, t.Exercises...)
+	}
+	return &c
+}
@@ -0,0 +1,15 @@
+package memory
+
+import "time"
+
+// restorable reports whether an item deleted at deletedAt is still in the
+// trash. Like the expiresAt TTL attribute of the DynamoDB tables, retention
+// counts in whole epoch seconds from deletion; once it has passed the item is
+// gone to ListDeleted, Restore and Purge, although the sweeper has yet to
+// remove it. A zero retention keeps the item until it is purged.
+func restorable(deletedAt *time.Time, retention time.Duration) bool {
+	if deletedAt == nil {
+		return false
+	}
+	return retention <= 0 || deletedAt.Add(retention).Unix() > time.Now().Unix()
+}
@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// WorkoutRepository is a thread-safe, in-process implementation of
+// repository.WorkoutRepository for local development and tests.
+type WorkoutRepository struct {
+	mu        sync.RWMutex
+	workouts  map[string]map[string]*models.Workout // UserID -> WorkoutID -> workout
+	retention time.Duration
+}
+
+func NewWorkoutRepository() *WorkoutRepository {
+	return &WorkoutRepository{
+		workouts: make(map[string]map[string]*models.Workout),
+	}
+}
+
+// WithRetention makes deleted workouts expire from the trash retention after
+// they were deleted, as the DynamoDB repository does. Without it they stay
+// until purged.
+func (r *WorkoutRepository) WithRetention(retention time.Duration) *WorkoutRepository {
+	r.retention = retention
+	return r
+}
+
+func (r *WorkoutRepository) GetByID(userID, workoutID string) (*models.Workout, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	workout, ok := r.workouts[userID][workoutID]
+	if !ok || workout.DeletedAt != nil {
+		return nil, models.ErrWorkoutNotFound
+	}
+	return cloneWorkout(workout), nil
+}
+
+func (r *WorkoutRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	return paginate(r.userWorkouts(userID, false), workoutIDOrder, opts)
+}
+
+func (r *WorkoutRepository) ListByDateRange(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	var matching []*models.Workout
+	for _, workout := range r.userWorkouts(userID, false) {
+		if dateRange.Contains(workout.Date) {
+			matching = append(matching, workout)
+		}
+	}
+	return paginate(matching, func(w *models.Workout) string {
+		return compositeKey(w.Date, w.WorkoutID)
+	}, opts)
+}
+
+func (r *WorkoutRepository) Create(workout *models.Workout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.workouts[workout.UserID][workout.WorkoutID]; exists {
+		return models.ErrWorkoutAlreadyExists
+	}
+	if workout.CreatedAt.IsZero() {
+		workout.CreatedAt = time.Now()
+	}
+	if r.workouts[workout.UserID] == nil {
+		r.workouts[workout.UserID] = make(map[string]*models.Workout)
+	}
+	r.workouts[workout.UserID][workout.WorkoutID] = liveWorkout(workout)
+	return nil
+}
+
+func (r *WorkoutRepository) Update(workout *models.Workout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.live(workout.UserID, workout.WorkoutID) {
+		return models.ErrWorkoutNotFound
+	}
+	r.workouts[workout.UserID][workout.WorkoutID] = liveWorkout(workout)
+	return nil
+}
+
+func (r *WorkoutRepository) Delete(workoutID string, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.live(userID, workoutID) {
+		return models.ErrWorkoutNotFound
+	}
+	now := time.Now().UTC()
+	r.workouts[userID][workoutID].DeletedAt = &now
+	return nil
+}
+
+func (r *WorkoutRepository) ListDeleted(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	return paginate(r.userWorkouts(userID, true), workoutIDOrder, opts)
+}
+
+func (r *WorkoutRepository) Restore(userID, workoutID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workout, ok := r.workouts[userID][workoutID]
+	if !ok || !restorable(workout.DeletedAt, r.retention) {
+		return models.ErrWorkoutNotFound
+	}
+	workout.DeletedAt = nil
+	return nil
+}
+
+func (r *WorkoutRepository) Purge(userID, workoutID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workout, ok := r.workouts[userID][workoutID]
+	if !ok || !restorable(workout.DeletedAt, r.retention) {
+		return models.ErrWorkoutNotFound
+	}
+	delete(r.workouts[userID], workoutID)
+	return nil
+}
+
+func (r *WorkoutRepository) PurgeDeletedBefore(cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for _, workouts := range r.workouts {
+		for id, workout := range workouts {
+			if workout.DeletedAt != nil && workout.DeletedAt.Before(cutoff) {
+				delete(workouts, id)
+				purged++
+			}
+		}
+	}
+	return purged, nil
+}
+
+// live reports whether the workout exists and is not in the trash. The
+// caller must hold r.mu.
+func (r *WorkoutRepository) live(userID, workoutID string) bool {
+	workout, ok := r.workouts[userID][workoutID]
+	return ok && workout.DeletedAt == nil
+}
+
+// userWorkouts returns copies of the workouts owned by userID that are in
+// the trash and unexpired, or that are not in the trash.
+func (r *WorkoutRepository) userWorkouts(userID string, deleted bool) []*models.Workout {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	workouts := make([]*models.Workout, 0, len(r.workouts[userID]))
+	for _, workout := range r.workouts[userID] {
+		if deleted && restorable(workout.DeletedAt, r.retention) || !deleted && workout.DeletedAt == nil {
+			workouts = append(workouts, cloneWorkout(workout))
+		}
+	}
+	return workouts
+}
+
+func workoutIDOrder(w *models.Workout) string {
+	return w.WorkoutID
+}
+
+// liveWorkout copies a workout for storage, dropping any DeletedAt sent by
+// the caller: only Delete moves workouts to the trash.
+func liveWorkout(w *models.Workout) *models.Workout {
+	c := cloneWorkout(w)
+	c.DeletedAt = nil
+	return c
+}
+
+// cloneWorkout copies a workout so callers cannot mutate stored state.
+func cloneWorkout(w *models.Workout) *models.Workout {
+	c := *w
+	if w.Exercises != nil {
+		c.Exercises = append([]string{}, w.Exercises...)
+	}
+	if w.Groups != nil {
+		c.Groups = make([]models.ExerciseGroup, len(w.Groups))
+		for i, g := range w.Groups {
+			c.Groups[i] = g
+			c.Groups[i].ExerciseIDs = append([]string{}, g.ExerciseIDs...)
+		}
+	}
+	c.StartedAt = cloneTime(w.StartedAt)
+	c.PausedAt = cloneTime(w.PausedAt)
+	c.FinishedAt = cloneTime(w.FinishedAt)
+	c.DeletedAt = cloneTime(w.DeletedAt)
+	return &c
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
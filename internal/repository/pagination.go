@@ -0,0 +1,89 @@
+package repository
+
+import "gym-tracker-api/internal/models"
+
+// ListOptions controls paging for list queries.
+type ListOptions struct {
+	Limit      int    // maximum number of items to return; 0 lets the backend choose
+	Cursor     string // opaque cursor taken from a previous page's NextCursor
+	Descending bool   // newest first, for listings with a sort key
+}
+
+// Page is a single page of list results. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// CollectAll follows cursors from fetch until every page has been read.
+func CollectAll[T any](fetch func(opts ListOptions) (*Page[T], error)) ([]T, error) {
+	var all []T
+	opts := ListOptions{}
+	for {
+		page, err := fetch(opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Items...)
+		if page.NextCursor == "" {
+			return all, nil
+		}
+		opts.Cursor = page.NextCursor
+	}
+}
+
+// ListAllWorkouts returns every workout belonging to the user.
+func ListAllWorkouts(repo WorkoutRepository, userID string) ([]*models.Workout, error) {
+	return CollectAll(func(opts ListOptions) (*Page[*models.Workout], error) {
+		return repo.ListByUserID(userID, opts)
+	})
+}
+
+// ListAllExercises returns every exercise belonging to the user.
+func ListAllExercises(repo ExerciseRepository, userID string) ([]*models.Exercise, error) {
+	return CollectAll(func(opts ListOptions) (*Page[*models.Exercise], error) {
+		return repo.ListByUserID(userID, opts)
+	})
+}
+
+// ListAllDeletedWorkouts returns every workout in the user's trash.
+func ListAllDeletedWorkouts(repo WorkoutRepository, userID string) ([]*models.Workout, error) {
+	return CollectAll(func(opts ListOptions) (*Page[*models.Workout], error) {
+		return repo.ListDeleted(userID, opts)
+	})
+}
+
+// ListAllDeletedExercises returns every exercise in the user's trash.
+func ListAllDeletedExercises(repo ExerciseRepository, userID string) ([]*models.Exercise, error) {
+	return CollectAll(func(opts ListOptions) (*Page[*models.Exercise], error) {
+		return repo.ListDeleted(userID, opts)
+	})
+}
+
+// ListAllTemplates returns every template belonging to the user.
+func ListAllTemplates(repo TemplateRepository, userID string) ([]*models.Template, error) {
+	return CollectAll(func(opts ListOptions) (*Page[*models.Template], error) {
+		return repo.ListByUserID(userID, opts)
+	})
+}
+
+// ListAllPrograms returns every program belonging to the user.
+func ListAllPrograms(repo ProgramRepository, userID string) ([]*models.Program, error) {
+	return CollectAll(func(opts ListOptions) (*Page[*models.Program], error) {
+		return repo.ListByUserID(userID, opts)
+	})
+}
+
+// ListAllRecords returns every personal record belonging to the user.
+func ListAllRecords(repo RecordRepository, userID string) ([]*models.PersonalRecord, error) {
+	return CollectAll(func(opts ListOptions) (*Page[*models.PersonalRecord], error) {
+		return repo.ListByUserID(userID, opts)
+	})
+}
+
+// ListAllMeasurements returns every measurement belonging to the user, oldest first.
+func ListAllMeasurements(repo MeasurementRepository, userID string) ([]*models.Measurement, error) {
+	return CollectAll(func(opts ListOptions) (*Page[*models.Measurement], error) {
+		return repo.ListByDateRange(userID, DateRange{}, opts)
+	})
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCollectAll_FollowsCursors(t *testing.T) {
+	pages := map[string]*Page[int]{
+		"":   {Items: []int{1, 2}, NextCursor: "p2"},
+		"p2": {Items: []int{3}, NextCursor: "p3"},
+		"p3": {Items: []int{4, 5}},
+	}
+
+	got, err := CollectAll(func(opts ListOptions) (*Page[int], error) {
+		return pages[opts.Cursor], nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("expected 5 items, got %v", got)
+	}
+	for i, v := range got {
+		if v != i+1 {
+			t.Errorf("item %d = %d, want %d", i, v, i+1)
+		}
+	}
+}
+
+func TestCollectAll_Error(t *testing.T) {
+	calls := 0
+	_, err := CollectAll(func(opts ListOptions) (*Page[int], error) {
+		calls++
+		if opts.Cursor == "" {
+			return &Page[int]{Items: []int{1}, NextCursor: "p2"}, nil
+		}
+		return nil, errors.New("db error")
+	})
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 fetches, got %d", calls)
+	}
+}
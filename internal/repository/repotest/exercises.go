@@ -0,0 +1,305 @@
+package repotest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+func newExercise(id, name, exerciseType string) *models.Exercise {
+	return &models.Exercise{
+		ExerciseID:   id,
+		Name:         name,
+		ExerciseType: exerciseType,
+		Sets: []models.WeightItem{
+			{Weight: 40, Unit: "kg", Reps: 10, SetType: models.SetTypeWarmup, Completed: true},
+			{Weight: 60, Unit: "kg", OriginalUnit: "kgs", Reps: 8, RIR: intPtr(0), Tempo: "31X0", RestSeconds: 120, Completed: true},
+			{Weight: 0, Unit: "kg", Duration: 45, SetType: models.SetTypeFailure, RPE: 9.5},
+		},
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+// RunExerciseRepositoryTests runs the exercise conformance suite against the
+// repositories returned by newRepo.
+func RunExerciseRepositoryTests(t *testing.T, newRepo ExerciseFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		weights := newExercise("ex-1", "Bench Press", models.ExerciseTypeWeights)
+		cardio := &models.Exercise{
+			ExerciseID:           "ex-2",
+			Name:                 "Rowing",
+			ExerciseType:         models.ExerciseTypeCardio,
+			Time:                 600,
+			Distance:             2.5,
+			DistanceUnit:         "km",
+			OriginalDistanceUnit: "kilometers",
+			Level:                7,
+			RPM:                  40.3,
+		}
+		for _, e := range []*models.Exercise{weights, cardio} {
+			if err := repo.Create("user-1", e); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if e.CreatedAt.IsZero() {
+				t.Error("Create should set CreatedAt")
+			}
+			got, err := repo.GetByID("user-1", e.ExerciseID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if !reflect.DeepEqual(got, e) {
+				t.Errorf("GetByID = %+v, want %+v", got, e)
+			}
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetByID("user-1", "missing"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("GetByID: err = %v, want ErrExerciseNotFound", err)
+		}
+		if err := repo.Update("user-1", newExercise("missing", "Squat", models.ExerciseTypeWeights)); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("Update: err = %v, want ErrExerciseNotFound", err)
+		}
+		if err := repo.Delete("user-1", "missing"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("Delete: err = %v, want ErrExerciseNotFound", err)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create("user-1", newExercise("ex-1", "Bench Press", models.ExerciseTypeWeights)); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Create("user-1", newExercise("ex-1", "Squat", models.ExerciseTypeWeights)); !errors.Is(err, models.ErrExerciseAlreadyExists) {
+			t.Errorf("Create duplicate: err = %v, want ErrExerciseAlreadyExists", err)
+		}
+		got, _ := repo.GetByID("user-1", "ex-1")
+		if got == nil || got.Name != "Bench Press" {
+			t.Errorf("duplicate Create overwrote the stored exercise: %+v", got)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		e := newExercise("ex-1", "Bench Press", models.ExerciseTypeWeights)
+		if err := repo.Create("user-1", e); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		e.Name = "Incline Press"
+		e.Sets = e.Sets[:1]
+		if err := repo.Update("user-1", e); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "ex-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if !reflect.DeepEqual(got, e) {
+			t.Errorf("after Update = %+v, want %+v", got, e)
+		}
+		// Lookups must follow the new name.
+		if byName, _ := repo.ListByName("user-1", "bench press"); len(byName) != 0 {
+			t.Errorf("ListByName old name = %v, want none", exerciseIDs(byName))
+		}
+		if byName, _ := repo.ListByName("user-1", "incline press"); len(byName) != 1 {
+			t.Errorf("ListByName new name = %v, want [ex-1]", exerciseIDs(byName))
+		}
+
+		if err := repo.Delete("user-1", "ex-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.GetByID("user-1", "ex-1"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("GetByID after Delete: err = %v, want ErrExerciseNotFound", err)
+		}
+	})
+
+	t.Run("Trash", func(t *testing.T) {
+		repo := newRepo(t)
+		bench := newExercise("ex-1", "Bench Press", models.ExerciseTypeWeights)
+		for _, e := range []*models.Exercise{bench, newExercise("ex-2", "Bench Dip", models.ExerciseTypeWeights)} {
+			if err := repo.Create("user-1", e); err != nil {
+				t.Fatalf("Create %s: %v", e.ExerciseID, err)
+			}
+		}
+		if err := repo.Delete("user-1", "ex-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		// Deleted exercises are hidden from every read and write...
+		if _, err := repo.GetByID("user-1", "ex-1"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("GetByID deleted: err = %v, want ErrExerciseNotFound", err)
+		}
+		if err := repo.Update("user-1", newExercise("ex-1", "Bench Press", models.ExerciseTypeWeights)); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("Update deleted: err = %v, want ErrExerciseNotFound", err)
+		}
+		if got, _ := repo.GetMany("user-1", []string{"ex-1", "ex-2"}); !reflect.DeepEqual(exerciseIDs(got), []string{"ex-2"}) {
+			t.Errorf("GetMany = %v, want [ex-2]", exerciseIDs(got))
+		}
+		if got, _ := repo.ListByName("user-1", "bench press"); len(got) != 0 {
+			t.Errorf("ListByName = %v, want none", exerciseIDs(got))
+		}
+		if got := exerciseIDs(collect(t, func(opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+			return repo.ListByNamePrefix("user-1", "bench", opts)
+		}, false)); !reflect.DeepEqual(got, []string{"ex-2"}) {
+			t.Errorf("ListByNamePrefix = %v, want [ex-2]", got)
+		}
+		if got := exerciseIDs(collect(t, func(opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+			return repo.ListByType("user-1", models.ExerciseTypeWeights, opts)
+		}, false)); !reflect.DeepEqual(got, []string{"ex-2"}) {
+			t.Errorf("ListByType = %v, want [ex-2]", got)
+		}
+		// ...but keep their ID.
+		if err := repo.Create("user-1", newExercise("ex-1", "Bench Press", models.ExerciseTypeWeights)); !errors.Is(err, models.ErrExerciseAlreadyExists) {
+			t.Errorf("Create over deleted: err = %v, want ErrExerciseAlreadyExists", err)
+		}
+
+		trash := collect(t, func(opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+			return repo.ListDeleted("user-1", opts)
+		}, false)
+		if len(trash) != 1 || trash[0].ExerciseID != "ex-1" || trash[0].DeletedAt == nil || len(trash[0].Sets) != 3 {
+			t.Fatalf("ListDeleted = %+v, want ex-1 with its sets", trash)
+		}
+
+		if err := repo.Restore("user-1", "ex-1"); err != nil {
+			t.Fatalf("Restore: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "ex-1")
+		if err != nil {
+			t.Fatalf("GetByID after Restore: %v", err)
+		}
+		if !reflect.DeepEqual(got, bench) {
+			t.Errorf("restored exercise = %+v, want %+v", got, bench)
+		}
+		if err := repo.Restore("user-1", "ex-1"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("Restore twice: err = %v, want ErrExerciseNotFound", err)
+		}
+
+		if err := repo.Purge("user-1", "ex-2"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("Purge live exercise: err = %v, want ErrExerciseNotFound", err)
+		}
+		if err := repo.Delete("user-1", "ex-2"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if err := repo.Purge("user-1", "ex-2"); err != nil {
+			t.Fatalf("Purge: %v", err)
+		}
+		if page, err := repo.ListDeleted("user-1", repository.ListOptions{}); err != nil || len(page.Items) != 0 {
+			t.Errorf("ListDeleted after Purge = %v, %v; want none", page, err)
+		}
+		// An exercise recreated under the same ID must not inherit the purged
+		// one's sets.
+		again := newExercise("ex-2", "Bench Dip", models.ExerciseTypeWeights)
+		again.Sets = again.Sets[:1]
+		if err := repo.Create("user-1", again); err != nil {
+			t.Errorf("Create after Purge: %v", err)
+		}
+		if got, err := repo.GetByID("user-1", "ex-2"); err != nil || !reflect.DeepEqual(got, again) {
+			t.Errorf("recreated exercise = %+v, %v; want %+v", got, err, again)
+		}
+	})
+
+	t.Run("UserIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create("user-1", newExercise("ex-1", "Bench Press", models.ExerciseTypeWeights)); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := repo.GetByID("user-2", "ex-1"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("GetByID other user: err = %v, want ErrExerciseNotFound", err)
+		}
+		if err := repo.Delete("user-2", "ex-1"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("Delete other user: err = %v, want ErrExerciseNotFound", err)
+		}
+		if byName, _ := repo.ListByName("user-2", "bench press"); len(byName) != 0 {
+			t.Errorf("ListByName other user = %v, want none", exerciseIDs(byName))
+		}
+	})
+
+	t.Run("ListByUserID", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, id := range []string{"ex-3", "ex-1", "ex-5", "ex-2", "ex-4"} {
+			if err := repo.Create("user-1", newExercise(id, "Squat", models.ExerciseTypeWeights)); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+		repo.Create("user-2", newExercise("ex-other", "Squat", models.ExerciseTypeWeights))
+
+		list := func(opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+			return repo.ListByUserID("user-1", opts)
+		}
+		if got, want := exerciseIDs(collect(t, list, false)), []string{"ex-1", "ex-2", "ex-3", "ex-4", "ex-5"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ascending = %v, want %v", got, want)
+		}
+		if got, want := exerciseIDs(collect(t, list, true)), []string{"ex-5", "ex-4", "ex-3", "ex-2", "ex-1"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("descending = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Lookups", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, e := range []*models.Exercise{
+			newExercise("ex-1", "Bench Press", models.ExerciseTypeWeights),
+			newExercise("ex-2", "bench  press", models.ExerciseTypeWeights),
+			newExercise("ex-3", "Bent Over Row", models.ExerciseTypeWeights),
+			newExercise("ex-4", "Rowing", models.ExerciseTypeCardio),
+			newExercise("ex-5", "Air Bike", models.ExerciseTypeCardio),
+			newExercise("ex-6", "Deadlift", models.ExerciseTypeWeights),
+		} {
+			if err := repo.Create("user-1", e); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+		repo.Create("user-2", newExercise("ex-other", "Bench Press", models.ExerciseTypeWeights))
+
+		t.Run("ListByName ignores case and spacing", func(t *testing.T) {
+			got, err := repo.ListByName("user-1", " BENCH   press")
+			if err != nil {
+				t.Fatalf("ListByName: %v", err)
+			}
+			if ids := sorted(exerciseIDs(got)); !reflect.DeepEqual(ids, []string{"ex-1", "ex-2"}) {
+				t.Errorf("got %v, want [ex-1 ex-2]", ids)
+			}
+		})
+
+		t.Run("ListByNamePrefix", func(t *testing.T) {
+			got := collect(t, func(opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+				return repo.ListByNamePrefix("user-1", "Ben", opts)
+			}, false)
+			if ids := sorted(exerciseIDs(got)); !reflect.DeepEqual(ids, []string{"ex-1", "ex-2", "ex-3"}) {
+				t.Errorf("got %v, want [ex-1 ex-2 ex-3]", ids)
+			}
+			// Ordered by name: both bench presses before the row.
+			if len(got) == 3 && got[2].ExerciseID != "ex-3" {
+				t.Errorf("got %v, want ex-3 last", exerciseIDs(got))
+			}
+		})
+
+		t.Run("ListByType orders by name", func(t *testing.T) {
+			list := func(opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+				return repo.ListByType("user-1", models.ExerciseTypeCardio, opts)
+			}
+			if got := exerciseIDs(collect(t, list, false)); !reflect.DeepEqual(got, []string{"ex-5", "ex-4"}) {
+				t.Errorf("ascending = %v, want [ex-5 ex-4]", got)
+			}
+			if got := exerciseIDs(collect(t, list, true)); !reflect.DeepEqual(got, []string{"ex-4", "ex-5"}) {
+				t.Errorf("descending = %v, want [ex-4 ex-5]", got)
+			}
+		})
+
+		t.Run("GetMany skips missing, duplicate and foreign IDs", func(t *testing.T) {
+			got, err := repo.GetMany("user-1", []string{"ex-3", "missing", "ex-1", "ex-3", "ex-other"})
+			if err != nil {
+				t.Fatalf("GetMany: %v", err)
+			}
+			if ids := sorted(exerciseIDs(got)); !reflect.DeepEqual(ids, []string{"ex-1", "ex-3"}) {
+				t.Errorf("got %v, want [ex-1 ex-3]", ids)
+			}
+		})
+	})
+}
@@ -0,0 +1,175 @@
+package repotest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+func newMeasurement(userID, measurementID, date string) *models.Measurement {
+	return &models.Measurement{
+		UserID:         userID,
+		MeasurementID:  measurementID,
+		Date:           date,
+		Bodyweight:     81.4,
+		WeightUnit:     models.UnitKg,
+		BodyFatPercent: 17.5,
+		Waist:          84,
+		Arms:           38.5,
+		LengthUnit:     models.LengthCm,
+	}
+}
+
+func measurementIDs(measurements []*models.Measurement) []string {
+	ids := make([]string, len(measurements))
+	for i, m := range measurements {
+		ids[i] = m.MeasurementID
+	}
+	return ids
+}
+
+// RunMeasurementRepositoryTests runs the measurement conformance suite against
+// the repositories returned by newRepo.
+func RunMeasurementRepositoryTests(t *testing.T, newRepo MeasurementFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		m := newMeasurement("user-1", "m-1", "2024-01-15")
+		if err := repo.Create(m); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if m.CreatedAt.IsZero() {
+			t.Error("Create should set CreatedAt")
+		}
+
+		got, err := repo.GetByID("user-1", "m-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if !got.CreatedAt.Equal(m.CreatedAt) {
+			t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, m.CreatedAt)
+		}
+		got.CreatedAt = m.CreatedAt
+		if !reflect.DeepEqual(got, m) {
+			t.Errorf("GetByID = %+v, want %+v", got, m)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetByID("user-1", "missing"); !errors.Is(err, models.ErrMeasurementNotFound) {
+			t.Errorf("GetByID: err = %v, want ErrMeasurementNotFound", err)
+		}
+		if err := repo.Update(newMeasurement("user-1", "missing", "2024-01-15")); !errors.Is(err, models.ErrMeasurementNotFound) {
+			t.Errorf("Update: err = %v, want ErrMeasurementNotFound", err)
+		}
+		if err := repo.Delete("user-1", "missing"); !errors.Is(err, models.ErrMeasurementNotFound) {
+			t.Errorf("Delete: err = %v, want ErrMeasurementNotFound", err)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newMeasurement("user-1", "m-1", "2024-01-15")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Create(newMeasurement("user-1", "m-1", "2024-02-01")); !errors.Is(err, models.ErrMeasurementAlreadyExists) {
+			t.Errorf("Create duplicate: err = %v, want ErrMeasurementAlreadyExists", err)
+		}
+		got, _ := repo.GetByID("user-1", "m-1")
+		if got == nil || got.Date != "2024-01-15" {
+			t.Errorf("duplicate Create overwrote the stored measurement: %+v", got)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		m := newMeasurement("user-1", "m-1", "2024-01-15")
+		if err := repo.Create(m); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		m.Date = "2024-01-16"
+		m.Bodyweight = 180
+		m.WeightUnit = models.UnitLb
+		m.Waist = 0
+		if err := repo.Update(m); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "m-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Date != "2024-01-16" || got.Bodyweight != 180 || got.WeightUnit != models.UnitLb || got.Waist != 0 {
+			t.Errorf("after Update = %+v", got)
+		}
+
+		if err := repo.Delete("user-1", "m-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.GetByID("user-1", "m-1"); !errors.Is(err, models.ErrMeasurementNotFound) {
+			t.Errorf("GetByID after Delete: err = %v, want ErrMeasurementNotFound", err)
+		}
+	})
+
+	t.Run("UserIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newMeasurement("user-1", "m-1", "2024-01-15")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := repo.GetByID("user-2", "m-1"); !errors.Is(err, models.ErrMeasurementNotFound) {
+			t.Errorf("GetByID other user: err = %v, want ErrMeasurementNotFound", err)
+		}
+		if err := repo.Delete("user-2", "m-1"); !errors.Is(err, models.ErrMeasurementNotFound) {
+			t.Errorf("Delete other user: err = %v, want ErrMeasurementNotFound", err)
+		}
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.ListByDateRange("user-1", repository.DateRange{}, repository.ListOptions{Cursor: "%%%"}); !errors.Is(err, models.ErrInvalidCursor) {
+			t.Errorf("err = %v, want ErrInvalidCursor", err)
+		}
+	})
+
+	t.Run("ListByDateRange", func(t *testing.T) {
+		repo := newRepo(t)
+		for id, date := range map[string]string{
+			"feb": "2024-02-20",
+			"mar": "2024-03-01",
+			"apr": "2024-04-10",
+			"may": "2024-05-31",
+			"jun": "2024-06-01",
+		} {
+			if err := repo.Create(newMeasurement("user-1", id, date)); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+		repo.Create(newMeasurement("user-2", "other", "2024-04-01"))
+
+		tests := []struct {
+			name       string
+			dateRange  repository.DateRange
+			descending bool
+			want       []string
+		}{
+			{"all", repository.DateRange{}, false, []string{"feb", "mar", "apr", "may", "jun"}},
+			{"inclusive bounds", repository.DateRange{From: "2024-03-01", To: "2024-05-31"}, false, []string{"mar", "apr", "may"}},
+			{"descending", repository.DateRange{From: "2024-03-01", To: "2024-05-31"}, true, []string{"may", "apr", "mar"}},
+			{"from only", repository.DateRange{From: "2024-05-01"}, false, []string{"may", "jun"}},
+			{"to only", repository.DateRange{To: "2024-02-28"}, false, []string{"feb"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := measurementIDs(collect(t, func(opts repository.ListOptions) (*repository.Page[*models.Measurement], error) {
+					return repo.ListByDateRange("user-1", tt.dateRange, opts)
+				}, tt.descending))
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package repotest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+)
+
+func newProfile(userID string) *models.UserProfile {
+	return &models.UserProfile{
+		UserID:      userID,
+		DisplayName: "Sam",
+		Bodyweight: []models.BodyweightEntry{
+			{Date: "2024-01-01", Weight: 180, Unit: models.UnitLb},
+			{Date: "2024-02-01", Weight: 176.5, Unit: models.UnitLb},
+		},
+		Units:              models.UnitPreferences{Weight: models.UnitLb, Distance: models.DistanceMiles},
+		Timezone:           "America/New_York",
+		WeekStart:          "sunday",
+		DefaultRestSeconds: 120,
+	}
+}
+
+// RunProfileRepositoryTests runs the profile conformance suite against the
+// repositories returned by newRepo.
+func RunProfileRepositoryTests(t *testing.T, newRepo ProfileFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		profile := newProfile("user-1")
+		if err := repo.Create(profile); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if profile.CreatedAt.IsZero() || profile.UpdatedAt.IsZero() {
+			t.Error("Create should set CreatedAt and UpdatedAt")
+		}
+
+		got, err := repo.GetByUserID("user-1")
+		if err != nil {
+			t.Fatalf("GetByUserID: %v", err)
+		}
+		if !sameSettings(got, profile) {
+			t.Errorf("GetByUserID = %+v, want %+v", got, profile)
+		}
+		if !got.CreatedAt.Equal(profile.CreatedAt) || !got.UpdatedAt.Equal(profile.UpdatedAt) {
+			t.Errorf("timestamps = %v/%v, want %v/%v", got.CreatedAt, got.UpdatedAt, profile.CreatedAt, profile.UpdatedAt)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetByUserID("missing"); !errors.Is(err, models.ErrProfileNotFound) {
+			t.Errorf("GetByUserID: err = %v, want ErrProfileNotFound", err)
+		}
+		if err := repo.Update(newProfile("missing")); !errors.Is(err, models.ErrProfileNotFound) {
+			t.Errorf("Update: err = %v, want ErrProfileNotFound", err)
+		}
+		if err := repo.Delete("missing"); !errors.Is(err, models.ErrProfileNotFound) {
+			t.Errorf("Delete: err = %v, want ErrProfileNotFound", err)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newProfile("user-1")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		dup := newProfile("user-1")
+		dup.Units = models.DefaultUnits
+		if err := repo.Create(dup); !errors.Is(err, models.ErrProfileAlreadyExists) {
+			t.Errorf("Create duplicate: err = %v, want ErrProfileAlreadyExists", err)
+		}
+		got, _ := repo.GetByUserID("user-1")
+		if got == nil || got.Units.Weight != models.UnitLb {
+			t.Errorf("duplicate Create overwrote the stored profile: %+v", got)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		profile := newProfile("user-1")
+		if err := repo.Create(profile); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		profile.Units = models.DefaultUnits
+		profile.Timezone = "Europe/London"
+		profile.Bodyweight = []models.BodyweightEntry{{Date: "2024-03-01", Weight: 80, Unit: models.UnitKg}}
+		if err := repo.Update(profile); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByUserID("user-1")
+		if err != nil {
+			t.Fatalf("GetByUserID: %v", err)
+		}
+		if !sameSettings(got, profile) {
+			t.Errorf("after Update = %+v, want %+v", got, profile)
+		}
+
+		if err := repo.Delete("user-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.GetByUserID("user-1"); !errors.Is(err, models.ErrProfileNotFound) {
+			t.Errorf("GetByUserID after Delete: err = %v, want ErrProfileNotFound", err)
+		}
+	})
+
+	t.Run("DeleteRemovesChildren", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newProfile("user-1")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Delete("user-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		// A profile recreated for the same user must not inherit the old
+		// bodyweight log.
+		again := newProfile("user-1")
+		again.Bodyweight = again.Bodyweight[1:]
+		if err := repo.Create(again); err != nil {
+			t.Fatalf("Create after Delete: %v", err)
+		}
+		got, err := repo.GetByUserID("user-1")
+		if err != nil {
+			t.Fatalf("GetByUserID: %v", err)
+		}
+		if !sameSettings(got, again) {
+			t.Errorf("recreated profile = %+v, want %+v", got, again)
+		}
+	})
+}
+
+// sameSettings compares everything but the timestamps.
+func sameSettings(got, want *models.UserProfile) bool {
+	g, w := *got, *want
+	g.CreatedAt, g.UpdatedAt = w.CreatedAt, w.UpdatedAt
+	return reflect.DeepEqual(g, w)
+}
@@ -0,0 +1,248 @@
+package repotest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+func newProgram(userID, programID string) *models.Program {
+	return &models.Program{
+		UserID:    userID,
+		ProgramID: programID,
+		Name:      "Strength Block",
+		Weeks: []models.ProgramWeek{
+			{Days: []models.ProgramDay{{Day: 1, TemplateID: "push"}, {Day: 3, TemplateID: "pull"}}},
+			{Days: []models.ProgramDay{{Day: 1, TemplateID: "push"}}},
+			{},
+			{Days: []models.ProgramDay{{Day: 2, TemplateID: "legs"}}, Deload: true},
+		},
+		Progression: models.Progression{WeeklyIncrement: 2.5, DeloadPercent: 60},
+	}
+}
+
+// normalizeProgram makes empty and nil slices compare equal, since backends
+// are free to return either.
+func normalizeProgram(p *models.Program) models.Program {
+	c := *p
+	c.CreatedAt = time.Time{}
+	c.Weeks = append([]models.ProgramWeek{}, p.Weeks...)
+	for i := range c.Weeks {
+		c.Weeks[i].Days = append([]models.ProgramDay{}, c.Weeks[i].Days...)
+	}
+	if p.Enrollment != nil {
+		e := *p.Enrollment
+		e.EnrolledAt = time.Time{}
+		e.Sessions = append([]models.ProgramSession{}, e.Sessions...)
+		c.Enrollment = &e
+	}
+	return c
+}
+
+func programIDs(programs []*models.Program) []string {
+	ids := make([]string, len(programs))
+	for i, p := range programs {
+		ids[i] = p.ProgramID
+	}
+	return ids
+}
+
+// RunProgramRepositoryTests runs the program conformance suite against the
+// repositories returned by newRepo.
+func RunProgramRepositoryTests(t *testing.T, newRepo ProgramFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		p := newProgram("user-1", "p-1")
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if p.CreatedAt.IsZero() {
+			t.Error("Create should set CreatedAt")
+		}
+
+		got, err := repo.GetByID("user-1", "p-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if !reflect.DeepEqual(normalizeProgram(got), normalizeProgram(p)) {
+			t.Errorf("GetByID = %+v, want %+v", got, p)
+		}
+		if got.Enrollment != nil {
+			t.Errorf("Enrollment = %+v, want nil", got.Enrollment)
+		}
+		if !got.CreatedAt.Equal(p.CreatedAt) {
+			t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, p.CreatedAt)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetByID("user-1", "missing"); !errors.Is(err, models.ErrProgramNotFound) {
+			t.Errorf("GetByID: err = %v, want ErrProgramNotFound", err)
+		}
+		if err := repo.Update(newProgram("user-1", "missing")); !errors.Is(err, models.ErrProgramNotFound) {
+			t.Errorf("Update: err = %v, want ErrProgramNotFound", err)
+		}
+		if err := repo.Delete("user-1", "missing"); !errors.Is(err, models.ErrProgramNotFound) {
+			t.Errorf("Delete: err = %v, want ErrProgramNotFound", err)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newProgram("user-1", "p-1")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Create(newProgram("user-1", "p-1")); !errors.Is(err, models.ErrProgramAlreadyExists) {
+			t.Errorf("Create duplicate: err = %v, want ErrProgramAlreadyExists", err)
+		}
+	})
+
+	t.Run("EnrollmentRoundTrip", func(t *testing.T) {
+		repo := newRepo(t)
+		p := newProgram("user-1", "p-1")
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		enrolledAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+		p.Enrollment = &models.Enrollment{
+			StartDate:  "2024-03-04",
+			EnrolledAt: enrolledAt,
+			Sessions: []models.ProgramSession{
+				{Week: 1, Day: 1, Date: "2024-03-04", WorkoutID: "w-1"},
+				{Week: 1, Day: 3, Date: "2024-03-06", WorkoutID: "w-2"},
+			},
+		}
+		p.Weeks = p.Weeks[:2]
+		if err := repo.Update(p); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "p-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if !reflect.DeepEqual(normalizeProgram(got), normalizeProgram(p)) {
+			t.Errorf("after enrolling = %+v, want %+v", got, p)
+		}
+		if got.Enrollment == nil || !got.Enrollment.EnrolledAt.Equal(enrolledAt) {
+			t.Errorf("Enrollment = %+v, want EnrolledAt %v", got.Enrollment, enrolledAt)
+		}
+
+		p.Enrollment = nil
+		if err := repo.Update(p); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, _ = repo.GetByID("user-1", "p-1")
+		if got == nil || got.Enrollment != nil {
+			t.Errorf("Enrollment after unenrolling = %+v, want nil", got)
+		}
+
+		if err := repo.Delete("user-1", "p-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.GetByID("user-1", "p-1"); !errors.Is(err, models.ErrProgramNotFound) {
+			t.Errorf("GetByID after Delete: err = %v, want ErrProgramNotFound", err)
+		}
+	})
+
+	t.Run("DeleteRemovesChildren", func(t *testing.T) {
+		repo := newRepo(t)
+		p := newProgram("user-1", "p-1")
+		p.Enrollment = &models.Enrollment{
+			StartDate:  "2024-03-04",
+			EnrolledAt: time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+			Sessions:   []models.ProgramSession{{Week: 1, Day: 1, Date: "2024-03-04", WorkoutID: "w-1"}},
+		}
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Delete("user-1", "p-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		// A program recreated under the same ID must not inherit the old
+		// weeks, days or sessions.
+		again := newProgram("user-1", "p-1")
+		again.Weeks = again.Weeks[1:2]
+		again.Enrollment = &models.Enrollment{StartDate: "2024-04-01", EnrolledAt: time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC)}
+		if err := repo.Create(again); err != nil {
+			t.Fatalf("Create after Delete: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "p-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if !reflect.DeepEqual(normalizeProgram(got), normalizeProgram(again)) {
+			t.Errorf("recreated program = %+v, want %+v", got, again)
+		}
+	})
+
+	t.Run("UpdateConflict", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newProgram("user-1", "p-1")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		first, _ := repo.GetByID("user-1", "p-1")
+		second, _ := repo.GetByID("user-1", "p-1")
+
+		first.Name = "First"
+		if err := repo.Update(first); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		second.Name = "Second"
+		if err := repo.Update(second); !errors.Is(err, models.ErrProgramConflict) {
+			t.Errorf("Update from a stale copy: err = %v, want ErrProgramConflict", err)
+		}
+		// The copy the first update returned stays current.
+		first.Name = "First again"
+		if err := repo.Update(first); err != nil {
+			t.Errorf("Update after Update: %v", err)
+		}
+		if got, _ := repo.GetByID("user-1", "p-1"); got == nil || got.Name != "First again" {
+			t.Errorf("stored program = %+v, want the first copy's changes", got)
+		}
+	})
+
+	t.Run("UserIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newProgram("user-1", "p-1")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := repo.GetByID("user-2", "p-1"); !errors.Is(err, models.ErrProgramNotFound) {
+			t.Errorf("GetByID other user: err = %v, want ErrProgramNotFound", err)
+		}
+		if err := repo.Delete("user-2", "p-1"); !errors.Is(err, models.ErrProgramNotFound) {
+			t.Errorf("Delete other user: err = %v, want ErrProgramNotFound", err)
+		}
+	})
+
+	t.Run("ListByUserID", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, id := range []string{"p-3", "p-1", "p-2"} {
+			if err := repo.Create(newProgram("user-1", id)); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+		repo.Create(newProgram("user-2", "p-other"))
+
+		list := func(opts repository.ListOptions) (*repository.Page[*models.Program], error) {
+			return repo.ListByUserID("user-1", opts)
+		}
+		if got, want := programIDs(collect(t, list, false)), []string{"p-1", "p-2", "p-3"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ascending = %v, want %v", got, want)
+		}
+		if got, want := programIDs(collect(t, list, true)), []string{"p-3", "p-2", "p-1"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("descending = %v, want %v", got, want)
+		}
+		for _, p := range collect(t, list, false) {
+			if len(p.Weeks) != 4 {
+				t.Errorf("listed program %s has %d weeks, want 4", p.ProgramID, len(p.Weeks))
+			}
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package repotest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+var recordEpoch = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func newRecord(userID, recordID, exerciseName, exerciseID string, day int) *models.PersonalRecord {
+	return &models.PersonalRecord{
+		UserID:       userID,
+		RecordID:     recordID,
+		ExerciseName: exerciseName,
+		ExerciseID:   exerciseID,
+		Kind:         models.RecordRepsAtWeight,
+		Qualifier:    "100 kg",
+		Value:        float64(5 + day),
+		Unit:         "reps",
+		Previous:     float64(4 + day),
+		AchievedAt:   recordEpoch.AddDate(0, 0, day),
+	}
+}
+
+func recordIDs(records []*models.PersonalRecord) []string {
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.RecordID
+	}
+	return ids
+}
+
+// RunRecordRepositoryTests runs the personal record conformance suite against
+// the repositories returned by newRepo.
+func RunRecordRepositoryTests(t *testing.T, newRepo RecordFactory) {
+	t.Run("CreateAndListByName", func(t *testing.T) {
+		repo := newRepo(t)
+		rec := newRecord("user-1", "r-1", "Bench Press", "ex-1", 0)
+		if err := repo.Create(rec); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		got, err := repo.ListByName("user-1", "  bench   PRESS ")
+		if err != nil {
+			t.Fatalf("ListByName: %v", err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("ListByName returned %d records, want 1", len(got))
+		}
+		if !got[0].AchievedAt.Equal(rec.AchievedAt) {
+			t.Errorf("AchievedAt = %v, want %v", got[0].AchievedAt, rec.AchievedAt)
+		}
+		got[0].AchievedAt = rec.AchievedAt
+		if !reflect.DeepEqual(got[0], rec) {
+			t.Errorf("ListByName = %+v, want %+v", got[0], rec)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newRecord("user-1", "r-1", "Bench Press", "ex-1", 0)); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Create(newRecord("user-1", "r-1", "Squat", "ex-2", 1)); !errors.Is(err, models.ErrRecordAlreadyExists) {
+			t.Errorf("Create duplicate: err = %v, want ErrRecordAlreadyExists", err)
+		}
+	})
+
+	t.Run("ListByNameOldestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, r := range []*models.PersonalRecord{
+			newRecord("user-1", "r-a", "Squat", "ex-3", 2),
+			newRecord("user-1", "r-b", "Squat", "ex-1", 0),
+			newRecord("user-1", "r-c", "Bench Press", "ex-2", 1),
+			newRecord("user-1", "r-d", "Squat", "ex-2", 1),
+		} {
+			if err := repo.Create(r); err != nil {
+				t.Fatalf("Create %s: %v", r.RecordID, err)
+			}
+		}
+
+		got, err := repo.ListByName("user-1", "squat")
+		if err != nil {
+			t.Fatalf("ListByName: %v", err)
+		}
+		if ids, want := recordIDs(got), []string{"r-b", "r-d", "r-a"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("ListByName = %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("ListByUserIDPaginates", func(t *testing.T) {
+		repo := newRepo(t)
+		want := []string{"r-1", "r-2", "r-3", "r-4", "r-5"}
+		for i, id := range want {
+			if err := repo.Create(newRecord("user-1", id, "Squat", "ex-1", i)); err != nil {
+				t.Fatalf("Create %s: %v", id, err)
+			}
+		}
+		repo.Create(newRecord("user-2", "r-x", "Squat", "ex-1", 0))
+
+		got := collect(t, func(opts repository.ListOptions) (*repository.Page[*models.PersonalRecord], error) {
+			return repo.ListByUserID("user-1", opts)
+		}, false)
+		if ids := recordIDs(got); !reflect.DeepEqual(ids, want) {
+			t.Errorf("ListByUserID = %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("DeleteByExercise", func(t *testing.T) {
+		repo := newRepo(t)
+		repo.Create(newRecord("user-1", "r-1", "Squat", "ex-1", 0))
+		repo.Create(newRecord("user-1", "r-2", "Squat", "ex-1", 1))
+		repo.Create(newRecord("user-1", "r-3", "Squat", "ex-2", 2))
+		repo.Create(newRecord("user-2", "r-4", "Squat", "ex-1", 0))
+
+		if err := repo.DeleteByExercise("user-1", "ex-1"); err != nil {
+			t.Fatalf("DeleteByExercise: %v", err)
+		}
+		if err := repo.DeleteByExercise("user-1", "missing"); err != nil {
+			t.Errorf("DeleteByExercise with no records: %v", err)
+		}
+
+		got, _ := repo.ListByName("user-1", "Squat")
+		if ids := recordIDs(got); !reflect.DeepEqual(ids, []string{"r-3"}) {
+			t.Errorf("after DeleteByExercise user-1 has %v, want [r-3]", ids)
+		}
+		other, _ := repo.ListByName("user-2", "Squat")
+		if len(other) != 1 {
+			t.Errorf("DeleteByExercise touched another user's records: %d left, want 1", len(other))
+		}
+	})
+
+	t.Run("UserIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		repo.Create(newRecord("user-1", "r-1", "Squat", "ex-1", 0))
+
+		got, err := repo.ListByName("user-2", "Squat")
+		if err != nil {
+			t.Fatalf("ListByName: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("user-2 sees %d of user-1's records", len(got))
+		}
+	})
+}
@@ -0,0 +1,77 @@
+// Package repotest is a conformance suite for repository implementations.
+// Every storage backend runs the same tests so their behaviour cannot drift:
+//
+//	func TestConformance(t *testing.T) {
+//		repotest.RunWorkoutRepositoryTests(t, func(t *testing.T) repository.WorkoutRepository {
+//			return NewWorkoutRepository()
+//		})
+//	}
+//
+// Each subtest asks the factory for a fresh, empty repository.
+package repotest
+
+import (
+	"sort"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// WorkoutFactory returns an empty WorkoutRepository for one subtest.
+type WorkoutFactory func(t *testing.T) repository.WorkoutRepository
+
+// ExerciseFactory returns an empty ExerciseRepository for one subtest.
+type ExerciseFactory func(t *testing.T) repository.ExerciseRepository
+
+// TemplateFactory returns an empty TemplateRepository for one subtest.
+type TemplateFactory func(t *testing.T) repository.TemplateRepository
+
+// ProgramFactory returns an empty ProgramRepository for one subtest.
+type ProgramFactory func(t *testing.T) repository.ProgramRepository
+
+// RecordFactory returns an empty RecordRepository for one subtest.
+type RecordFactory func(t *testing.T) repository.RecordRepository
+
+// ProfileFactory returns an empty ProfileRepository for one subtest.
+type ProfileFactory func(t *testing.T) repository.ProfileRepository
+
+// MeasurementFactory returns an empty MeasurementRepository for one subtest.
+type MeasurementFactory func(t *testing.T) repository.MeasurementRepository
+
+// collect reads every page of a listing using a small page size, so that
+// cursor handling is exercised as well as filtering and ordering.
+func collect[T any](t *testing.T, list func(opts repository.ListOptions) (*repository.Page[T], error), descending bool) []T {
+	t.Helper()
+	items, err := repository.CollectAll(func(opts repository.ListOptions) (*repository.Page[T], error) {
+		opts.Limit = 2
+		opts.Descending = descending
+		return list(opts)
+	})
+	if err != nil {
+		t.Fatalf("listing failed: %v", err)
+	}
+	return items
+}
+
+func workoutIDs(workouts []*models.Workout) []string {
+	ids := make([]string, len(workouts))
+	for i, w := range workouts {
+		ids[i] = w.WorkoutID
+	}
+	return ids
+}
+
+func exerciseIDs(exercises []*models.Exercise) []string {
+	ids := make([]string, len(exercises))
+	for i, e := range exercises {
+		ids[i] = e.ExerciseID
+	}
+	return ids
+}
+
+func sorted(ids []string) []string {
+	out := append([]string{}, ids...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,181 @@
+package repotest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+func newTemplate(userID, templateID string) *models.Template {
+	return &models.Template{
+		UserID:     userID,
+		TemplateID: templateID,
+		Name:       "Push Day A",
+		Exercises: []models.ExercisePrescription{
+			{Name: "Bench Press", ExerciseType: models.ExerciseTypeWeights, TargetSets: 3, TargetReps: 5, TargetWeight: 80, Unit: "kg"},
+			{Name: "Plank", ExerciseType: models.ExerciseTypeBodyWeight, TargetSets: 2},
+		},
+	}
+}
+
+func templateIDs(templates []*models.Template) []string {
+	ids := make([]string, len(templates))
+	for i, t := range templates {
+		ids[i] = t.TemplateID
+	}
+	return ids
+}
+
+// RunTemplateRepositoryTests runs the template conformance suite against the
+// repositories returned by newRepo.
+func RunTemplateRepositoryTests(t *testing.T, newRepo TemplateFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		tmpl := newTemplate("user-1", "t-1")
+		if err := repo.Create(tmpl); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if tmpl.CreatedAt.IsZero() {
+			t.Error("Create should set CreatedAt")
+		}
+
+		got, err := repo.GetByID("user-1", "t-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.UserID != tmpl.UserID || got.TemplateID != tmpl.TemplateID || got.Name != tmpl.Name {
+			t.Errorf("GetByID = %+v, want %+v", got, tmpl)
+		}
+		if !reflect.DeepEqual(got.Exercises, tmpl.Exercises) {
+			t.Errorf("Exercises = %+v, want %+v in stored order", got.Exercises, tmpl.Exercises)
+		}
+		if !got.CreatedAt.Equal(tmpl.CreatedAt) {
+			t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, tmpl.CreatedAt)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetByID("user-1", "missing"); !errors.Is(err, models.ErrTemplateNotFound) {
+			t.Errorf("GetByID: err = %v, want ErrTemplateNotFound", err)
+		}
+		if err := repo.Update(newTemplate("user-1", "missing")); !errors.Is(err, models.ErrTemplateNotFound) {
+			t.Errorf("Update: err = %v, want ErrTemplateNotFound", err)
+		}
+		if err := repo.Delete("user-1", "missing"); !errors.Is(err, models.ErrTemplateNotFound) {
+			t.Errorf("Delete: err = %v, want ErrTemplateNotFound", err)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newTemplate("user-1", "t-1")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		dup := newTemplate("user-1", "t-1")
+		dup.Name = "Other"
+		if err := repo.Create(dup); !errors.Is(err, models.ErrTemplateAlreadyExists) {
+			t.Errorf("Create duplicate: err = %v, want ErrTemplateAlreadyExists", err)
+		}
+		got, _ := repo.GetByID("user-1", "t-1")
+		if got == nil || got.Name != "Push Day A" {
+			t.Errorf("duplicate Create overwrote the stored template: %+v", got)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		tmpl := newTemplate("user-1", "t-1")
+		if err := repo.Create(tmpl); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		tmpl.Name = "Push Day B"
+		tmpl.Exercises = []models.ExercisePrescription{
+			{Name: "Overhead Press", ExerciseType: models.ExerciseTypeWeights, TargetSets: 5, TargetReps: 5, TargetWeight: 40, Unit: "kg"},
+		}
+		if err := repo.Update(tmpl); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "t-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Name != "Push Day B" || !reflect.DeepEqual(got.Exercises, tmpl.Exercises) {
+			t.Errorf("after Update = %+v", got)
+		}
+
+		if err := repo.Delete("user-1", "t-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.GetByID("user-1", "t-1"); !errors.Is(err, models.ErrTemplateNotFound) {
+			t.Errorf("GetByID after Delete: err = %v, want ErrTemplateNotFound", err)
+		}
+	})
+
+	t.Run("DeleteRemovesChildren", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newTemplate("user-1", "t-1")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Delete("user-1", "t-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		// A template recreated under the same ID must not inherit the old
+		// prescriptions.
+		again := newTemplate("user-1", "t-1")
+		again.Exercises = again.Exercises[1:]
+		if err := repo.Create(again); err != nil {
+			t.Fatalf("Create after Delete: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "t-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if !reflect.DeepEqual(got.Exercises, again.Exercises) {
+			t.Errorf("Exercises = %+v, want %+v", got.Exercises, again.Exercises)
+		}
+	})
+
+	t.Run("UserIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newTemplate("user-1", "t-1")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := repo.GetByID("user-2", "t-1"); !errors.Is(err, models.ErrTemplateNotFound) {
+			t.Errorf("GetByID other user: err = %v, want ErrTemplateNotFound", err)
+		}
+		if err := repo.Delete("user-2", "t-1"); !errors.Is(err, models.ErrTemplateNotFound) {
+			t.Errorf("Delete other user: err = %v, want ErrTemplateNotFound", err)
+		}
+	})
+
+	t.Run("ListByUserID", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, id := range []string{"t-3", "t-1", "t-2"} {
+			if err := repo.Create(newTemplate("user-1", id)); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+		repo.Create(newTemplate("user-2", "t-other"))
+
+		list := func(opts repository.ListOptions) (*repository.Page[*models.Template], error) {
+			return repo.ListByUserID("user-1", opts)
+		}
+		if got, want := templateIDs(collect(t, list, false)), []string{"t-1", "t-2", "t-3"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ascending = %v, want %v", got, want)
+		}
+		if got, want := templateIDs(collect(t, list, true)), []string{"t-3", "t-2", "t-1"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("descending = %v, want %v", got, want)
+		}
+		for _, tmpl := range collect(t, list, false) {
+			if len(tmpl.Exercises) != 2 {
+				t.Errorf("listed template %s has %d exercises, want 2", tmpl.TemplateID, len(tmpl.Exercises))
+			}
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// ExpiringWorkoutFactory returns an empty WorkoutRepository whose deleted
+// workouts expire from the trash after retention.
+type ExpiringWorkoutFactory func(t *testing.T, retention time.Duration) repository.WorkoutRepository
+
+// ExpiringExerciseFactory returns an empty ExerciseRepository whose deleted
+// exercises expire from the trash after retention.
+type ExpiringExerciseFactory func(t *testing.T, retention time.Duration) repository.ExerciseRepository
+
+// RunTrashExpiryTests checks that workouts and exercises are gone from the
+// trash once their retention has passed, even though no sweeper or TTL has
+// removed them yet. Expiry counts in whole epoch seconds, so a retention of a
+// nanosecond has passed as soon as Delete returns.
+func RunTrashExpiryTests(t *testing.T, newWorkouts ExpiringWorkoutFactory, newExercises ExpiringExerciseFactory) {
+	t.Run("Workouts", func(t *testing.T) {
+		repo := newWorkouts(t, time.Nanosecond)
+		if err := repo.Create(newWorkout("user-1", "w-1", "2024-01-15")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Delete("w-1", "user-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		if page, err := repo.ListDeleted("user-1", repository.ListOptions{}); err != nil || len(page.Items) != 0 {
+			t.Errorf("ListDeleted = %v, %v; want the expired workout hidden", page, err)
+		}
+		if err := repo.Restore("user-1", "w-1"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("Restore: err = %v, want ErrWorkoutNotFound", err)
+		}
+		if err := repo.Purge("user-1", "w-1"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("Purge: err = %v, want ErrWorkoutNotFound", err)
+		}
+		if sweeper, ok := repo.(repository.TrashSweeper); ok {
+			if n, err := sweeper.PurgeDeletedBefore(time.Now().Add(time.Hour)); err != nil || n != 1 {
+				t.Errorf("PurgeDeletedBefore = %d, %v; want the expired workout swept", n, err)
+			}
+		}
+	})
+
+	t.Run("Exercises", func(t *testing.T) {
+		repo := newExercises(t, time.Nanosecond)
+		if err := repo.Create("user-1", newExercise("ex-1", "Bench Press", models.ExerciseTypeWeights)); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Delete("user-1", "ex-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		if page, err := repo.ListDeleted("user-1", repository.ListOptions{}); err != nil || len(page.Items) != 0 {
+			t.Errorf("ListDeleted = %v, %v; want the expired exercise hidden", page, err)
+		}
+		if err := repo.Restore("user-1", "ex-1"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("Restore: err = %v, want ErrExerciseNotFound", err)
+		}
+		if err := repo.Purge("user-1", "ex-1"); !errors.Is(err, models.ErrExerciseNotFound) {
+			t.Errorf("Purge: err = %v, want ErrExerciseNotFound", err)
+		}
+		if sweeper, ok := repo.(repository.TrashSweeper); ok {
+			if n, err := sweeper.PurgeDeletedBefore(time.Now().Add(time.Hour)); err != nil || n != 1 {
+				t.Errorf("PurgeDeletedBefore = %d, %v; want the expired exercise swept", n, err)
+			}
+		}
+	})
+}
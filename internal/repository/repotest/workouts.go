@@ -0,0 +1,363 @@
+package repotest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+func newWorkout(userID, workoutID, date string) *models.Workout {
+	return &models.Workout{
+		UserID:    userID,
+		WorkoutID: workoutID,
+		Name:      "Push Day",
+		Date:      date,
+		Exercises: []string{"ex-2", "ex-1"},
+	}
+}
+
+// RunWorkoutRepositoryTests runs the workout conformance suite against the
+// repositories returned by newRepo.
+func RunWorkoutRepositoryTests(t *testing.T, newRepo WorkoutFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		w := newWorkout("user-1", "w-1", "2024-01-15")
+		if err := repo.Create(w); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if w.CreatedAt.IsZero() {
+			t.Error("Create should set CreatedAt")
+		}
+
+		got, err := repo.GetByID("user-1", "w-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.UserID != w.UserID || got.WorkoutID != w.WorkoutID || got.Name != w.Name || got.Date != w.Date {
+			t.Errorf("GetByID = %+v, want %+v", got, w)
+		}
+		if !reflect.DeepEqual(got.Exercises, w.Exercises) {
+			t.Errorf("Exercises = %v, want %v in stored order", got.Exercises, w.Exercises)
+		}
+		if !got.CreatedAt.Equal(w.CreatedAt) {
+			t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, w.CreatedAt)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetByID("user-1", "missing"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("GetByID: err = %v, want ErrWorkoutNotFound", err)
+		}
+		if err := repo.Update(newWorkout("user-1", "missing", "2024-01-15")); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("Update: err = %v, want ErrWorkoutNotFound", err)
+		}
+		if err := repo.Delete("missing", "user-1"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("Delete: err = %v, want ErrWorkoutNotFound", err)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newWorkout("user-1", "w-1", "2024-01-15")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := repo.Create(newWorkout("user-1", "w-1", "2024-02-01")); !errors.Is(err, models.ErrWorkoutAlreadyExists) {
+			t.Errorf("Create duplicate: err = %v, want ErrWorkoutAlreadyExists", err)
+		}
+		got, _ := repo.GetByID("user-1", "w-1")
+		if got == nil || got.Date != "2024-01-15" {
+			t.Errorf("duplicate Create overwrote the stored workout: %+v", got)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		w := newWorkout("user-1", "w-1", "2024-01-15")
+		if err := repo.Create(w); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		w.Name = "Pull Day"
+		w.Date = "2024-01-16"
+		w.Exercises = []string{"ex-3"}
+		if err := repo.Update(w); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "w-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Name != "Pull Day" || got.Date != "2024-01-16" || !reflect.DeepEqual(got.Exercises, []string{"ex-3"}) {
+			t.Errorf("after Update = %+v", got)
+		}
+
+		if err := repo.Delete("w-1", "user-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.GetByID("user-1", "w-1"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("GetByID after Delete: err = %v, want ErrWorkoutNotFound", err)
+		}
+	})
+
+	t.Run("Groups", func(t *testing.T) {
+		repo := newRepo(t)
+		w := newWorkout("user-1", "w-1", "2024-01-15")
+		w.Exercises = []string{"ex-1", "ex-2", "ex-3"}
+		w.Groups = []models.ExerciseGroup{
+			{GroupID: "g-1", Kind: models.GroupSuperset, ExerciseIDs: []string{"ex-1", "ex-2"}, Rounds: 3, RestSeconds: 90},
+			models.SingleGroup("ex-3"),
+		}
+		if err := repo.Create(w); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "w-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if !reflect.DeepEqual(got.Groups, w.Groups) {
+			t.Errorf("Groups = %+v, want %+v", got.Groups, w.Groups)
+		}
+
+		w.Exercises = []string{"ex-3", "ex-1"}
+		w.Groups = []models.ExerciseGroup{
+			models.SingleGroup("ex-3"),
+			{GroupID: "g-2", Kind: models.GroupAMRAP, ExerciseIDs: []string{"ex-1"}},
+		}
+		if err := repo.Update(w); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err = repo.GetByID("user-1", "w-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if !reflect.DeepEqual(got.Exercises, w.Exercises) || !reflect.DeepEqual(got.Groups, w.Groups) {
+			t.Errorf("after Update = %v %+v, want %v %+v", got.Exercises, got.Groups, w.Exercises, w.Groups)
+		}
+	})
+
+	t.Run("Session", func(t *testing.T) {
+		repo := newRepo(t)
+		w := newWorkout("user-1", "w-1", "2024-01-15")
+		if err := repo.Create(w); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		started := time.Date(2024, 1, 15, 18, 0, 0, 0, time.UTC)
+		paused := started.Add(20 * time.Minute)
+		w.WorkoutSession = models.WorkoutSession{Status: models.WorkoutInProgress, StartedAt: &started, PausedAt: &paused, PausedSeconds: 90}
+		if err := repo.Update(w); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "w-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != models.WorkoutInProgress || got.StartedAt == nil || !got.StartedAt.Equal(started) ||
+			got.PausedAt == nil || !got.PausedAt.Equal(paused) || got.FinishedAt != nil || got.PausedSeconds != 90 {
+			t.Errorf("in-progress session = %+v", got.WorkoutSession)
+		}
+
+		finished := started.Add(time.Hour)
+		w.WorkoutSession = models.WorkoutSession{Status: models.WorkoutCompleted, StartedAt: &started, FinishedAt: &finished, PausedSeconds: 600, DurationSeconds: 3000}
+		if err := repo.Update(w); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err = repo.GetByID("user-1", "w-1")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != models.WorkoutCompleted || got.PausedAt != nil || got.FinishedAt == nil || !got.FinishedAt.Equal(finished) ||
+			got.DurationSeconds != 3000 {
+			t.Errorf("completed session = %+v", got.WorkoutSession)
+		}
+	})
+
+	t.Run("Trash", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, w := range []*models.Workout{newWorkout("user-1", "w-1", "2024-01-15"), newWorkout("user-1", "w-2", "2024-01-16"), newWorkout("user-1", "w-3", "2024-01-17")} {
+			if err := repo.Create(w); err != nil {
+				t.Fatalf("Create %s: %v", w.WorkoutID, err)
+			}
+		}
+		if err := repo.Delete("w-1", "user-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if err := repo.Delete("w-1", "user-1"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("Delete twice: err = %v, want ErrWorkoutNotFound", err)
+		}
+
+		// Deleted workouts are hidden from every read and write...
+		if _, err := repo.GetByID("user-1", "w-1"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("GetByID deleted: err = %v, want ErrWorkoutNotFound", err)
+		}
+		if err := repo.Update(newWorkout("user-1", "w-1", "2024-01-15")); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("Update deleted: err = %v, want ErrWorkoutNotFound", err)
+		}
+		if got := workoutIDs(collect(t, func(opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+			return repo.ListByUserID("user-1", opts)
+		}, false)); !reflect.DeepEqual(got, []string{"w-2", "w-3"}) {
+			t.Errorf("ListByUserID = %v, want [w-2 w-3]", got)
+		}
+		if got := workoutIDs(collect(t, func(opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+			return repo.ListByDateRange("user-1", repository.DateRange{}, opts)
+		}, false)); !reflect.DeepEqual(got, []string{"w-2", "w-3"}) {
+			t.Errorf("ListByDateRange = %v, want [w-2 w-3]", got)
+		}
+		// ...but keep their ID.
+		if err := repo.Create(newWorkout("user-1", "w-1", "2024-01-15")); !errors.Is(err, models.ErrWorkoutAlreadyExists) {
+			t.Errorf("Create over deleted: err = %v, want ErrWorkoutAlreadyExists", err)
+		}
+
+		trash := collect(t, func(opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+			return repo.ListDeleted("user-1", opts)
+		}, false)
+		if len(trash) != 1 || trash[0].WorkoutID != "w-1" || trash[0].DeletedAt == nil ||
+			!reflect.DeepEqual(trash[0].Exercises, []string{"ex-2", "ex-1"}) {
+			t.Fatalf("ListDeleted = %+v, want w-1 with its exercises", trash)
+		}
+
+		if err := repo.Restore("user-1", "w-2"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("Restore live workout: err = %v, want ErrWorkoutNotFound", err)
+		}
+		if err := repo.Restore("user-1", "w-1"); err != nil {
+			t.Fatalf("Restore: %v", err)
+		}
+		got, err := repo.GetByID("user-1", "w-1")
+		if err != nil {
+			t.Fatalf("GetByID after Restore: %v", err)
+		}
+		if got.DeletedAt != nil || !reflect.DeepEqual(got.Exercises, []string{"ex-2", "ex-1"}) {
+			t.Errorf("restored workout = %+v", got)
+		}
+
+		if err := repo.Purge("user-1", "w-1"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("Purge live workout: err = %v, want ErrWorkoutNotFound", err)
+		}
+		if err := repo.Delete("w-1", "user-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if err := repo.Purge("user-1", "w-1"); err != nil {
+			t.Fatalf("Purge: %v", err)
+		}
+		if page, err := repo.ListDeleted("user-1", repository.ListOptions{}); err != nil || len(page.Items) != 0 {
+			t.Errorf("ListDeleted after Purge = %v, %v; want none", page, err)
+		}
+		// A workout recreated under the same ID must not inherit the purged
+		// one's exercises.
+		again := newWorkout("user-1", "w-1", "2024-01-15")
+		again.Exercises = []string{"ex-3"}
+		if err := repo.Create(again); err != nil {
+			t.Errorf("Create after Purge: %v", err)
+		}
+		if got, err := repo.GetByID("user-1", "w-1"); err != nil || !reflect.DeepEqual(got.Exercises, []string{"ex-3"}) {
+			t.Errorf("recreated workout = %+v, %v; want exercises [ex-3]", got, err)
+		}
+
+		// Backends without TTL expire the trash with a sweeper.
+		if sweeper, ok := repo.(repository.TrashSweeper); ok {
+			if err := repo.Delete("w-2", "user-1"); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if n, err := sweeper.PurgeDeletedBefore(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+				t.Errorf("PurgeDeletedBefore an hour ago = %d, %v; want 0", n, err)
+			}
+			if n, err := sweeper.PurgeDeletedBefore(time.Now().Add(time.Hour)); err != nil || n != 1 {
+				t.Errorf("PurgeDeletedBefore an hour ahead = %d, %v; want 1", n, err)
+			}
+			if page, err := repo.ListDeleted("user-1", repository.ListOptions{}); err != nil || len(page.Items) != 0 {
+				t.Errorf("ListDeleted after sweep = %v, %v; want none", page, err)
+			}
+		}
+	})
+
+	t.Run("UserIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(newWorkout("user-1", "w-1", "2024-01-15")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := repo.GetByID("user-2", "w-1"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("GetByID other user: err = %v, want ErrWorkoutNotFound", err)
+		}
+		if err := repo.Delete("w-1", "user-2"); !errors.Is(err, models.ErrWorkoutNotFound) {
+			t.Errorf("Delete other user: err = %v, want ErrWorkoutNotFound", err)
+		}
+		page, err := repo.ListByUserID("user-2", repository.ListOptions{})
+		if err != nil {
+			t.Fatalf("ListByUserID other user: %v", err)
+		}
+		if len(page.Items) != 0 {
+			t.Errorf("ListByUserID other user = %v, want none", workoutIDs(page.Items))
+		}
+	})
+
+	t.Run("ListByUserID", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, id := range []string{"w-3", "w-1", "w-5", "w-2", "w-4"} {
+			if err := repo.Create(newWorkout("user-1", id, "2024-01-15")); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+		repo.Create(newWorkout("user-2", "w-other", "2024-01-15"))
+
+		list := func(opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+			return repo.ListByUserID("user-1", opts)
+		}
+		if got, want := workoutIDs(collect(t, list, false)), []string{"w-1", "w-2", "w-3", "w-4", "w-5"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ascending = %v, want %v", got, want)
+		}
+		if got, want := workoutIDs(collect(t, list, true)), []string{"w-5", "w-4", "w-3", "w-2", "w-1"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("descending = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.ListByUserID("user-1", repository.ListOptions{Cursor: "%%%"}); !errors.Is(err, models.ErrInvalidCursor) {
+			t.Errorf("err = %v, want ErrInvalidCursor", err)
+		}
+	})
+
+	t.Run("ListByDateRange", func(t *testing.T) {
+		repo := newRepo(t)
+		for id, date := range map[string]string{
+			"feb": "2024-02-20",
+			"mar": "2024-03-01",
+			"apr": "2024-04-10",
+			"may": "2024-05-31T18:00:00Z",
+			"jun": "2024-06-01",
+		} {
+			if err := repo.Create(newWorkout("user-1", id, date)); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+		repo.Create(newWorkout("user-2", "other", "2024-04-01"))
+
+		tests := []struct {
+			name       string
+			dateRange  repository.DateRange
+			descending bool
+			want       []string
+		}{
+			{"all", repository.DateRange{}, false, []string{"feb", "mar", "apr", "may", "jun"}},
+			{"inclusive bounds", repository.DateRange{From: "2024-03-01", To: "2024-05-31"}, false, []string{"mar", "apr", "may"}},
+			{"descending", repository.DateRange{From: "2024-03-01", To: "2024-05-31"}, true, []string{"may", "apr", "mar"}},
+			{"from only", repository.DateRange{From: "2024-05-01"}, false, []string{"may", "jun"}},
+			{"to only", repository.DateRange{To: "2024-02-28"}, false, []string{"feb"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := workoutIDs(collect(t, func(opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+					return repo.ListByDateRange("user-1", tt.dateRange, opts)
+				}, tt.descending))
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,330 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// ExerciseRepository implements repository.ExerciseRepository on the exercises
+// and exercise_sets tables.
+type ExerciseRepository struct {
+	store     *Store
+	retention time.Duration
+}
+
+// WithRetention makes deleted exercises expire from the trash retention after
+// they were deleted, as the DynamoDB repository does. Without it they stay
+// until purged.
+func (r *ExerciseRepository) WithRetention(retention time.Duration) *ExerciseRepository {
+	r.retention = retention
+	return r
+}
+
+const selectExercises = `SELECT user_id, exercise_id, name, exercise_type, time_seconds, distance, distance_unit, original_distance_unit, level, reps, rpm, created_at, deleted_at FROM exercises`
+
+// nameKeyset orders exercises by normalized name, matching the Dynamo name indexes.
+var nameKeyset = keyset{"name_key", "exercise_id"}
+
+func nameKey(e *models.Exercise) []string {
+	return []string{models.NormalizeName(e.Name), e.ExerciseID}
+}
+
+func (r *ExerciseRepository) GetByID(userID, exerciseID string) (*models.Exercise, error) {
+	exercises, err := r.query(selectExercises+` WHERE user_id = ? AND exercise_id = ? AND deleted_at = ''`, userID, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(exercises) == 0 {
+		return nil, models.ErrExerciseNotFound
+	}
+	return exercises[0], nil
+}
+
+func (r *ExerciseRepository) GetMany(userID string, exerciseIDs []string) ([]*models.Exercise, error) {
+	found := make(map[string]*models.Exercise, len(exerciseIDs))
+	err := forEachChunk(exerciseIDs, func(chunk []string) error {
+		exercises, err := r.query(selectExercises+` WHERE user_id = ? AND deleted_at = '' AND exercise_id IN (`+placeholders(len(chunk))+`)`, inArgs(userID, chunk)...)
+		if err != nil {
+			return err
+		}
+		for _, e := range exercises {
+			found[e.ExerciseID] = e
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Return exercises in request order, once each.
+	exercises := make([]*models.Exercise, 0, len(found))
+	for _, id := range exerciseIDs {
+		if e, ok := found[id]; ok {
+			exercises = append(exercises, e)
+			delete(found, id)
+		}
+	}
+	return exercises, nil
+}
+
+func (r *ExerciseRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	return r.list(selectExercises+` WHERE user_id = ? AND deleted_at = ''`, []interface{}{userID},
+		keyset{"exercise_id"}, opts, exerciseIDKey)
+}
+
+func (r *ExerciseRepository) ListByType(userID, exerciseType string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	return r.list(selectExercises+` WHERE user_id = ? AND exercise_type = ? AND deleted_at = ''`, []interface{}{userID, exerciseType},
+		nameKeyset, opts, nameKey)
+}
+
+func (r *ExerciseRepository) ListByName(userID, exerciseName string) ([]*models.Exercise, error) {
+	page, err := r.list(selectExercises+` WHERE user_id = ? AND name_key = ? AND deleted_at = ''`, []interface{}{userID, models.NormalizeName(exerciseName)},
+		nameKeyset, repository.ListOptions{}, nameKey)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+func (r *ExerciseRepository) ListByNamePrefix(userID, prefix string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	pattern := escapeLike(models.NormalizeName(prefix)) + "%"
+	return r.list(selectExercises+` WHERE user_id = ? AND name_key LIKE ? ESCAPE '\' AND deleted_at = ''`, []interface{}{userID, pattern},
+		nameKeyset, opts, nameKey)
+}
+
+func (r *ExerciseRepository) Create(userID string, exercise *models.Exercise) error {
+	if exercise.CreatedAt.IsZero() {
+		exercise.CreatedAt = time.Now().UTC()
+	}
+	return r.store.inTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO exercises
+			(user_id, exercise_id, name, name_key, exercise_type, time_seconds, distance, distance_unit, original_distance_unit, level, reps, rpm, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			userID, exercise.ExerciseID, exercise.Name, models.NormalizeName(exercise.Name), exercise.ExerciseType,
+			exercise.Time, exercise.Distance, exercise.DistanceUnit, exercise.OriginalDistanceUnit, exercise.Level, exercise.Reps, exercise.RPM,
+			exercise.CreatedAt.UTC().Format(time.RFC3339Nano))
+		if err != nil {
+			return insertError(err, models.ErrExerciseAlreadyExists, "exercise")
+		}
+		return r.insertSets(tx, userID, exercise)
+	})
+}
+
+func (r *ExerciseRepository) Update(userID string, exercise *models.Exercise) error {
+	return r.store.inTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(r.store.rebind(`UPDATE exercises SET
+			name = ?, name_key = ?, exercise_type = ?, time_seconds = ?, distance = ?, distance_unit = ?, original_distance_unit = ?, level = ?, reps = ?, rpm = ?
+			WHERE user_id = ? AND exercise_id = ? AND deleted_at = ''`),
+			exercise.Name, models.NormalizeName(exercise.Name), exercise.ExerciseType, exercise.Time, exercise.Distance,
+			exercise.DistanceUnit, exercise.OriginalDistanceUnit, exercise.Level, exercise.Reps, exercise.RPM, userID, exercise.ExerciseID)
+		if err != nil {
+			return fmt.Errorf("failed to update exercise: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return models.ErrExerciseNotFound
+		}
+		if err := r.deleteSets(tx, userID, exercise.ExerciseID); err != nil {
+			return err
+		}
+		return r.insertSets(tx, userID, exercise)
+	})
+}
+
+// Delete moves the exercise to the trash, keeping its sets.
+func (r *ExerciseRepository) Delete(userID string, exerciseID string) error {
+	now := time.Now().UTC()
+	res, err := r.store.db.Exec(r.store.rebind(`UPDATE exercises SET deleted_at = ?, expires_at = ? WHERE user_id = ? AND exercise_id = ? AND deleted_at = ''`),
+		now.Format(time.RFC3339Nano), trashExpiry(now, r.retention), userID, exerciseID)
+	if err != nil {
+		return fmt.Errorf("failed to delete exercise: %w", err)
+	}
+	return r.affected(res)
+}
+
+func (r *ExerciseRepository) ListDeleted(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	return r.list(selectExercises+` WHERE user_id = ? AND `+inTrash, []interface{}{userID, trashNow()},
+		keyset{"exercise_id"}, opts, exerciseIDKey)
+}
+
+func (r *ExerciseRepository) Restore(userID, exerciseID string) error {
+	res, err := r.store.db.Exec(r.store.rebind(`UPDATE exercises SET deleted_at = '', expires_at = 0 WHERE user_id = ? AND exercise_id = ? AND `+inTrash),
+		userID, exerciseID, trashNow())
+	if err != nil {
+		return fmt.Errorf("failed to restore exercise: %w", err)
+	}
+	return r.affected(res)
+}
+
+// Purge removes a deleted exercise; its sets go with it through ON DELETE
+// CASCADE.
+func (r *ExerciseRepository) Purge(userID, exerciseID string) error {
+	return r.purge(`DELETE FROM exercises WHERE user_id = ? AND exercise_id = ? AND `+inTrash, userID, exerciseID, trashNow())
+}
+
+func (r *ExerciseRepository) purge(query string, args ...interface{}) error {
+	res, err := r.store.db.Exec(r.store.rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("failed to purge exercise: %w", err)
+	}
+	return r.affected(res)
+}
+
+func (r *ExerciseRepository) PurgeDeletedBefore(cutoff time.Time) (int, error) {
+	rows, err := r.store.db.Query(`SELECT user_id, exercise_id, deleted_at FROM exercises WHERE deleted_at <> ''`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query deleted exercises: %w", err)
+	}
+	expired, err := scanExpired(rows, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read deleted exercises: %w", err)
+	}
+	purged := 0
+	for _, key := range expired {
+		// Expired rows are gone to Purge, so match any trashed row.
+		if err := r.purge(`DELETE FROM exercises WHERE user_id = ? AND exercise_id = ? AND deleted_at <> ''`, key.userID, key.id); err != nil {
+			if errors.Is(err, models.ErrExerciseNotFound) {
+				continue // restored or purged since the scan
+			}
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// affected maps an UPDATE or DELETE that matched no row to ErrExerciseNotFound.
+func (r *ExerciseRepository) affected(res sql.Result) error {
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrExerciseNotFound
+	}
+	return nil
+}
+
+func exerciseIDKey(e *models.Exercise) []string {
+	return []string{e.ExerciseID}
+}
+
+func (r *ExerciseRepository) list(query string, args []interface{}, keys keyset, opts repository.ListOptions, key func(*models.Exercise) []string) (*repository.Page[*models.Exercise], error) {
+	query, args, err := keys.apply(query, args, opts)
+	if err != nil {
+		return nil, err
+	}
+	exercises, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return page(exercises, opts, key), nil
+}
+
+// query runs an exercise SELECT, which must filter on a single user, and
+// attaches each exercise's sets in order.
+func (r *ExerciseRepository) query(query string, args ...interface{}) ([]*models.Exercise, error) {
+	rows, err := r.store.db.Query(r.store.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exercises: %w", err)
+	}
+	defer rows.Close()
+
+	var userID string
+	var exercises []*models.Exercise
+	for rows.Next() {
+		var e models.Exercise
+		var createdAt, deletedAt string
+		if err := rows.Scan(&userID, &e.ExerciseID, &e.Name, &e.ExerciseType, &e.Time, &e.Distance,
+			&e.DistanceUnit, &e.OriginalDistanceUnit, &e.Level, &e.Reps, &e.RPM, &createdAt, &deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan exercise: %w", err)
+		}
+		if created, err := parseOptionalTime(createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse exercise created_at: %w", err)
+		} else if created != nil {
+			e.CreatedAt = *created
+		}
+		if e.DeletedAt, err = parseOptionalTime(deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse exercise deleted_at: %w", err)
+		}
+		exercises = append(exercises, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read exercises: %w", err)
+	}
+	if len(exercises) == 0 {
+		return exercises, nil
+	}
+	return exercises, r.loadSets(userID, exercises)
+}
+
+func (r *ExerciseRepository) loadSets(userID string, exercises []*models.Exercise) error {
+	byID := make(map[string]*models.Exercise, len(exercises))
+	ids := make([]string, len(exercises))
+	for i, e := range exercises {
+		byID[e.ExerciseID] = e
+		ids[i] = e.ExerciseID
+	}
+
+	return forEachChunk(ids, func(chunk []string) error {
+		rows, err := r.store.db.Query(r.store.rebind(`SELECT exercise_id, weight, unit, original_unit, reps, duration_seconds,
+			set_type, rpe, rir, tempo, rest_seconds, completed FROM exercise_sets
+			WHERE user_id = ? AND exercise_id IN (`+placeholders(len(chunk))+`) ORDER BY exercise_id, position`), inArgs(userID, chunk)...)
+		if err != nil {
+			return fmt.Errorf("failed to query exercise sets: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var exerciseID string
+			var set models.WeightItem
+			var rir sql.NullInt64
+			if err := rows.Scan(&exerciseID, &set.Weight, &set.Unit, &set.OriginalUnit, &set.Reps, &set.Duration,
+				&set.SetType, &set.RPE, &rir, &set.Tempo, &set.RestSeconds, &set.Completed); err != nil {
+				return fmt.Errorf("failed to scan exercise set: %w", err)
+			}
+			if rir.Valid {
+				v := int(rir.Int64)
+				set.RIR = &v
+			}
+			e := byID[exerciseID]
+			e.Sets = append(e.Sets, set)
+		}
+		return rows.Err()
+	})
+}
+
+func (r *ExerciseRepository) insertSets(tx *sql.Tx, userID string, exercise *models.Exercise) error {
+	for i, set := range exercise.Sets {
+		var rir sql.NullInt64
+		if set.RIR != nil {
+			rir = sql.NullInt64{Int64: int64(*set.RIR), Valid: true}
+		}
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO exercise_sets (user_id, exercise_id, position, weight, unit, original_unit, reps, duration_seconds,
+			set_type, rpe, rir, tempo, rest_seconds, completed) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			userID, exercise.ExerciseID, i, set.Weight, set.Unit, set.OriginalUnit, set.Reps, set.Duration,
+			set.SetType, set.RPE, rir, set.Tempo, set.RestSeconds, set.Completed)
+		if err != nil {
+			return fmt.Errorf("failed to insert exercise set: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *ExerciseRepository) deleteSets(tx *sql.Tx, userID, exerciseID string) error {
+	_, err := tx.Exec(r.store.rebind(`DELETE FROM exercise_sets WHERE user_id = ? AND exercise_id = ?`), userID, exerciseID)
+	if err != nil {
+		return fmt.Errorf("failed to delete exercise sets: %w", err)
+	}
+	return nil
+}
+
+// escapeLike escapes LIKE wildcards so a prefix matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,126 @@
+package sqlstore
+
+import (
+	"fmt"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// MeasurementRepository implements repository.MeasurementRepository on the
+// measurements table.
+type MeasurementRepository struct {
+	store *Store
+}
+
+const selectMeasurements = `SELECT user_id, measurement_id, measurement_date, bodyweight, weight_unit, body_fat_percent,
+	chest, waist, arms, thighs, length_unit, created_at FROM measurements`
+
+func (r *MeasurementRepository) GetByID(userID, measurementID string) (*models.Measurement, error) {
+	measurements, err := r.query(selectMeasurements+` WHERE user_id = ? AND measurement_id = ?`, userID, measurementID)
+	if err != nil {
+		return nil, err
+	}
+	if len(measurements) == 0 {
+		return nil, models.ErrMeasurementNotFound
+	}
+	return measurements[0], nil
+}
+
+func (r *MeasurementRepository) ListByDateRange(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Measurement], error) {
+	query := selectMeasurements + ` WHERE user_id = ?`
+	args := []interface{}{userID}
+	if dateRange.From != "" {
+		query += ` AND measurement_date >= ?`
+		args = append(args, dateRange.From)
+	}
+	if dateRange.To != "" {
+		query += ` AND measurement_date <= ?`
+		args = append(args, dateRange.UpperBound())
+	}
+	query, args, err := keyset{"measurement_date", "measurement_id"}.apply(query, args, opts)
+	if err != nil {
+		return nil, err
+	}
+	measurements, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return page(measurements, opts, func(m *models.Measurement) []string {
+		return []string{m.Date, m.MeasurementID}
+	}), nil
+}
+
+func (r *MeasurementRepository) Create(measurement *models.Measurement) error {
+	if measurement.CreatedAt.IsZero() {
+		measurement.CreatedAt = time.Now()
+	}
+	m := measurement
+	_, err := r.store.db.Exec(r.store.rebind(`INSERT INTO measurements
+		(user_id, measurement_id, measurement_date, bodyweight, weight_unit, body_fat_percent, chest, waist, arms, thighs, length_unit, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		m.UserID, m.MeasurementID, m.Date, m.Bodyweight, m.WeightUnit, m.BodyFatPercent,
+		m.Chest, m.Waist, m.Arms, m.Thighs, m.LengthUnit, m.CreatedAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return insertError(err, models.ErrMeasurementAlreadyExists, "measurement")
+	}
+	return nil
+}
+
+func (r *MeasurementRepository) Update(measurement *models.Measurement) error {
+	m := measurement
+	res, err := r.store.db.Exec(r.store.rebind(`UPDATE measurements SET
+		measurement_date = ?, bodyweight = ?, weight_unit = ?, body_fat_percent = ?, chest = ?, waist = ?, arms = ?, thighs = ?, length_unit = ?
+		WHERE user_id = ? AND measurement_id = ?`),
+		m.Date, m.Bodyweight, m.WeightUnit, m.BodyFatPercent, m.Chest, m.Waist, m.Arms, m.Thighs, m.LengthUnit,
+		m.UserID, m.MeasurementID)
+	if err != nil {
+		return fmt.Errorf("failed to update measurement: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrMeasurementNotFound
+	}
+	return nil
+}
+
+func (r *MeasurementRepository) Delete(userID, measurementID string) error {
+	res, err := r.store.db.Exec(r.store.rebind(`DELETE FROM measurements WHERE user_id = ? AND measurement_id = ?`), userID, measurementID)
+	if err != nil {
+		return fmt.Errorf("failed to delete measurement: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrMeasurementNotFound
+	}
+	return nil
+}
+
+func (r *MeasurementRepository) query(query string, args ...interface{}) ([]*models.Measurement, error) {
+	rows, err := r.store.db.Query(r.store.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query measurements: %w", err)
+	}
+	defer rows.Close()
+
+	var measurements []*models.Measurement
+	for rows.Next() {
+		var m models.Measurement
+		var createdAt string
+		if err := rows.Scan(&m.UserID, &m.MeasurementID, &m.Date, &m.Bodyweight, &m.WeightUnit, &m.BodyFatPercent,
+			&m.Chest, &m.Waist, &m.Arms, &m.Thighs, &m.LengthUnit, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan measurement: %w", err)
+		}
+		if m.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse measurement created_at: %w", err)
+		}
+		measurements = append(measurements, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read measurements: %w", err)
+	}
+	return measurements, nil
+}
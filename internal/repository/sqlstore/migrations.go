@@ -0,0 +1,340 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration is one versioned schema change. Statements must be valid in both
+// SQLite and PostgreSQL. Never edit a released migration; append a new one.
+type migration struct {
+	version    int
+	name       string
+	statements []string
+}
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create workouts and exercises",
+		statements: []string{
+			`CREATE TABLE workouts (
+				user_id      TEXT NOT NULL,
+				workout_id   TEXT NOT NULL,
+				name         TEXT NOT NULL,
+				workout_date TEXT NOT NULL,
+				created_at   TEXT NOT NULL,
+				PRIMARY KEY (user_id, workout_id)
+			)`,
+			`CREATE INDEX workouts_user_date ON workouts (user_id, workout_date, workout_id)`,
+			`CREATE TABLE exercises (
+				user_id       TEXT NOT NULL,
+				exercise_id   TEXT NOT NULL,
+				name          TEXT NOT NULL,
+				name_key      TEXT NOT NULL,
+				exercise_type TEXT NOT NULL,
+				time_seconds  INTEGER NOT NULL DEFAULT 0,
+				distance      DOUBLE PRECISION NOT NULL DEFAULT 0,
+				distance_unit TEXT NOT NULL DEFAULT '',
+				level         DOUBLE PRECISION NOT NULL DEFAULT 0,
+				reps          INTEGER NOT NULL DEFAULT 0,
+				rpm           DOUBLE PRECISION NOT NULL DEFAULT 0,
+				PRIMARY KEY (user_id, exercise_id)
+			)`,
+			`CREATE INDEX exercises_user_name ON exercises (user_id, name_key, exercise_id)`,
+			`CREATE INDEX exercises_user_type ON exercises (user_id, exercise_type, name_key, exercise_id)`,
+			`CREATE TABLE exercise_sets (
+				user_id          TEXT NOT NULL,
+				exercise_id      TEXT NOT NULL,
+				position         INTEGER NOT NULL,
+				weight           DOUBLE PRECISION NOT NULL DEFAULT 0,
+				unit             TEXT NOT NULL DEFAULT '',
+				reps             INTEGER NOT NULL DEFAULT 0,
+				duration_seconds INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (user_id, exercise_id, position),
+				FOREIGN KEY (user_id, exercise_id) REFERENCES exercises (user_id, exercise_id) ON DELETE CASCADE
+			)`,
+			// Links are not foreign keys to exercises: a workout may reference an
+			// exercise that has since been deleted.
+			`CREATE TABLE workout_exercises (
+				user_id     TEXT NOT NULL,
+				workout_id  TEXT NOT NULL,
+				position    INTEGER NOT NULL,
+				exercise_id TEXT NOT NULL,
+				PRIMARY KEY (user_id, workout_id, position),
+				FOREIGN KEY (user_id, workout_id) REFERENCES workouts (user_id, workout_id) ON DELETE CASCADE
+			)`,
+		},
+	},
+	{
+		version: 2,
+		name:    "create templates",
+		statements: []string{
+			`CREATE TABLE templates (
+				user_id     TEXT NOT NULL,
+				template_id TEXT NOT NULL,
+				name        TEXT NOT NULL,
+				created_at  TEXT NOT NULL,
+				PRIMARY KEY (user_id, template_id)
+			)`,
+			`CREATE TABLE template_exercises (
+				user_id       TEXT NOT NULL,
+				template_id   TEXT NOT NULL,
+				position      INTEGER NOT NULL,
+				name          TEXT NOT NULL,
+				exercise_type TEXT NOT NULL,
+				target_sets   INTEGER NOT NULL DEFAULT 0,
+				target_reps   INTEGER NOT NULL DEFAULT 0,
+				target_weight DOUBLE PRECISION NOT NULL DEFAULT 0,
+				unit          TEXT NOT NULL DEFAULT '',
+				PRIMARY KEY (user_id, template_id, position),
+				FOREIGN KEY (user_id, template_id) REFERENCES templates (user_id, template_id) ON DELETE CASCADE
+			)`,
+		},
+	},
+	{
+		version: 3,
+		name:    "create programs",
+		statements: []string{
+			// start_date is empty while the user is not enrolled.
+			`CREATE TABLE programs (
+				user_id          TEXT NOT NULL,
+				program_id       TEXT NOT NULL,
+				name             TEXT NOT NULL,
+				weekly_increment DOUBLE PRECISION NOT NULL DEFAULT 0,
+				deload_percent   DOUBLE PRECISION NOT NULL DEFAULT 0,
+				start_date       TEXT NOT NULL DEFAULT '',
+				enrolled_at      TEXT NOT NULL DEFAULT '',
+				created_at       TEXT NOT NULL,
+				PRIMARY KEY (user_id, program_id)
+			)`,
+			`CREATE TABLE program_weeks (
+				user_id    TEXT NOT NULL,
+				program_id TEXT NOT NULL,
+				week       INTEGER NOT NULL,
+				deload     INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (user_id, program_id, week),
+				FOREIGN KEY (user_id, program_id) REFERENCES programs (user_id, program_id) ON DELETE CASCADE
+			)`,
+			`CREATE TABLE program_days (
+				user_id     TEXT NOT NULL,
+				program_id  TEXT NOT NULL,
+				week        INTEGER NOT NULL,
+				position    INTEGER NOT NULL,
+				day         INTEGER NOT NULL,
+				template_id TEXT NOT NULL,
+				PRIMARY KEY (user_id, program_id, week, position),
+				FOREIGN KEY (user_id, program_id) REFERENCES programs (user_id, program_id) ON DELETE CASCADE
+			)`,
+			`CREATE TABLE program_sessions (
+				user_id      TEXT NOT NULL,
+				program_id   TEXT NOT NULL,
+				position     INTEGER NOT NULL,
+				week         INTEGER NOT NULL,
+				day          INTEGER NOT NULL,
+				session_date TEXT NOT NULL,
+				workout_id   TEXT NOT NULL,
+				PRIMARY KEY (user_id, program_id, position),
+				FOREIGN KEY (user_id, program_id) REFERENCES programs (user_id, program_id) ON DELETE CASCADE
+			)`,
+		},
+	},
+	{
+		version: 4,
+		name:    "create records",
+		statements: []string{
+			`CREATE TABLE records (
+				user_id       TEXT NOT NULL,
+				record_id     TEXT NOT NULL,
+				exercise_name TEXT NOT NULL,
+				name_key      TEXT NOT NULL,
+				exercise_id   TEXT NOT NULL,
+				kind          TEXT NOT NULL,
+				qualifier     TEXT NOT NULL DEFAULT '',
+				value         DOUBLE PRECISION NOT NULL,
+				unit          TEXT NOT NULL DEFAULT '',
+				previous      DOUBLE PRECISION NOT NULL DEFAULT 0,
+				achieved_at   TEXT NOT NULL,
+				PRIMARY KEY (user_id, record_id)
+			)`,
+			`CREATE INDEX records_user_name ON records (user_id, name_key)`,
+			`CREATE INDEX records_user_exercise ON records (user_id, exercise_id)`,
+		},
+	},
+	{
+		version: 5,
+		name:    "add unit preferences",
+		statements: []string{
+			`ALTER TABLE exercises ADD COLUMN original_distance_unit TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE exercise_sets ADD COLUMN original_unit TEXT NOT NULL DEFAULT ''`,
+			`CREATE TABLE profiles (
+				user_id       TEXT NOT NULL,
+				weight_unit   TEXT NOT NULL,
+				distance_unit TEXT NOT NULL,
+				created_at    TEXT NOT NULL,
+				updated_at    TEXT NOT NULL,
+				PRIMARY KEY (user_id)
+			)`,
+		},
+	},
+	{
+		version: 6,
+		name:    "add profile settings",
+		statements: []string{
+			`ALTER TABLE profiles ADD COLUMN display_name TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE profiles ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC'`,
+			`ALTER TABLE profiles ADD COLUMN week_start TEXT NOT NULL DEFAULT 'monday'`,
+			`ALTER TABLE profiles ADD COLUMN default_rest_seconds INTEGER NOT NULL DEFAULT 90`,
+			`CREATE TABLE profile_bodyweight (
+				user_id    TEXT NOT NULL,
+				entry_date TEXT NOT NULL,
+				weight     DOUBLE PRECISION NOT NULL,
+				unit       TEXT NOT NULL,
+				PRIMARY KEY (user_id, entry_date),
+				FOREIGN KEY (user_id) REFERENCES profiles (user_id) ON DELETE CASCADE
+			)`,
+		},
+	},
+	{
+		version: 7,
+		name:    "create measurements",
+		statements: []string{
+			`CREATE TABLE measurements (
+				user_id          TEXT NOT NULL,
+				measurement_id   TEXT NOT NULL,
+				measurement_date TEXT NOT NULL,
+				bodyweight       DOUBLE PRECISION NOT NULL DEFAULT 0,
+				weight_unit      TEXT NOT NULL DEFAULT '',
+				body_fat_percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+				chest            DOUBLE PRECISION NOT NULL DEFAULT 0,
+				waist            DOUBLE PRECISION NOT NULL DEFAULT 0,
+				arms             DOUBLE PRECISION NOT NULL DEFAULT 0,
+				thighs           DOUBLE PRECISION NOT NULL DEFAULT 0,
+				length_unit      TEXT NOT NULL DEFAULT '',
+				created_at       TEXT NOT NULL,
+				PRIMARY KEY (user_id, measurement_id)
+			)`,
+			`CREATE INDEX measurements_user_date ON measurements (user_id, measurement_date, measurement_id)`,
+		},
+	},
+	{
+		version: 8,
+		name:    "add set detail",
+		statements: []string{
+			`ALTER TABLE exercise_sets ADD COLUMN set_type TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE exercise_sets ADD COLUMN rpe DOUBLE PRECISION NOT NULL DEFAULT 0`,
+			`ALTER TABLE exercise_sets ADD COLUMN rir INTEGER`,
+			`ALTER TABLE exercise_sets ADD COLUMN tempo TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE exercise_sets ADD COLUMN rest_seconds INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE exercise_sets ADD COLUMN completed BOOLEAN NOT NULL DEFAULT FALSE`,
+		},
+	},
+	{
+		version: 9,
+		name:    "create workout groups",
+		statements: []string{
+			`CREATE TABLE workout_groups (
+				user_id      TEXT NOT NULL,
+				workout_id   TEXT NOT NULL,
+				position     INTEGER NOT NULL,
+				group_id     TEXT NOT NULL,
+				kind         TEXT NOT NULL,
+				rounds       INTEGER NOT NULL DEFAULT 0,
+				rest_seconds INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (user_id, workout_id, position),
+				FOREIGN KEY (user_id, workout_id) REFERENCES workouts (user_id, workout_id) ON DELETE CASCADE
+			)`,
+			// Links written before groups existed keep an empty group_id.
+			`ALTER TABLE workout_exercises ADD COLUMN group_id TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		version: 10,
+		name:    "add workout sessions",
+		// Session timestamps are RFC 3339 text, empty when not recorded.
+		statements: []string{
+			`ALTER TABLE workouts ADD COLUMN status TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE workouts ADD COLUMN started_at TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE workouts ADD COLUMN paused_at TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE workouts ADD COLUMN finished_at TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE workouts ADD COLUMN paused_seconds INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE workouts ADD COLUMN duration_seconds INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		version: 11,
+		name:    "add trash",
+		// deleted_at is RFC 3339 text, empty unless the row is in the trash.
+		// Trashed workouts keep their links and exercises their sets so that
+		// they can be restored.
+		statements: []string{
+			`ALTER TABLE workouts ADD COLUMN deleted_at TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE exercises ADD COLUMN deleted_at TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		version: 12,
+		name:    "add program versions",
+		statements: []string{
+			`ALTER TABLE programs ADD COLUMN version INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		version: 13,
+		name:    "add trash expiry",
+		// expires_at is in epoch seconds, zero for rows outside the trash
+		// or trashed without a retention.
+		statements: []string{
+			`ALTER TABLE workouts ADD COLUMN expires_at BIGINT NOT NULL DEFAULT 0`,
+			`ALTER TABLE exercises ADD COLUMN expires_at BIGINT NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		version: 14,
+		name:    "add exercise created_at",
+		// created_at is RFC 3339 text; exercises stored before it was added
+		// have none.
+		statements: []string{
+			`ALTER TABLE exercises ADD COLUMN created_at TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+}
+
+// migrate applies every migration newer than the recorded schema version, each
+// in its own transaction.
+func (s *Store) migrate() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		err := s.inTx(func(tx *sql.Tx) error {
+			for _, stmt := range m.statements {
+				if _, err := tx.Exec(stmt); err != nil {
+					return err
+				}
+			}
+			_, err := tx.Exec(s.rebind(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`),
+				m.version, m.name, time.Now().UTC().Format(time.RFC3339))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package sqlstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// keyset describes the ordered columns a listing pages over. The last column
+// must make the order unique (normally the item ID).
+type keyset []string
+
+// apply appends the cursor predicate, ORDER BY and LIMIT for opts to a query
+// whose WHERE clause is already open. One extra row is requested so the caller
+// can tell whether another page follows.
+func (k keyset) apply(query string, args []interface{}, opts repository.ListOptions) (string, []interface{}, error) {
+	op, dir := ">", "ASC"
+	if opts.Descending {
+		op, dir = "<", "DESC"
+	}
+
+	if opts.Cursor != "" {
+		after, err := decodeCursor(opts.Cursor, len(k))
+		if err != nil {
+			return "", nil, err
+		}
+		// (a > ?) OR (a = ? AND b > ?) ...
+		var terms []string
+		for i := range k {
+			var parts []string
+			for j := 0; j < i; j++ {
+				parts = append(parts, k[j]+" = ?")
+				args = append(args, after[j])
+			}
+			parts = append(parts, k[i]+" "+op+" ?")
+			args = append(args, after[i])
+			terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+		}
+		query += " AND (" + strings.Join(terms, " OR ") + ")"
+	}
+
+	order := make([]string, len(k))
+	for i, column := range k {
+		order[i] = column + " " + dir
+	}
+	query += " ORDER BY " + strings.Join(order, ", ")
+	if opts.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(opts.Limit+1)
+	}
+	return query, args, nil
+}
+
+// page trims the extra row fetched by apply and sets the next cursor from the
+// last item kept, using key to read that item's keyset values.
+func page[T any](items []T, opts repository.ListOptions, key func(T) []string) *repository.Page[T] {
+	p := &repository.Page[T]{Items: items}
+	if opts.Limit > 0 && len(items) > opts.Limit {
+		p.Items = items[:opts.Limit]
+		p.NextCursor = encodeCursor(key(p.Items[opts.Limit-1]))
+	}
+	return p
+}
+
+func encodeCursor(values []string) string {
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string, n int) ([]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil || len(values) != n {
+		return nil, models.ErrInvalidCursor
+	}
+	return values, nil
+}
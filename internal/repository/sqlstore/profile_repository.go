@@ -0,0 +1,139 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gym-tracker-api/internal/models"
+)
+
+// ProfileRepository implements repository.ProfileRepository on the profiles
+// and profile_bodyweight tables.
+type ProfileRepository struct {
+	store *Store
+}
+
+func (r *ProfileRepository) GetByUserID(userID string) (*models.UserProfile, error) {
+	var p models.UserProfile
+	var createdAt, updatedAt string
+	err := r.store.db.QueryRow(r.store.rebind(`SELECT user_id, display_name, weight_unit, distance_unit, timezone, week_start,
+		default_rest_seconds, created_at, updated_at FROM profiles WHERE user_id = ?`), userID).
+		Scan(&p.UserID, &p.DisplayName, &p.Units.Weight, &p.Units.Distance, &p.Timezone, &p.WeekStart,
+			&p.DefaultRestSeconds, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	if p.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse profile created_at: %w", err)
+	}
+	if p.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse profile updated_at: %w", err)
+	}
+	if p.Bodyweight, err = r.loadBodyweight(userID); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ProfileRepository) Create(profile *models.UserProfile) error {
+	if profile.CreatedAt.IsZero() {
+		profile.CreatedAt = time.Now()
+	}
+	if profile.UpdatedAt.IsZero() {
+		profile.UpdatedAt = profile.CreatedAt
+	}
+	return r.store.inTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO profiles
+			(user_id, display_name, weight_unit, distance_unit, timezone, week_start, default_rest_seconds, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			profile.UserID, profile.DisplayName, profile.Units.Weight, profile.Units.Distance, profile.Timezone, profile.WeekStart,
+			profile.DefaultRestSeconds, profile.CreatedAt.UTC().Format(time.RFC3339Nano), profile.UpdatedAt.UTC().Format(time.RFC3339Nano))
+		if err != nil {
+			return insertError(err, models.ErrProfileAlreadyExists, "profile")
+		}
+		return r.insertBodyweight(tx, profile)
+	})
+}
+
+func (r *ProfileRepository) Update(profile *models.UserProfile) error {
+	return r.store.inTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(r.store.rebind(`UPDATE profiles SET
+			display_name = ?, weight_unit = ?, distance_unit = ?, timezone = ?, week_start = ?, default_rest_seconds = ?, updated_at = ?
+			WHERE user_id = ?`),
+			profile.DisplayName, profile.Units.Weight, profile.Units.Distance, profile.Timezone, profile.WeekStart,
+			profile.DefaultRestSeconds, profile.UpdatedAt.UTC().Format(time.RFC3339Nano), profile.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to update profile: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return models.ErrProfileNotFound
+		}
+		if err := r.deleteBodyweight(tx, profile.UserID); err != nil {
+			return err
+		}
+		return r.insertBodyweight(tx, profile)
+	})
+}
+
+// Delete removes the profile; its bodyweight log goes with it through ON
+// DELETE CASCADE.
+func (r *ProfileRepository) Delete(userID string) error {
+	res, err := r.store.db.Exec(r.store.rebind(`DELETE FROM profiles WHERE user_id = ?`), userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrProfileNotFound
+	}
+	return nil
+}
+
+func (r *ProfileRepository) loadBodyweight(userID string) ([]models.BodyweightEntry, error) {
+	rows, err := r.store.db.Query(r.store.rebind(`SELECT entry_date, weight, unit FROM profile_bodyweight
+		WHERE user_id = ? ORDER BY entry_date`), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bodyweight: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.BodyweightEntry{}
+	for rows.Next() {
+		var e models.BodyweightEntry
+		if err := rows.Scan(&e.Date, &e.Weight, &e.Unit); err != nil {
+			return nil, fmt.Errorf("failed to scan bodyweight: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bodyweight: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *ProfileRepository) insertBodyweight(tx *sql.Tx, profile *models.UserProfile) error {
+	for _, e := range profile.Bodyweight {
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO profile_bodyweight (user_id, entry_date, weight, unit) VALUES (?, ?, ?, ?)`),
+			profile.UserID, e.Date, e.Weight, e.Unit)
+		if err != nil {
+			return fmt.Errorf("failed to insert bodyweight: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *ProfileRepository) deleteBodyweight(tx *sql.Tx, userID string) error {
+	_, err := tx.Exec(r.store.rebind(`DELETE FROM profile_bodyweight WHERE user_id = ?`), userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bodyweight: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// ProgramRepository implements repository.ProgramRepository on the programs
+// table and its program_weeks, program_days and program_sessions children.
+type ProgramRepository struct {
+	store *Store
+}
+
+const selectPrograms = `SELECT user_id, program_id, name, weekly_increment, deload_percent, start_date, enrolled_at, created_at, version FROM programs`
+
+// programChildren are the child tables, cleared before Update rewrites them.
+var programChildren = []string{"program_sessions", "program_days", "program_weeks"}
+
+func (r *ProgramRepository) GetByID(userID, programID string) (*models.Program, error) {
+	programs, err := r.query(selectPrograms+` WHERE user_id = ? AND program_id = ?`, userID, programID)
+	if err != nil {
+		return nil, err
+	}
+	if len(programs) == 0 {
+		return nil, models.ErrProgramNotFound
+	}
+	return programs[0], nil
+}
+
+func (r *ProgramRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Program], error) {
+	query, args, err := keyset{"program_id"}.apply(selectPrograms+` WHERE user_id = ?`, []interface{}{userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	programs, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return page(programs, opts, func(p *models.Program) []string {
+		return []string{p.ProgramID}
+	}), nil
+}
+
+func (r *ProgramRepository) Create(program *models.Program) error {
+	if program.CreatedAt.IsZero() {
+		program.CreatedAt = time.Now()
+	}
+	return r.store.inTx(func(tx *sql.Tx) error {
+		startDate, enrolledAt := enrollmentColumns(program.Enrollment)
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO programs (user_id, program_id, name, weekly_increment, deload_percent, start_date, enrolled_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+			program.UserID, program.ProgramID, program.Name, program.Progression.WeeklyIncrement, program.Progression.DeloadPercent,
+			startDate, enrolledAt, program.CreatedAt.UTC().Format(time.RFC3339Nano))
+		if err != nil {
+			return insertError(err, models.ErrProgramAlreadyExists, "program")
+		}
+		return r.insertChildren(tx, program)
+	})
+}
+
+func (r *ProgramRepository) Update(program *models.Program) error {
+	err := r.store.inTx(func(tx *sql.Tx) error {
+		startDate, enrolledAt := enrollmentColumns(program.Enrollment)
+		res, err := tx.Exec(r.store.rebind(`UPDATE programs SET name = ?, weekly_increment = ?, deload_percent = ?, start_date = ?, enrolled_at = ?,
+			version = version + 1 WHERE user_id = ? AND program_id = ? AND version = ?`),
+			program.Name, program.Progression.WeeklyIncrement, program.Progression.DeloadPercent, startDate, enrolledAt,
+			program.UserID, program.ProgramID, program.Version)
+		if err != nil {
+			return fmt.Errorf("failed to update program: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return r.missingOrConflict(tx, program.UserID, program.ProgramID)
+		}
+		if err := r.deleteChildren(tx, program.UserID, program.ProgramID); err != nil {
+			return err
+		}
+		return r.insertChildren(tx, program)
+	})
+	if err != nil {
+		return err
+	}
+	program.Version++
+	return nil
+}
+
+// missingOrConflict tells apart the two reasons a versioned UPDATE matched no
+// row.
+func (r *ProgramRepository) missingOrConflict(q querier, userID, programID string) error {
+	var n int
+	err := q.QueryRow(r.store.rebind(`SELECT COUNT(*) FROM programs WHERE user_id = ? AND program_id = ?`), userID, programID).Scan(&n)
+	if err != nil {
+		return fmt.Errorf("failed to check program: %w", err)
+	}
+	if n == 0 {
+		return models.ErrProgramNotFound
+	}
+	return models.ErrProgramConflict
+}
+
+// Delete removes the program; its weeks, days and sessions go with it through
+// ON DELETE CASCADE.
+func (r *ProgramRepository) Delete(userID, programID string) error {
+	res, err := r.store.db.Exec(r.store.rebind(`DELETE FROM programs WHERE user_id = ? AND program_id = ?`), userID, programID)
+	if err != nil {
+		return fmt.Errorf("failed to delete program: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrProgramNotFound
+	}
+	return nil
+}
+
+// enrollmentColumns flattens an enrollment into the start_date and enrolled_at
+// columns; both are empty when the user is not enrolled.
+func enrollmentColumns(e *models.Enrollment) (string, string) {
+	if e == nil {
+		return "", ""
+	}
+	return e.StartDate, e.EnrolledAt.UTC().Format(time.RFC3339Nano)
+}
+
+// query runs a program SELECT, which must filter on a single user, and attaches each program's weeks and sessions.
+func (r *ProgramRepository) query(query string, args ...interface{}) ([]*models.Program, error) {
+	rows, err := r.store.db.Query(r.store.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query programs: %w", err)
+	}
+	defer rows.Close()
+
+	var programs []*models.Program
+	for rows.Next() {
+		var p models.Program
+		var startDate, enrolledAt, createdAt string
+		if err := rows.Scan(&p.UserID, &p.ProgramID, &p.Name, &p.Progression.WeeklyIncrement, &p.Progression.DeloadPercent,
+			&startDate, &enrolledAt, &createdAt, &p.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan program: %w", err)
+		}
+		if p.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse program created_at: %w", err)
+		}
+		if startDate != "" {
+			p.Enrollment = &models.Enrollment{StartDate: startDate, Sessions: []models.ProgramSession{}}
+			if p.Enrollment.EnrolledAt, err = time.Parse(time.RFC3339Nano, enrolledAt); err != nil {
+				return nil, fmt.Errorf("failed to parse program enrolled_at: %w", err)
+			}
+		}
+		p.Weeks = []models.ProgramWeek{}
+		programs = append(programs, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read programs: %w", err)
+	}
+	if len(programs) == 0 {
+		return programs, nil
+	}
+	return programs, r.loadChildren(programs[0].UserID, programs)
+}
+
+func (r *ProgramRepository) loadChildren(userID string, programs []*models.Program) error {
+	byID := make(map[string]*models.Program, len(programs))
+	ids := make([]string, len(programs))
+	for i, p := range programs {
+		byID[p.ProgramID] = p
+		ids[i] = p.ProgramID
+	}
+
+	return forEachChunk(ids, func(chunk []string) error {
+		where := ` WHERE user_id = ? AND program_id IN (` + placeholders(len(chunk)) + `)`
+		args := inArgs(userID, chunk)
+
+		err := r.scanEach(`SELECT program_id, deload FROM program_weeks`+where+` ORDER BY program_id, week`, args, func(rows *sql.Rows) error {
+			var programID string
+			var deload int
+			if err := rows.Scan(&programID, &deload); err != nil {
+				return err
+			}
+			p := byID[programID]
+			p.Weeks = append(p.Weeks, models.ProgramWeek{Days: []models.ProgramDay{}, Deload: deload != 0})
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load program weeks: %w", err)
+		}
+
+		err = r.scanEach(`SELECT program_id, week, day, template_id FROM program_days`+where+` ORDER BY program_id, week, position`, args, func(rows *sql.Rows) error {
+			var programID string
+			var week int
+			var day models.ProgramDay
+			if err := rows.Scan(&programID, &week, &day.Day, &day.TemplateID); err != nil {
+				return err
+			}
+			p := byID[programID]
+			if week < 1 || week > len(p.Weeks) {
+				return fmt.Errorf("program %s has a day in missing week %d", programID, week)
+			}
+			p.Weeks[week-1].Days = append(p.Weeks[week-1].Days, day)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load program days: %w", err)
+		}
+
+		err = r.scanEach(`SELECT program_id, week, day, session_date, workout_id FROM program_sessions`+where+` ORDER BY program_id, position`, args, func(rows *sql.Rows) error {
+			var programID string
+			var s models.ProgramSession
+			if err := rows.Scan(&programID, &s.Week, &s.Day, &s.Date, &s.WorkoutID); err != nil {
+				return err
+			}
+			if p := byID[programID]; p.Enrollment != nil {
+				p.Enrollment.Sessions = append(p.Enrollment.Sessions, s)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load program sessions: %w", err)
+		}
+		return nil
+	})
+}
+
+// scanEach runs query and calls fn for every row.
+func (r *ProgramRepository) scanEach(query string, args []interface{}, fn func(rows *sql.Rows) error) error {
+	rows, err := r.store.db.Query(r.store.rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *ProgramRepository) insertChildren(tx *sql.Tx, program *models.Program) error {
+	for i, week := range program.Weeks {
+		deload := 0
+		if week.Deload {
+			deload = 1
+		}
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO program_weeks (user_id, program_id, week, deload) VALUES (?, ?, ?, ?)`),
+			program.UserID, program.ProgramID, i+1, deload)
+		if err != nil {
+			return fmt.Errorf("failed to insert program week: %w", err)
+		}
+		for j, day := range week.Days {
+			_, err := tx.Exec(r.store.rebind(`INSERT INTO program_days (user_id, program_id, week, position, day, template_id) VALUES (?, ?, ?, ?, ?, ?)`),
+				program.UserID, program.ProgramID, i+1, j, day.Day, day.TemplateID)
+			if err != nil {
+				return fmt.Errorf("failed to insert program day: %w", err)
+			}
+		}
+	}
+	if program.Enrollment == nil {
+		return nil
+	}
+	for i, s := range program.Enrollment.Sessions {
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO program_sessions (user_id, program_id, position, week, day, session_date, workout_id) VALUES (?, ?, ?, ?, ?, ?, ?)`),
+			program.UserID, program.ProgramID, i, s.Week, s.Day, s.Date, s.WorkoutID)
+		if err != nil {
+			return fmt.Errorf("failed to insert program session: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *ProgramRepository) deleteChildren(tx *sql.Tx, userID, programID string) error {
+	for _, table := range programChildren {
+		_, err := tx.Exec(r.store.rebind(`DELETE FROM `+table+` WHERE user_id = ? AND program_id = ?`), userID, programID)
+		if err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+	return nil
+}
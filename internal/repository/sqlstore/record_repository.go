@@ -0,0 +1,86 @@
+package sqlstore
+
+import (
+	"fmt"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// RecordRepository implements repository.RecordRepository on the records table.
+type RecordRepository struct {
+	store *Store
+}
+
+const selectRecords = `SELECT user_id, record_id, exercise_name, exercise_id, kind, qualifier, value, unit, previous, achieved_at FROM records`
+
+func (r *RecordRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.PersonalRecord], error) {
+	query, args, err := keyset{"record_id"}.apply(selectRecords+` WHERE user_id = ?`, []interface{}{userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	records, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return page(records, opts, func(rec *models.PersonalRecord) []string {
+		return []string{rec.RecordID}
+	}), nil
+}
+
+// ListByName sorts in Go: achieved_at is RFC 3339 text with variable-length
+// fractions, which does not order correctly as a string.
+func (r *RecordRepository) ListByName(userID, exerciseName string) ([]*models.PersonalRecord, error) {
+	records, err := r.query(selectRecords+` WHERE user_id = ? AND name_key = ?`, userID, models.NormalizeName(exerciseName))
+	if err != nil {
+		return nil, err
+	}
+	models.SortRecords(records)
+	return records, nil
+}
+
+func (r *RecordRepository) Create(record *models.PersonalRecord) error {
+	_, err := r.store.db.Exec(r.store.rebind(`INSERT INTO records (user_id, record_id, exercise_name, name_key, exercise_id, kind, qualifier, value, unit, previous, achieved_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		record.UserID, record.RecordID, record.ExerciseName, models.NormalizeName(record.ExerciseName), record.ExerciseID,
+		record.Kind, record.Qualifier, record.Value, record.Unit, record.Previous, record.AchievedAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return insertError(err, models.ErrRecordAlreadyExists, "record")
+	}
+	return nil
+}
+
+func (r *RecordRepository) DeleteByExercise(userID, exerciseID string) error {
+	_, err := r.store.db.Exec(r.store.rebind(`DELETE FROM records WHERE user_id = ? AND exercise_id = ?`), userID, exerciseID)
+	if err != nil {
+		return fmt.Errorf("failed to delete records: %w", err)
+	}
+	return nil
+}
+
+func (r *RecordRepository) query(query string, args ...interface{}) ([]*models.PersonalRecord, error) {
+	rows, err := r.store.db.Query(r.store.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*models.PersonalRecord
+	for rows.Next() {
+		var rec models.PersonalRecord
+		var achievedAt string
+		if err := rows.Scan(&rec.UserID, &rec.RecordID, &rec.ExerciseName, &rec.ExerciseID, &rec.Kind, &rec.Qualifier,
+			&rec.Value, &rec.Unit, &rec.Previous, &achievedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan record: %w", err)
+		}
+		if rec.AchievedAt, err = time.Parse(time.RFC3339Nano, achievedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse record achieved_at: %w", err)
+		}
+		records = append(records, &rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read records: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,217 @@
+// Package sqlstore implements the repository interfaces on database/sql. It
+// supports SQLite (driver "sqlite") for local use and PostgreSQL (driver
+// "postgres") for production, using normalized tables and versioned
+// migrations that are applied when the store is opened.
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gym-tracker-api/internal/repository"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// Store is an open SQL database with the schema migrated to the latest version.
+type Store struct {
+	db     *sql.DB
+	driver string
+}
+
+// Open connects to the database and applies any pending migrations.
+func Open(driver, dsn string) (*Store, error) {
+	if driver != DriverSQLite && driver != DriverPostgres {
+		return nil, fmt.Errorf("unsupported SQL driver %q", driver)
+	}
+
+	if driver == DriverSQLite {
+		dsn = sqliteDSN(dsn)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database: %w", driver, err)
+	}
+	if driver == DriverSQLite {
+		// SQLite allows a single writer; serialise access through one connection.
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to %s database: %w", driver, err)
+	}
+
+	s := &Store{db: db, driver: driver}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// sqliteDSN turns on foreign keys, which SQLite leaves off by default, so the
+// schema's ON DELETE CASCADE clauses take effect. The driver applies the pragma
+// to every connection it opens.
+func sqliteDSN(dsn string) string {
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "_pragma=foreign_keys(1)"
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Workouts returns a WorkoutRepository backed by this store.
+func (s *Store) Workouts() *WorkoutRepository {
+	return &WorkoutRepository{store: s}
+}
+
+// Exercises returns an ExerciseRepository backed by this store.
+func (s *Store) Exercises() *ExerciseRepository {
+	return &ExerciseRepository{store: s}
+}
+
+// Templates returns a TemplateRepository backed by this store.
+func (s *Store) Templates() *TemplateRepository {
+	return &TemplateRepository{store: s}
+}
+
+// Programs returns a ProgramRepository backed by this store.
+func (s *Store) Programs() *ProgramRepository {
+	return &ProgramRepository{store: s}
+}
+
+// Records returns a RecordRepository backed by this store.
+func (s *Store) Records() *RecordRepository {
+	return &RecordRepository{store: s}
+}
+
+// Profiles returns a ProfileRepository backed by this store.
+func (s *Store) Profiles() *ProfileRepository {
+	return &ProfileRepository{store: s}
+}
+
+// Measurements returns a MeasurementRepository backed by this store.
+func (s *Store) Measurements() *MeasurementRepository {
+	return &MeasurementRepository{store: s}
+}
+
+// rebind rewrites ? placeholders into the driver's native form ($1, $2, ... for Postgres).
+func (s *Store) rebind(query string) string {
+	if s.driver != DriverPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// inTx runs fn in a transaction, committing on success and rolling back on error.
+func (s *Store) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertError maps an INSERT that hit an existing primary key or UNIQUE
+// index to exists, so a duplicate is reported the same however it raced.
+// Other failures are wrapped as "failed to insert <what>".
+func insertError(err error, exists error, what string) error {
+	if isUniqueViolation(err) {
+		return exists
+	}
+	return fmt.Errorf("failed to insert %s: %w", what, err)
+}
+
+// isUniqueViolation reports whether err is a primary key or UNIQUE constraint
+// failure from either driver.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		return code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || code == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	}
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation"
+}
+
+// maxInArgs bounds the IDs sent in a single IN (...) list.
+const maxInArgs = 500
+
+// forEachChunk calls fn with successive slices of ids of at most maxInArgs.
+func forEachChunk(ids []string, fn func(chunk []string) error) error {
+	for start := 0; start < len(ids); start += maxInArgs {
+		end := start + maxInArgs
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := fn(ids[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inArgs builds the arguments for "user_id = ? AND id IN (...)".
+func inArgs(userID string, ids []string) []interface{} {
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, userID)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return args
+}
+
+// placeholders returns "?, ?, ?" for n arguments.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+var (
+	_ repository.WorkoutRepository     = (*WorkoutRepository)(nil)
+	_ repository.ExerciseRepository    = (*ExerciseRepository)(nil)
+	_ repository.TemplateRepository    = (*TemplateRepository)(nil)
+	_ repository.ProgramRepository     = (*ProgramRepository)(nil)
+	_ repository.RecordRepository      = (*RecordRepository)(nil)
+	_ repository.ProfileRepository     = (*ProfileRepository)(nil)
+	_ repository.MeasurementRepository = (*MeasurementRepository)(nil)
+	_ repository.TrashSweeper          = (*WorkoutRepository)(nil)
+	_ repository.TrashSweeper          = (*ExerciseRepository)(nil)
+)
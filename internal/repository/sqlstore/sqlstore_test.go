@@ -0,0 +1,181 @@
+package sqlstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/repository/repotest"
+)
+
+// openTestStore opens a fresh SQLite database, or the Postgres database named
+// by SQLSTORE_POSTGRES_DSN when set, emptying its tables first.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	driver, dsn := DriverSQLite, filepath.Join(t.TempDir(), "test.db")
+	if pg := os.Getenv("SQLSTORE_POSTGRES_DSN"); pg != "" {
+		driver, dsn = DriverPostgres, pg
+	}
+	s, err := Open(driver, dsn)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	// Child rows go with their parents through ON DELETE CASCADE, so the
+	// tables can be emptied in any order.
+	for _, table := range migratedTables(t, s) {
+		if _, err := s.db.Exec("DELETE FROM " + table); err != nil {
+			t.Fatalf("empty %s: %v", table, err)
+		}
+	}
+	return s
+}
+
+// migratedTables lists every table the migrations created, read from the
+// catalog so that tables added by later migrations are included.
+func migratedTables(t *testing.T, s *Store) []string {
+	t.Helper()
+	query := `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`
+	if s.driver == DriverPostgres {
+		query = `SELECT tablename FROM pg_tables WHERE schemaname = current_schema()`
+	}
+	rows, err := s.db.Query(query)
+	if err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			t.Fatalf("list tables: %v", err)
+		}
+		if table != "schema_migrations" {
+			tables = append(tables, table)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	return tables
+}
+
+func newExercise(id, name, exerciseType string) *models.Exercise {
+	return &models.Exercise{
+		ExerciseID:   id,
+		Name:         name,
+		ExerciseType: exerciseType,
+	}
+}
+
+func TestOpen_MigrationsAreIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	for i := 0; i < 2; i++ {
+		s, err := Open(DriverSQLite, path)
+		if err != nil {
+			t.Fatalf("Open #%d: %v", i+1, err)
+		}
+		var version int
+		if err := s.db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+			t.Fatalf("read version: %v", err)
+		}
+		if version != migrations[len(migrations)-1].version {
+			t.Errorf("schema version = %d, want %d", version, migrations[len(migrations)-1].version)
+		}
+		s.Close()
+	}
+}
+
+func TestOpen_EnablesForeignKeys(t *testing.T) {
+	s, err := Open(DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+	var enabled int
+	if err := s.db.QueryRow(`PRAGMA foreign_keys`).Scan(&enabled); err != nil {
+		t.Fatalf("read pragma: %v", err)
+	}
+	if enabled != 1 {
+		t.Errorf("foreign_keys = %d, want 1", enabled)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	s := &Store{driver: DriverPostgres}
+	got := s.rebind(`SELECT * FROM t WHERE a = ? AND b IN (?, ?)`)
+	if want := `SELECT * FROM t WHERE a = $1 AND b IN ($2, $3)`; got != want {
+		t.Errorf("rebind = %q, want %q", got, want)
+	}
+}
+
+func TestWorkoutRepository_Conformance(t *testing.T) {
+	repotest.RunWorkoutRepositoryTests(t, func(t *testing.T) repository.WorkoutRepository {
+		return openTestStore(t).Workouts()
+	})
+}
+
+func TestExerciseRepository_Conformance(t *testing.T) {
+	repotest.RunExerciseRepositoryTests(t, func(t *testing.T) repository.ExerciseRepository {
+		return openTestStore(t).Exercises()
+	})
+}
+
+func TestTrashExpiry_Conformance(t *testing.T) {
+	repotest.RunTrashExpiryTests(t,
+		func(t *testing.T, retention time.Duration) repository.WorkoutRepository {
+			return openTestStore(t).Workouts().WithRetention(retention)
+		},
+		func(t *testing.T, retention time.Duration) repository.ExerciseRepository {
+			return openTestStore(t).Exercises().WithRetention(retention)
+		})
+}
+
+func TestTemplateRepository_Conformance(t *testing.T) {
+	repotest.RunTemplateRepositoryTests(t, func(t *testing.T) repository.TemplateRepository {
+		return openTestStore(t).Templates()
+	})
+}
+
+func TestProgramRepository_Conformance(t *testing.T) {
+	repotest.RunProgramRepositoryTests(t, func(t *testing.T) repository.ProgramRepository {
+		return openTestStore(t).Programs()
+	})
+}
+
+func TestRecordRepository_Conformance(t *testing.T) {
+	repotest.RunRecordRepositoryTests(t, func(t *testing.T) repository.RecordRepository {
+		return openTestStore(t).Records()
+	})
+}
+
+func TestExerciseRepository_PrefixEscapesWildcards(t *testing.T) {
+	repo := openTestStore(t).Exercises()
+	repo.Create("user-1", newExercise("ex-1", "100% Effort", models.ExerciseTypeOther))
+	repo.Create("user-1", newExercise("ex-2", "1x0 Sprints", models.ExerciseTypeCardio))
+
+	page, err := repo.ListByNamePrefix("user-1", "1_0%", repository.ListOptions{})
+	if err != nil || len(page.Items) != 0 {
+		t.Errorf("ListByNamePrefix(1_0%%): got %v, err %v; want none", page, err)
+	}
+	page, err = repo.ListByNamePrefix("user-1", "100%", repository.ListOptions{})
+	if err != nil || len(page.Items) != 1 || page.Items[0].ExerciseID != "ex-1" {
+		t.Errorf("ListByNamePrefix(100%%): got %v, err %v; want [ex-1]", page, err)
+	}
+}
+
+func TestProfileRepository_Conformance(t *testing.T) {
+	repotest.RunProfileRepositoryTests(t, func(t *testing.T) repository.ProfileRepository {
+		return openTestStore(t).Profiles()
+	})
+}
+
+func TestMeasurementRepository_Conformance(t *testing.T) {
+	repotest.RunMeasurementRepositoryTests(t, func(t *testing.T) repository.MeasurementRepository {
+		return openTestStore(t).Measurements()
+	})
+}
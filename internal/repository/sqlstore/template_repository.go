@@ -0,0 +1,170 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// TemplateRepository implements repository.TemplateRepository on the templates
+// and template_exercises tables.
+type TemplateRepository struct {
+	store *Store
+}
+
+const selectTemplates = `SELECT user_id, template_id, name, created_at FROM templates`
+
+func (r *TemplateRepository) GetByID(userID, templateID string) (*models.Template, error) {
+	templates, err := r.query(selectTemplates+` WHERE user_id = ? AND template_id = ?`, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, models.ErrTemplateNotFound
+	}
+	return templates[0], nil
+}
+
+func (r *TemplateRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Template], error) {
+	query, args, err := keyset{"template_id"}.apply(selectTemplates+` WHERE user_id = ?`, []interface{}{userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return page(templates, opts, func(t *models.Template) []string {
+		return []string{t.TemplateID}
+	}), nil
+}
+
+func (r *TemplateRepository) Create(template *models.Template) error {
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = time.Now()
+	}
+	return r.store.inTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO templates (user_id, template_id, name, created_at) VALUES (?, ?, ?, ?)`),
+			template.UserID, template.TemplateID, template.Name, template.CreatedAt.UTC().Format(time.RFC3339Nano))
+		if err != nil {
+			return insertError(err, models.ErrTemplateAlreadyExists, "template")
+		}
+		return r.insertExercises(tx, template)
+	})
+}
+
+func (r *TemplateRepository) Update(template *models.Template) error {
+	return r.store.inTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(r.store.rebind(`UPDATE templates SET name = ? WHERE user_id = ? AND template_id = ?`),
+			template.Name, template.UserID, template.TemplateID)
+		if err != nil {
+			return fmt.Errorf("failed to update template: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return models.ErrTemplateNotFound
+		}
+		if err := r.deleteExercises(tx, template.UserID, template.TemplateID); err != nil {
+			return err
+		}
+		return r.insertExercises(tx, template)
+	})
+}
+
+// Delete removes the template; its prescriptions go with it through ON DELETE
+// CASCADE.
+func (r *TemplateRepository) Delete(userID, templateID string) error {
+	res, err := r.store.db.Exec(r.store.rebind(`DELETE FROM templates WHERE user_id = ? AND template_id = ?`), userID, templateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrTemplateNotFound
+	}
+	return nil
+}
+
+// query runs a template SELECT, which must filter on a single user, and attaches each template's prescriptions in order.
+func (r *TemplateRepository) query(query string, args ...interface{}) ([]*models.Template, error) {
+	rows, err := r.store.db.Query(r.store.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*models.Template
+	for rows.Next() {
+		var t models.Template
+		var createdAt string
+		if err := rows.Scan(&t.UserID, &t.TemplateID, &t.Name, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		if t.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse template created_at: %w", err)
+		}
+		t.Exercises = []models.ExercisePrescription{}
+		templates = append(templates, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read templates: %w", err)
+	}
+	if len(templates) == 0 {
+		return templates, nil
+	}
+	return templates, r.loadExercises(templates[0].UserID, templates)
+}
+
+func (r *TemplateRepository) loadExercises(userID string, templates []*models.Template) error {
+	byID := make(map[string]*models.Template, len(templates))
+	ids := make([]string, len(templates))
+	for i, t := range templates {
+		byID[t.TemplateID] = t
+		ids[i] = t.TemplateID
+	}
+
+	return forEachChunk(ids, func(chunk []string) error {
+		rows, err := r.store.db.Query(r.store.rebind(`SELECT template_id, name, exercise_type, target_sets, target_reps, target_weight, unit
+			FROM template_exercises WHERE user_id = ? AND template_id IN (`+placeholders(len(chunk))+`) ORDER BY template_id, position`), inArgs(userID, chunk)...)
+		if err != nil {
+			return fmt.Errorf("failed to query template exercises: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var templateID string
+			var p models.ExercisePrescription
+			if err := rows.Scan(&templateID, &p.Name, &p.ExerciseType, &p.TargetSets, &p.TargetReps, &p.TargetWeight, &p.Unit); err != nil {
+				return fmt.Errorf("failed to scan template exercise: %w", err)
+			}
+			t := byID[templateID]
+			t.Exercises = append(t.Exercises, p)
+		}
+		return rows.Err()
+	})
+}
+
+func (r *TemplateRepository) insertExercises(tx *sql.Tx, template *models.Template) error {
+	for i, p := range template.Exercises {
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO template_exercises (user_id, template_id, position, name, exercise_type, target_sets, target_reps, target_weight, unit)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			template.UserID, template.TemplateID, i, p.Name, p.ExerciseType, p.TargetSets, p.TargetReps, p.TargetWeight, p.Unit)
+		if err != nil {
+			return fmt.Errorf("failed to insert template exercise: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *TemplateRepository) deleteExercises(tx *sql.Tx, userID, templateID string) error {
+	_, err := tx.Exec(r.store.rebind(`DELETE FROM template_exercises WHERE user_id = ? AND template_id = ?`), userID, templateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete template exercises: %w", err)
+	}
+	return nil
+}
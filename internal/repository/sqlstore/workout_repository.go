@@ -0,0 +1,399 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+// WorkoutRepository implements repository.WorkoutRepository on the workouts,
+// workout_exercises and workout_groups tables.
+type WorkoutRepository struct {
+	store     *Store
+	retention time.Duration
+}
+
+// WithRetention makes deleted workouts expire from the trash retention after
+// they were deleted, as the DynamoDB repository does. Without it they stay
+// until purged.
+func (r *WorkoutRepository) WithRetention(retention time.Duration) *WorkoutRepository {
+	r.retention = retention
+	return r
+}
+
+const selectWorkouts = `SELECT user_id, workout_id, name, workout_date, created_at,
+	status, started_at, paused_at, finished_at, paused_seconds, duration_seconds, deleted_at FROM workouts`
+
+func (r *WorkoutRepository) GetByID(userID, workoutID string) (*models.Workout, error) {
+	workouts, err := r.query(selectWorkouts+` WHERE user_id = ? AND workout_id = ? AND deleted_at = ''`, userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if len(workouts) == 0 {
+		return nil, models.ErrWorkoutNotFound
+	}
+	return workouts[0], nil
+}
+
+func (r *WorkoutRepository) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	return r.list(selectWorkouts+` WHERE user_id = ? AND deleted_at = ''`, []interface{}{userID},
+		keyset{"workout_id"}, opts, workoutIDKey)
+}
+
+func (r *WorkoutRepository) ListByDateRange(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	query := selectWorkouts + ` WHERE user_id = ? AND deleted_at = ''`
+	args := []interface{}{userID}
+	if dateRange.From != "" {
+		query += ` AND workout_date >= ?`
+		args = append(args, dateRange.From)
+	}
+	if dateRange.To != "" {
+		query += ` AND workout_date <= ?`
+		args = append(args, dateRange.UpperBound())
+	}
+	return r.list(query, args, keyset{"workout_date", "workout_id"}, opts, func(w *models.Workout) []string {
+		return []string{w.Date, w.WorkoutID}
+	})
+}
+
+func (r *WorkoutRepository) Create(workout *models.Workout) error {
+	if workout.CreatedAt.IsZero() {
+		workout.CreatedAt = time.Now()
+	}
+	return r.store.inTx(func(tx *sql.Tx) error {
+		session := workout.WorkoutSession
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO workouts (user_id, workout_id, name, workout_date, created_at,
+			status, started_at, paused_at, finished_at, paused_seconds, duration_seconds) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+			workout.UserID, workout.WorkoutID, workout.Name, workout.Date, workout.CreatedAt.UTC().Format(time.RFC3339Nano),
+			session.Status, formatOptionalTime(session.StartedAt), formatOptionalTime(session.PausedAt), formatOptionalTime(session.FinishedAt),
+			session.PausedSeconds, session.DurationSeconds)
+		if err != nil {
+			return insertError(err, models.ErrWorkoutAlreadyExists, "workout")
+		}
+		return r.insertLinks(tx, workout)
+	})
+}
+
+func (r *WorkoutRepository) Update(workout *models.Workout) error {
+	return r.store.inTx(func(tx *sql.Tx) error {
+		session := workout.WorkoutSession
+		res, err := tx.Exec(r.store.rebind(`UPDATE workouts SET name = ?, workout_date = ?,
+			status = ?, started_at = ?, paused_at = ?, finished_at = ?, paused_seconds = ?, duration_seconds = ?
+			WHERE user_id = ? AND workout_id = ? AND deleted_at = ''`),
+			workout.Name, workout.Date,
+			session.Status, formatOptionalTime(session.StartedAt), formatOptionalTime(session.PausedAt), formatOptionalTime(session.FinishedAt),
+			session.PausedSeconds, session.DurationSeconds,
+			workout.UserID, workout.WorkoutID)
+		if err != nil {
+			return fmt.Errorf("failed to update workout: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return models.ErrWorkoutNotFound
+		}
+		if err := r.deleteLinks(tx, workout.UserID, workout.WorkoutID); err != nil {
+			return err
+		}
+		return r.insertLinks(tx, workout)
+	})
+}
+
+// Delete moves the workout to the trash, keeping its exercises and groups.
+func (r *WorkoutRepository) Delete(workoutID string, userID string) error {
+	now := time.Now().UTC()
+	res, err := r.store.db.Exec(r.store.rebind(`UPDATE workouts SET deleted_at = ?, expires_at = ? WHERE user_id = ? AND workout_id = ? AND deleted_at = ''`),
+		now.Format(time.RFC3339Nano), trashExpiry(now, r.retention), userID, workoutID)
+	if err != nil {
+		return fmt.Errorf("failed to delete workout: %w", err)
+	}
+	return r.affected(res)
+}
+
+func (r *WorkoutRepository) ListDeleted(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	return r.list(selectWorkouts+` WHERE user_id = ? AND `+inTrash, []interface{}{userID, trashNow()},
+		keyset{"workout_id"}, opts, workoutIDKey)
+}
+
+func (r *WorkoutRepository) Restore(userID, workoutID string) error {
+	res, err := r.store.db.Exec(r.store.rebind(`UPDATE workouts SET deleted_at = '', expires_at = 0 WHERE user_id = ? AND workout_id = ? AND `+inTrash),
+		userID, workoutID, trashNow())
+	if err != nil {
+		return fmt.Errorf("failed to restore workout: %w", err)
+	}
+	return r.affected(res)
+}
+
+// Purge removes a deleted workout; its exercise links and groups go with it
+// through ON DELETE CASCADE.
+func (r *WorkoutRepository) Purge(userID, workoutID string) error {
+	return r.purge(`DELETE FROM workouts WHERE user_id = ? AND workout_id = ? AND `+inTrash, userID, workoutID, trashNow())
+}
+
+func (r *WorkoutRepository) purge(query string, args ...interface{}) error {
+	res, err := r.store.db.Exec(r.store.rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("failed to purge workout: %w", err)
+	}
+	return r.affected(res)
+}
+
+func (r *WorkoutRepository) PurgeDeletedBefore(cutoff time.Time) (int, error) {
+	rows, err := r.store.db.Query(`SELECT user_id, workout_id, deleted_at FROM workouts WHERE deleted_at <> ''`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query deleted workouts: %w", err)
+	}
+	expired, err := scanExpired(rows, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read deleted workouts: %w", err)
+	}
+	purged := 0
+	for _, key := range expired {
+		// Expired rows are gone to Purge, so match any trashed row.
+		if err := r.purge(`DELETE FROM workouts WHERE user_id = ? AND workout_id = ? AND deleted_at <> ''`, key.userID, key.id); err != nil {
+			if errors.Is(err, models.ErrWorkoutNotFound) {
+				continue // restored or purged since the scan
+			}
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// affected maps an UPDATE or DELETE that matched no row to ErrWorkoutNotFound.
+func (r *WorkoutRepository) affected(res sql.Result) error {
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrWorkoutNotFound
+	}
+	return nil
+}
+
+func workoutIDKey(w *models.Workout) []string {
+	return []string{w.WorkoutID}
+}
+
+func (r *WorkoutRepository) list(query string, args []interface{}, keys keyset, opts repository.ListOptions, key func(*models.Workout) []string) (*repository.Page[*models.Workout], error) {
+	query, args, err := keys.apply(query, args, opts)
+	if err != nil {
+		return nil, err
+	}
+	workouts, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return page(workouts, opts, key), nil
+}
+
+// query runs a workout SELECT, which must filter on a single user, and attaches each workout's exercise IDs in order.
+func (r *WorkoutRepository) query(query string, args ...interface{}) ([]*models.Workout, error) {
+	rows, err := r.store.db.Query(r.store.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workouts: %w", err)
+	}
+	defer rows.Close()
+
+	var workouts []*models.Workout
+	for rows.Next() {
+		var w models.Workout
+		var createdAt, startedAt, pausedAt, finishedAt, deletedAt string
+		if err := rows.Scan(&w.UserID, &w.WorkoutID, &w.Name, &w.Date, &createdAt,
+			&w.Status, &startedAt, &pausedAt, &finishedAt, &w.PausedSeconds, &w.DurationSeconds, &deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workout: %w", err)
+		}
+		if w.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse workout created_at: %w", err)
+		}
+		if w.StartedAt, err = parseOptionalTime(startedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse workout started_at: %w", err)
+		}
+		if w.PausedAt, err = parseOptionalTime(pausedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse workout paused_at: %w", err)
+		}
+		if w.FinishedAt, err = parseOptionalTime(finishedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse workout finished_at: %w", err)
+		}
+		if w.DeletedAt, err = parseOptionalTime(deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse workout deleted_at: %w", err)
+		}
+		w.Exercises = []string{}
+		workouts = append(workouts, &w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read workouts: %w", err)
+	}
+	if len(workouts) == 0 {
+		return workouts, nil
+	}
+	return workouts, r.loadLinks(workouts[0].UserID, workouts)
+}
+
+func (r *WorkoutRepository) loadLinks(userID string, workouts []*models.Workout) error {
+	byID := make(map[string]*models.Workout, len(workouts))
+	ids := make([]string, len(workouts))
+	for i, w := range workouts {
+		byID[w.WorkoutID] = w
+		ids[i] = w.WorkoutID
+	}
+
+	return forEachChunk(ids, func(chunk []string) error {
+		if err := r.loadGroups(userID, chunk, byID); err != nil {
+			return err
+		}
+
+		rows, err := r.store.db.Query(r.store.rebind(`SELECT workout_id, exercise_id, group_id FROM workout_exercises
+			WHERE user_id = ? AND workout_id IN (`+placeholders(len(chunk))+`) ORDER BY workout_id, position`), inArgs(userID, chunk)...)
+		if err != nil {
+			return fmt.Errorf("failed to query workout exercises: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var workoutID, exerciseID, groupID string
+			if err := rows.Scan(&workoutID, &exerciseID, &groupID); err != nil {
+				return fmt.Errorf("failed to scan workout exercise: %w", err)
+			}
+			w := byID[workoutID]
+			w.Exercises = append(w.Exercises, exerciseID)
+			for i := range w.Groups {
+				if w.Groups[i].GroupID == groupID {
+					w.Groups[i].ExerciseIDs = append(w.Groups[i].ExerciseIDs, exerciseID)
+					break
+				}
+			}
+		}
+		return rows.Err()
+	})
+}
+
+// loadGroups attaches the groups of the workouts in ids, in order, without
+// their members; loadLinks fills those in.
+func (r *WorkoutRepository) loadGroups(userID string, ids []string, byID map[string]*models.Workout) error {
+	rows, err := r.store.db.Query(r.store.rebind(`SELECT workout_id, group_id, kind, rounds, rest_seconds FROM workout_groups
+		WHERE user_id = ? AND workout_id IN (`+placeholders(len(ids))+`) ORDER BY workout_id, position`), inArgs(userID, ids)...)
+	if err != nil {
+		return fmt.Errorf("failed to query workout groups: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workoutID string
+		var g models.ExerciseGroup
+		if err := rows.Scan(&workoutID, &g.GroupID, &g.Kind, &g.Rounds, &g.RestSeconds); err != nil {
+			return fmt.Errorf("failed to scan workout group: %w", err)
+		}
+		g.ExerciseIDs = []string{}
+		w := byID[workoutID]
+		w.Groups = append(w.Groups, g)
+	}
+	return rows.Err()
+}
+
+// insertLinks stores the workout's exercises, and its groups when it has any,
+// in order.
+func (r *WorkoutRepository) insertLinks(tx *sql.Tx, workout *models.Workout) error {
+	groupOf := make(map[string]string, len(workout.Exercises))
+	for i, g := range workout.Groups {
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO workout_groups (user_id, workout_id, position, group_id, kind, rounds, rest_seconds) VALUES (?, ?, ?, ?, ?, ?, ?)`),
+			workout.UserID, workout.WorkoutID, i, g.GroupID, g.Kind, g.Rounds, g.RestSeconds)
+		if err != nil {
+			return fmt.Errorf("failed to insert workout group: %w", err)
+		}
+		for _, exerciseID := range g.ExerciseIDs {
+			groupOf[exerciseID] = g.GroupID
+		}
+	}
+	for i, exerciseID := range workout.Exercises {
+		_, err := tx.Exec(r.store.rebind(`INSERT INTO workout_exercises (user_id, workout_id, position, exercise_id, group_id) VALUES (?, ?, ?, ?, ?)`),
+			workout.UserID, workout.WorkoutID, i, exerciseID, groupOf[exerciseID])
+		if err != nil {
+			return fmt.Errorf("failed to insert workout exercise: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *WorkoutRepository) deleteLinks(tx *sql.Tx, userID, workoutID string) error {
+	_, err := tx.Exec(r.store.rebind(`DELETE FROM workout_exercises WHERE user_id = ? AND workout_id = ?`), userID, workoutID)
+	if err != nil {
+		return fmt.Errorf("failed to delete workout exercises: %w", err)
+	}
+	_, err = tx.Exec(r.store.rebind(`DELETE FROM workout_groups WHERE user_id = ? AND workout_id = ?`), userID, workoutID)
+	if err != nil {
+		return fmt.Errorf("failed to delete workout groups: %w", err)
+	}
+	return nil
+}
+
+// formatOptionalTime stores a missing time as empty text.
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// inTrash matches rows that are in the trash and unexpired; its argument is
+// trashNow. Like the expiresAt TTL attribute of the DynamoDB tables, expires_at
+// is in epoch seconds: once it has passed the row is gone to ListDeleted,
+// Restore and Purge, although the sweeper has yet to remove it. Rows trashed
+// without a retention have an expires_at of zero and never expire.
+const inTrash = `deleted_at <> '' AND (expires_at = 0 OR expires_at > ?)`
+
+// trashNow is the current time that inTrash compares expires_at with.
+func trashNow() int64 {
+	return time.Now().Unix()
+}
+
+// trashExpiry is the expires_at of a row deleted at now.
+func trashExpiry(now time.Time, retention time.Duration) int64 {
+	if retention <= 0 {
+		return 0
+	}
+	return now.Add(retention).Unix()
+}
+
+// trashKey identifies a row in the trash.
+type trashKey struct {
+	userID, id string
+}
+
+// scanExpired reads (user_id, id, deleted_at) rows, keeping those deleted
+// before cutoff, and closes rows. The times are compared once parsed, since
+// RFC 3339 text with fractional seconds does not sort as text.
+func scanExpired(rows *sql.Rows, cutoff time.Time) ([]trashKey, error) {
+	defer rows.Close()
+	var expired []trashKey
+	for rows.Next() {
+		var key trashKey
+		var deletedAt string
+		if err := rows.Scan(&key.userID, &key.id, &deletedAt); err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, deletedAt)
+		if err != nil {
+			return nil, err
+		}
+		if t.Before(cutoff) {
+			expired = append(expired, key)
+		}
+	}
+	return expired, rows.Err()
+}
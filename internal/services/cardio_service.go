@@ -0,0 +1,205 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+type CardioService interface {
+	// CardioStats analyses every workout containing the named cardio
+	// exercise: pace, speed, RPM and machine metrics per session, interval
+	// comparisons, and a bucketed RPM trend.
+	CardioStats(userID, exerciseName string, query models.CardioQuery) (*models.CardioStats, error)
+}
+
+type cardioService struct {
+	workouts  repository.WorkoutRepository
+	exercises repository.ExerciseRepository
+	metrics   *MachineMetrics
+}
+
+func NewCardioService(workouts repository.WorkoutRepository, exercises repository.ExerciseRepository, metrics *MachineMetrics) CardioService {
+	return &cardioService{
+		workouts:  workouts,
+		exercises: exercises,
+		metrics:   metrics,
+	}
+}
+
+// intervalKey identifies sessions with the same interval structure.
+type intervalKey struct {
+	rounds   int
+	distance float64
+	unit     string
+}
+
+func (s *cardioService) CardioStats(userID, exerciseName string, query models.CardioQuery) (*models.CardioStats, error) {
+	query.Normalize()
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	exercises, err := s.exercises.ListByName(userID, exerciseName)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Exercise, len(exercises))
+	for _, e := range exercises {
+		if e.ExerciseType == models.ExerciseTypeCardio {
+			byID[e.ExerciseID] = e
+		}
+	}
+
+	workouts, err := repository.CollectAll(func(opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+		return s.workouts.ListByDateRange(userID, repository.DateRange{From: query.From, To: query.To}, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.CardioStats{
+		Name:     exerciseName,
+		Unit:     query.Unit,
+		Bucket:   query.Bucket,
+		Sessions: []models.CardioSession{},
+		RPMTrend: []models.StatsPoint{},
+	}
+	metric := s.metrics.For(exerciseName)
+	lastByKey := make(map[intervalKey]int) // index into stats.Sessions
+	averages := []float64{}
+	used := make(map[string]bool)
+
+	for _, workout := range workouts {
+		var rounds []*models.Exercise
+		for _, id := range workout.Exercises {
+			if e, ok := byID[id]; ok && !used[id] {
+				used[id] = true
+				rounds = append(rounds, e)
+			}
+		}
+		if len(rounds) == 0 {
+			continue
+		}
+
+		session := summariseCardio(workout, rounds, query.Unit, metric)
+		average := float64(session.Time) / float64(session.Rounds)
+		if session.Rounds > 1 {
+			key := intervalKey{session.Rounds, rounds[0].Distance, models.NormalizeDistanceUnit(rounds[0].DistanceUnit)}
+			if prev, ok := lastByKey[key]; ok && session.Time > 0 && stats.Sessions[prev].Time > 0 {
+				delta := average - averages[prev]
+				session.Intervals = &models.IntervalComparison{
+					PreviousWorkoutID: stats.Sessions[prev].WorkoutID,
+					PreviousDate:      stats.Sessions[prev].Date,
+					AverageTimeDelta:  round2(delta),
+					Improved:          delta < 0,
+				}
+			}
+			lastByKey[key] = len(stats.Sessions)
+		}
+		stats.Sessions = append(stats.Sessions, session)
+		averages = append(averages, average)
+	}
+
+	stats.RPMTrend = rpmTrend(stats.Sessions, query.Bucket)
+	return stats, nil
+}
+
+// summariseCardio totals one workout's rounds. Distance is reported in unit
+// when the rounds measure distance, or in calories when they do not.
+func summariseCardio(workout *models.Workout, rounds []*models.Exercise, unit string, metric MachineMetric) models.CardioSession {
+	session := models.CardioSession{
+		WorkoutID:    workout.WorkoutID,
+		Date:         workout.Date,
+		Rounds:       len(rounds),
+		DistanceUnit: unit,
+	}
+	calories := models.NormalizeDistanceUnit(rounds[0].DistanceUnit) == models.DistanceCalories
+	if calories {
+		session.DistanceUnit = models.DistanceCalories
+	}
+
+	var meters, rpmSum, metricSum float64
+	var rpmCount, metricCount int
+	for _, e := range rounds {
+		session.Time += e.Time
+		if len(rounds) > 1 && e.Time > 0 && (session.BestRoundTime == 0 || e.Time < session.BestRoundTime) {
+			session.BestRoundTime = e.Time
+		}
+		if calories {
+			if models.NormalizeDistanceUnit(e.DistanceUnit) == models.DistanceCalories {
+				session.Distance += e.Distance
+			}
+		} else if m, ok := models.DistanceInMeters(e.Distance, e.DistanceUnit); ok {
+			meters += m
+		}
+
+		rpm := e.RPM
+		if rpm == 0 {
+			rpm, _ = RPMMetric.Compute(e)
+		}
+		if rpm > 0 {
+			rpmSum += rpm
+			rpmCount++
+		}
+		if value, ok := metric.Compute(e); ok {
+			metricSum += value
+			metricCount++
+		}
+	}
+
+	if session.Time == 0 && len(rounds) == len(workout.Exercises) {
+		// Untimed rounds that make up the whole workout take its measured
+		// duration.
+		session.Time = workout.DurationSeconds
+	}
+
+	if !calories {
+		session.Distance = round2(models.MetersIn(meters, unit))
+		if session.Distance > 0 && session.Time > 0 {
+			session.Pace = round2(float64(session.Time) / models.MetersIn(meters, unit))
+			session.Speed = round2(models.MetersIn(meters, unit) / (float64(session.Time) / 3600))
+		}
+	}
+	if rpmCount > 0 {
+		session.RPM = round2(rpmSum / float64(rpmCount))
+	}
+	if metricCount > 0 {
+		session.Machine = &models.MachineValue{
+			Metric: metric.Name,
+			Unit:   metric.Unit,
+			Value:  round2(metricSum / float64(metricCount)),
+		}
+	}
+	return session
+}
+
+// rpmTrend averages session RPMs per bucket, ignoring sessions without one.
+func rpmTrend(sessions []models.CardioSession, bucket string) []models.StatsPoint {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, session := range sessions {
+		date, err := models.ParseDate(session.Date)
+		if err != nil || session.RPM == 0 {
+			continue
+		}
+		period := models.BucketStart(bucket, date).Format(models.DateLayout)
+		sums[period] += session.RPM
+		counts[period]++
+	}
+
+	trend := make([]models.StatsPoint, 0, len(sums))
+	for period, sum := range sums {
+		trend = append(trend, models.StatsPoint{Period: period, Value: round2(sum / float64(counts[period]))})
+	}
+	sort.Slice(trend, func(i, j int) bool {
+		return trend[i].Period < trend[j].Period
+	})
+	return trend
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+)
+
+// session stores a workout on date with one cardio exercise per round time,
+// the way cmd/import splits interval rows.
+func (f *fixture) session(t *testing.T, id, date, name string, distance float64, unit string, times ...int) {
+	t.Helper()
+	workout := &models.Workout{UserID: "user-1", WorkoutID: id, Name: "Cardio", Date: date}
+	for i, tm := range times {
+		exercise := &models.Exercise{
+			ExerciseID:   id + "-" + string(rune('a'+i)),
+			Name:         name,
+			ExerciseType: models.ExerciseTypeCardio,
+			Distance:     distance,
+			DistanceUnit: unit,
+			Time:         tm,
+		}
+		if err := f.exercises.Create("user-1", exercise); err != nil {
+			t.Fatalf("create exercise: %v", err)
+		}
+		workout.Exercises = append(workout.Exercises, exercise.ExerciseID)
+	}
+	if err := f.workouts.Create(workout); err != nil {
+		t.Fatalf("create workout: %v", err)
+	}
+}
+
+func TestCardioStats_PaceSpeedAndRPM(t *testing.T) {
+	f := newFixture()
+	f.session(t, "w-1", "2024-03-04", "Bike", 10, "km", 1800)
+
+	stats, err := f.cardioSvc.CardioStats("user-1", "bike", models.CardioQuery{})
+	if err != nil {
+		t.Fatalf("CardioStats: %v", err)
+	}
+	if len(stats.Sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(stats.Sessions))
+	}
+	s := stats.Sessions[0]
+	if s.Distance != 10 || s.DistanceUnit != "km" || s.Pace != 180 || s.Speed != 20 {
+		t.Errorf("session = %+v, want 10 km at 180 s/km and 20 km/h", s)
+	}
+	wantRPM := round2(10000 / 6.2 / 30)
+	if s.RPM != wantRPM || s.Machine == nil || s.Machine.Metric != "rpm" {
+		t.Errorf("RPM = %v, machine = %+v, want %v rpm", s.RPM, s.Machine, wantRPM)
+	}
+	if len(stats.RPMTrend) != 1 || stats.RPMTrend[0].Period != "2024-03-04" {
+		t.Errorf("RPMTrend = %+v, want one point for week of 2024-03-04", stats.RPMTrend)
+	}
+
+	miles, _ := f.cardioSvc.CardioStats("user-1", "Bike", models.CardioQuery{Unit: "miles"})
+	if got := miles.Sessions[0]; got.DistanceUnit != "mi" || got.Distance != 6.21 || got.Pace != 289.68 {
+		t.Errorf("miles session = %+v, want 6.21 mi at 289.68 s/mi", got)
+	}
+}
+
+func TestCardioStats_IntervalImprovement(t *testing.T) {
+	f := newFixture()
+	f.session(t, "w-1", "2024-03-04", "Row", 500, "m", 100, 104, 106)
+	f.session(t, "w-2", "2024-03-06", "Row", 1000, "m", 220, 230)
+	f.session(t, "w-3", "2024-03-11", "Row", 500, "m", 98, 100, 102)
+
+	stats, err := f.cardioSvc.CardioStats("user-1", "Row", models.CardioQuery{})
+	if err != nil {
+		t.Fatalf("CardioStats: %v", err)
+	}
+	if len(stats.Sessions) != 3 {
+		t.Fatalf("got %d sessions, want 3", len(stats.Sessions))
+	}
+	if stats.Sessions[0].Intervals != nil || stats.Sessions[1].Intervals != nil {
+		t.Error("sessions without an earlier match should have no interval comparison")
+	}
+
+	last := stats.Sessions[2]
+	if last.Rounds != 3 || last.BestRoundTime != 98 {
+		t.Errorf("rounds = %d, best = %d, want 3 and 98", last.Rounds, last.BestRoundTime)
+	}
+	cmp := last.Intervals
+	if cmp == nil || cmp.PreviousWorkoutID != "w-1" || cmp.AverageTimeDelta != -3.33 || !cmp.Improved {
+		t.Errorf("Intervals = %+v, want improvement of -3.33 s/round over w-1", cmp)
+	}
+	if last.Machine == nil || last.Machine.Metric != "split_500m" || last.Machine.Value != 100 {
+		t.Errorf("Machine = %+v, want 100 s/500m split", last.Machine)
+	}
+}
+
+func TestCardioStats_CaloriesHaveNoPace(t *testing.T) {
+	f := newFixture()
+	f.session(t, "w-1", "2024-03-04", "Ski Erg", 15, "cal", 60, 60)
+
+	stats, _ := f.cardioSvc.CardioStats("user-1", "Ski Erg", models.CardioQuery{})
+	s := stats.Sessions[0]
+	if s.DistanceUnit != "cal" || s.Distance != 30 || s.Pace != 0 || s.Speed != 0 {
+		t.Errorf("session = %+v, want 30 cal with no pace or speed", s)
+	}
+	if s.Machine == nil || s.Machine.Metric != "watts" || s.Machine.Value != 150 {
+		t.Errorf("Machine = %+v, want 150 W", s.Machine)
+	}
+}
+
+func TestCardioStats_UntimedRoundsUseWorkoutDuration(t *testing.T) {
+	f := newFixture()
+	f.session(t, "w-1", "2024-03-04", "Run", 5, "km", 0)
+	w, _ := f.workouts.GetByID("user-1", "w-1")
+	w.WorkoutSession = models.WorkoutSession{Status: models.WorkoutCompleted, DurationSeconds: 1500}
+	if err := f.workouts.Update(w); err != nil {
+		t.Fatalf("update workout: %v", err)
+	}
+
+	stats, err := f.cardioSvc.CardioStats("user-1", "run", models.CardioQuery{})
+	if err != nil {
+		t.Fatalf("CardioStats: %v", err)
+	}
+	if s := stats.Sessions[0]; s.Time != 1500 || s.Pace != 300 {
+		t.Errorf("session = %+v, want 1500 s at 300 s/km", s)
+	}
+}
+
+func TestCardioStats_InvalidQuery(t *testing.T) {
+	f := newFixture()
+	if _, err := f.cardioSvc.CardioStats("user-1", "Bike", models.CardioQuery{Unit: "cal"}); !errors.Is(err, models.ErrInvalidStatsQuery) {
+		t.Errorf("err = %v, want ErrInvalidStatsQuery", err)
+	}
+}
+
+func TestMachineMetrics_Registry(t *testing.T) {
+	m := DefaultMachineMetrics()
+	tests := map[string]string{
+		"Concept2 Rower": "split_500m",
+		"Rowing":         "split_500m",
+		" row ":          "split_500m",
+		"Ski Erg":        "watts",
+		"SkiErg":         "watts",
+		"Assault Bike":   "rpm",
+		// Keywords only match whole words, and a bare "row" only the whole name.
+		"Skipping":        "rpm",
+		"Ski jumps":       "rpm",
+		"Barrow carry":    "rpm",
+		"Upright row":     "rpm",
+		"Bent over row":   "rpm",
+		"Narrow grip row": "rpm",
+	}
+	for name, want := range tests {
+		if got := m.For(name).Name; got != want {
+			t.Errorf("For(%q) = %s, want %s", name, got, want)
+		}
+	}
+
+	m.Register("treadmill", MachineMetric{Name: "incline", Compute: func(*models.Exercise) (float64, bool) { return 0, false }})
+	if got := m.For("Treadmill Run").Name; got != "incline" {
+		t.Errorf("registered metric not used: got %s", got)
+	}
+}
+
+func TestSkiErgWattsMetric_Distance(t *testing.T) {
+	// 500 m in 120 s: pace 0.24 s/m, 2.80 / 0.24³ ≈ 202.5 W.
+	watts, ok := SkiErgWattsMetric.Compute(&models.Exercise{Distance: 500, DistanceUnit: "m", Time: 120})
+	if !ok || math.Abs(watts-202.55) > 0.01 {
+		t.Errorf("watts = %v, %v; want about 202.55", watts, ok)
+	}
+}
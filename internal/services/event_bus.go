@@ -0,0 +1,92 @@
+package services
+
+import (
+	"sync"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/utils"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind by before
+// it is dropped.
+const subscriberBuffer = 32
+
+// EventPublisher receives the changes services make to workouts and
+// exercises.
+type EventPublisher interface {
+	Publish(event models.WorkoutEvent)
+}
+
+// EventBus fans published events out to subscribers in the same process. It
+// does not reach other instances of the API.
+type EventBus interface {
+	EventPublisher
+	// Subscribe returns the user's events as they are published and a
+	// function that ends the subscription. The channel is closed when the
+	// subscription ends, including when the subscriber falls so far behind
+	// that events would be lost; it should then reload and subscribe again.
+	Subscribe(userID string) (<-chan models.WorkoutEvent, func())
+}
+
+type eventBus struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan models.WorkoutEvent]bool // UserID -> subscribers
+}
+
+// NewEventBus returns an empty, in-process EventBus.
+func NewEventBus() EventBus {
+	return &eventBus{
+		subscribers: make(map[string]map[chan models.WorkoutEvent]bool),
+	}
+}
+
+// Publish never blocks: a subscriber whose buffer is full is dropped.
+func (b *eventBus) Publish(event models.WorkoutEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+			b.remove(event.UserID, ch)
+		}
+	}
+}
+
+func (b *eventBus) Subscribe(userID string) (<-chan models.WorkoutEvent, func()) {
+	ch := make(chan models.WorkoutEvent, subscriberBuffer)
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan models.WorkoutEvent]bool)
+	}
+	b.subscribers[userID][ch] = true
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(userID, ch)
+	}
+}
+
+// remove closes and forgets a subscriber; it is a no-op if the subscriber is
+// already gone. The caller must hold b.mu.
+func (b *eventBus) remove(userID string, ch chan models.WorkoutEvent) {
+	if !b.subscribers[userID][ch] {
+		return
+	}
+	delete(b.subscribers[userID], ch)
+	if len(b.subscribers[userID]) == 0 {
+		delete(b.subscribers, userID)
+	}
+	close(ch)
+}
+
+// publish stamps and publishes an event; a nil publisher drops it.
+func publish(events EventPublisher, event models.WorkoutEvent) {
+	if events == nil {
+		return
+	}
+	event.At = utils.GetCurrentTime()
+	events.Publish(event)
+}
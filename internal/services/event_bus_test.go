@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"gym-tracker-api/internal/models"
+)
+
+// recordingPublisher collects published events.
+type recordingPublisher struct {
+	events []models.WorkoutEvent
+}
+
+func (p *recordingPublisher) Publish(event models.WorkoutEvent) {
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) types() []string {
+	types := make([]string, len(p.events))
+	for i, e := range p.events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestEventBus_DeliversToUsersSubscribers(t *testing.T) {
+	bus := NewEventBus()
+	mine, cancelMine := bus.Subscribe("user-1")
+	defer cancelMine()
+	theirs, cancelTheirs := bus.Subscribe("user-2")
+	defer cancelTheirs()
+
+	bus.Publish(models.WorkoutEvent{Type: models.EventStatusChanged, UserID: "user-1", WorkoutID: "w-1"})
+
+	select {
+	case event := <-mine:
+		if event.Type != models.EventStatusChanged || event.WorkoutID != "w-1" {
+			t.Errorf("event = %+v", event)
+		}
+	default:
+		t.Fatal("subscriber received nothing")
+	}
+	select {
+	case event := <-theirs:
+		t.Errorf("other user received %+v", event)
+	default:
+	}
+}
+
+func TestEventBus_CancelClosesChannel(t *testing.T) {
+	bus := NewEventBus()
+	events, cancel := bus.Subscribe("user-1")
+	cancel()
+	cancel() // a second cancel is harmless
+
+	if _, ok := <-events; ok {
+		t.Error("expected closed channel after cancel")
+	}
+	bus.Publish(models.WorkoutEvent{Type: models.EventWorkoutUpdated, UserID: "user-1"})
+}
+
+func TestEventBus_DropsSlowSubscriber(t *testing.T) {
+	bus := NewEventBus()
+	events, cancel := bus.Subscribe("user-1")
+	defer cancel()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		bus.Publish(models.WorkoutEvent{Type: models.EventWorkoutUpdated, UserID: "user-1"})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("received %d events before close, want %d", received, subscriberBuffer)
+	}
+}
@@ -3,26 +3,45 @@ package services
 import (
 	"gym-tracker-api/internal/models"
 	"gym-tracker-api/internal/repository"
-	"strings"
+	"log"
 )
 
 type ExerciseService interface {
 	GetExercise(userID, exerciseID string) (*models.Exercise, error)
-	GetExercises(userID string) ([]*models.Exercise, error)
+	GetExercises(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error)
 	CreateExercise(userID string, exercise *models.Exercise, storeRpm bool) error
 	UpdateExercise(userID, exerciseID string, exercise *models.Exercise, storeRpm bool) error
+	// DeleteExercise moves the exercise to the trash and forgets its records.
 	DeleteExercise(userID, exerciseID string) error
-	ListExercisesByType(userID, exerciseType string) ([]*models.Exercise, error)
+	ListDeletedExercises(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error)
+	// RestoreExercise takes the exercise out of the trash, tracks its
+	// records again and returns it.
+	RestoreExercise(userID, exerciseID string) (*models.Exercise, error)
+	// DiscardExercise permanently deletes an exercise the user never saw,
+	// such as one left over from a failed template start. It skips the trash
+	// and publishes nothing.
+	DiscardExercise(userID, exerciseID string) error
+	ListExercisesByType(userID, exerciseType string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error)
 	ListExercisesByName(userID, exerciseName string) ([]*models.Exercise, error)
+	SearchExercisesByName(userID, prefix string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error)
 }
 
 type exerciseService struct {
-	repo repository.ExerciseRepository
+	repo    repository.ExerciseRepository
+	records RecordService
+	events  EventPublisher
+	metrics *MachineMetrics
 }
 
-func NewExerciseService(repo repository.ExerciseRepository) ExerciseService {
+// NewExerciseService returns an ExerciseService. When records is not nil,
+// personal records are tracked as exercises are created, updated and deleted.
+// When events is not nil, updates and deletions are published to it.
+func NewExerciseService(repo repository.ExerciseRepository, records RecordService, events EventPublisher) ExerciseService {
 	return &exerciseService{
-		repo: repo,
+		repo:    repo,
+		records: records,
+		events:  events,
+		metrics: DefaultMachineMetrics(),
 	}
 }
 
@@ -34,71 +53,162 @@ func (s *exerciseService) GetExercise(userID, exerciseID string) (*models.Exerci
 	return exercise, nil
 }
 
-func (s *exerciseService) GetExercises(userID string) ([]*models.Exercise, error) {
-	exercises, err := s.repo.ListByUserID(userID)
+func (s *exerciseService) GetExercises(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	page, err := s.repo.ListByUserID(userID, pageOptions(opts))
 	if err != nil {
 		return nil, err
 	}
-	return exercises, nil
+	return page, nil
 }
 
 func (s *exerciseService) CreateExercise(userID string, exercise *models.Exercise, storeRpm bool) error {
+	exercise.NormalizeUnits()
 	if err := exercise.Validate(); err != nil {
 		return err
 	}
 	if storeRpm {
-		exercise.RPM = calculateRPM(exercise)
+		exercise.RPM = s.storedRPM(exercise)
+	}
+	exercise.NewRecords = nil
+	if err := s.repo.Create(userID, exercise); err != nil {
+		return err
 	}
-	return s.repo.Create(userID, exercise)
+	s.trackRecords(userID, exercise, false)
+	return nil
 }
 
 func (s *exerciseService) UpdateExercise(userID string, exerciseID string, exercise *models.Exercise, storeRpm bool) error {
+	exercise.NormalizeUnits()
 	if err := exercise.Validate(); err != nil {
 		return err
 	}
 	exercise.ExerciseID = exerciseID
 	if storeRpm {
-		exercise.RPM = calculateRPM(exercise)
+		exercise.RPM = s.storedRPM(exercise)
+	}
+	exercise.NewRecords = nil
+	// The stored exercise keeps its creation time, which dates its records,
+	// and tells whether a set was logged; a failed read falls back to a
+	// plain update event.
+	previous, _ := s.repo.GetByID(userID, exerciseID)
+	if previous != nil {
+		exercise.CreatedAt = previous.CreatedAt
+	}
+	if err := s.repo.Update(userID, exercise); err != nil {
+		return err
+	}
+	s.trackRecords(userID, exercise, true)
+
+	publish(s.events, models.WorkoutEvent{Type: models.EventExerciseUpdated, UserID: userID, ExerciseID: exerciseID})
+	if previous != nil && loggedSet(previous, exercise) {
+		publish(s.events, models.WorkoutEvent{Type: models.EventSetLogged, UserID: userID, ExerciseID: exerciseID})
+	}
+	return nil
+}
+
+// loggedSet reports whether updating before to after added a set or
+// completed one.
+func loggedSet(before, after *models.Exercise) bool {
+	return len(after.Sets) > len(before.Sets) || completedSets(after) > completedSets(before)
+}
+
+func completedSets(exercise *models.Exercise) int {
+	n := 0
+	for _, set := range exercise.Sets {
+		if set.Completed {
+			n++
+		}
+	}
+	return n
+}
+
+// trackRecords sets exercise.NewRecords to the personal records it beats. An
+// edited exercise first gives up the records it held, so correcting a typo
+// also corrects the records. The exercise itself is already saved, so
+// failures are logged rather than returned.
+func (s *exerciseService) trackRecords(userID string, exercise *models.Exercise, edited bool) {
+	if s.records == nil {
+		return
+	}
+	if edited {
+		if err := s.records.ForgetExercise(userID, exercise.ExerciseID); err != nil {
+			log.Printf("failed to clear records for exercise %s: %v", exercise.ExerciseID, err)
+			return
+		}
+	}
+	records, err := s.records.TrackExercise(userID, exercise)
+	if err != nil {
+		log.Printf("failed to track records for exercise %s: %v", exercise.ExerciseID, err)
 	}
-	return s.repo.Update(userID, exercise)
+	exercise.NewRecords = records
 }
 
-// calculateRPM computes revolutions per minute for a cardio exercise.
-// Requires cardio type, a positive time (seconds), and distance in miles or km.
-// Uses the rule: 6.2 metres = 1 revolution.
-func calculateRPM(exercise *models.Exercise) float64 {
-	if exercise.ExerciseType != models.ExerciseTypeCardio {
+// storedRPM is the cadence stored with an exercise when the client asks for
+// it. Only machines whose metric is RPM have one; rowers and ski ergs report
+// their own metric through the cardio stats instead.
+func (s *exerciseService) storedRPM(exercise *models.Exercise) float64 {
+	metric := s.metrics.For(exercise.Name)
+	if metric.Name != RPMMetric.Name {
 		return 0
 	}
-	if exercise.Time <= 0 || exercise.Distance <= 0 {
+	rpm, ok := metric.Compute(exercise)
+	if !ok {
 		return 0
 	}
+	return rpm
+}
 
-	var distanceMeters float64
-	switch strings.ToLower(exercise.DistanceUnit) {
-	case "miles", "mile":
-		distanceMeters = exercise.Distance * 1609.344
-	case "km", "kilometers", "kilometre", "kilometres":
-		distanceMeters = exercise.Distance * 1000
-	default:
-		return 0
+func (s *exerciseService) DeleteExercise(userID, exerciseID string) error {
+	if err := s.repo.Delete(userID, exerciseID); err != nil {
+		return err
+	}
+	if s.records != nil {
+		if err := s.records.ForgetExercise(userID, exerciseID); err != nil {
+			log.Printf("failed to clear records for exercise %s: %v", exerciseID, err)
+		}
 	}
+	publish(s.events, models.WorkoutEvent{Type: models.EventExerciseRemoved, UserID: userID, ExerciseID: exerciseID})
+	return nil
+}
 
-	revolutions := distanceMeters / 6.2
-	minutes := float64(exercise.Time) / 60.0
-	return revolutions / minutes
+func (s *exerciseService) ListDeletedExercises(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	page, err := s.repo.ListDeleted(userID, pageOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
 }
 
-func (s *exerciseService) DeleteExercise(userID, exerciseID string) error {
-	return s.repo.Delete(userID, exerciseID)
+func (s *exerciseService) RestoreExercise(userID, exerciseID string) (*models.Exercise, error) {
+	if err := s.repo.Restore(userID, exerciseID); err != nil {
+		return nil, err
+	}
+	exercise, err := s.repo.GetByID(userID, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	s.trackRecords(userID, exercise, false)
+	return exercise, nil
 }
 
-func (s *exerciseService) ListExercisesByType(userID, exerciseType string) ([]*models.Exercise, error) {
-	exercises, err := s.repo.ListByType(userID, exerciseType)
+func (s *exerciseService) DiscardExercise(userID, exerciseID string) error {
+	if err := s.repo.Delete(userID, exerciseID); err != nil {
+		return err
+	}
+	if s.records != nil {
+		if err := s.records.ForgetExercise(userID, exerciseID); err != nil {
+			log.Printf("failed to clear records for exercise %s: %v", exerciseID, err)
+		}
+	}
+	return s.repo.Purge(userID, exerciseID)
+}
+
+func (s *exerciseService) ListExercisesByType(userID, exerciseType string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	page, err := s.repo.ListByType(userID, exerciseType, pageOptions(opts))
 	if err != nil {
 		return nil, err
 	}
-	return exercises, nil
+	return page, nil
 }
 
 func (s *exerciseService) ListExercisesByName(userID, exerciseName string) ([]*models.Exercise, error) {
@@ -108,3 +218,16 @@ func (s *exerciseService) ListExercisesByName(userID, exerciseName string) ([]*m
 	}
 	return exercises, nil
 }
+
+// SearchExercisesByName returns exercises whose name starts with prefix,
+// ignoring case and extra whitespace.
+func (s *exerciseService) SearchExercisesByName(userID, prefix string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	if models.NormalizeName(prefix) == "" {
+		return s.GetExercises(userID, opts)
+	}
+	page, err := s.repo.ListByNamePrefix(userID, prefix, pageOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
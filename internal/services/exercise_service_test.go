@@ -3,9 +3,11 @@ package services
 import (
 	"errors"
 	"math"
+	"reflect"
 	"testing"
 
 	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
 )
 
 func approxEqual(a, b float64) bool {
@@ -17,24 +19,56 @@ type mockExerciseRepo struct {
 	exercise  *models.Exercise
 	exercises []*models.Exercise
 	err       error
+	prefix    string
 }
 
 func (m *mockExerciseRepo) GetByID(userID, exerciseID string) (*models.Exercise, error) {
 	return m.exercise, m.err
 }
 
-func (m *mockExerciseRepo) ListByUserID(userID string) ([]*models.Exercise, error) {
-	return m.exercises, m.err
+func (m *mockExerciseRepo) GetMany(userID string, exerciseIDs []string) ([]*models.Exercise, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	wanted := make(map[string]bool, len(exerciseIDs))
+	for _, id := range exerciseIDs {
+		wanted[id] = true
+	}
+	var found []*models.Exercise
+	for _, e := range m.exercises {
+		if wanted[e.ExerciseID] {
+			found = append(found, e)
+		}
+	}
+	return found, nil
 }
 
-func (m *mockExerciseRepo) ListByType(userID, exerciseType string) ([]*models.Exercise, error) {
-	return m.exercises, m.err
+func (m *mockExerciseRepo) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &repository.Page[*models.Exercise]{Items: m.exercises}, nil
+}
+
+func (m *mockExerciseRepo) ListByType(userID, exerciseType string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &repository.Page[*models.Exercise]{Items: m.exercises}, nil
 }
 
 func (m *mockExerciseRepo) ListByName(userID, exerciseName string) ([]*models.Exercise, error) {
 	return m.exercises, m.err
 }
 
+func (m *mockExerciseRepo) ListByNamePrefix(userID, prefix string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.prefix = prefix
+	return &repository.Page[*models.Exercise]{Items: m.exercises}, nil
+}
+
 func (m *mockExerciseRepo) Create(userID string, exercise *models.Exercise) error {
 	return m.err
 }
@@ -47,6 +81,21 @@ func (m *mockExerciseRepo) Delete(userID, exerciseID string) error {
 	return m.err
 }
 
+func (m *mockExerciseRepo) ListDeleted(userID string, opts repository.ListOptions) (*repository.Page[*models.Exercise], error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &repository.Page[*models.Exercise]{}, nil
+}
+
+func (m *mockExerciseRepo) Restore(userID, exerciseID string) error {
+	return m.err
+}
+
+func (m *mockExerciseRepo) Purge(userID, exerciseID string) error {
+	return m.err
+}
+
 func sampleExercise() *models.Exercise {
 	return &models.Exercise{
 		ExerciseID:   "ex-1",
@@ -59,7 +108,7 @@ func sampleExercise() *models.Exercise {
 
 func TestGetExercise_Success(t *testing.T) {
 	want := sampleExercise()
-	svc := NewExerciseService(&mockExerciseRepo{exercise: want})
+	svc := NewExerciseService(&mockExerciseRepo{exercise: want}, nil, nil)
 
 	got, err := svc.GetExercise("user-1", "ex-1")
 	if err != nil {
@@ -71,7 +120,7 @@ func TestGetExercise_Success(t *testing.T) {
 }
 
 func TestGetExercise_RepoError(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{err: models.ErrExerciseNotFound})
+	svc := NewExerciseService(&mockExerciseRepo{err: models.ErrExerciseNotFound}, nil, nil)
 
 	_, err := svc.GetExercise("user-1", "missing")
 	if err == nil {
@@ -83,21 +132,21 @@ func TestGetExercise_RepoError(t *testing.T) {
 
 func TestGetExercises_Success(t *testing.T) {
 	exercises := []*models.Exercise{sampleExercise()}
-	svc := NewExerciseService(&mockExerciseRepo{exercises: exercises})
+	svc := NewExerciseService(&mockExerciseRepo{exercises: exercises}, nil, nil)
 
-	got, err := svc.GetExercises("user-1")
+	got, err := svc.GetExercises("user-1", repository.ListOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(got) != 1 {
-		t.Errorf("expected 1 exercise, got %d", len(got))
+	if len(got.Items) != 1 {
+		t.Errorf("expected 1 exercise, got %d", len(got.Items))
 	}
 }
 
 func TestGetExercises_RepoError(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{err: errors.New("db error")})
+	svc := NewExerciseService(&mockExerciseRepo{err: errors.New("db error")}, nil, nil)
 
-	_, err := svc.GetExercises("user-1")
+	_, err := svc.GetExercises("user-1", repository.ListOptions{})
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
@@ -106,7 +155,7 @@ func TestGetExercises_RepoError(t *testing.T) {
 // CreateExercise
 
 func TestCreateExercise_Success(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	if err := svc.CreateExercise("user-1", sampleExercise(), false); err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -114,7 +163,7 @@ func TestCreateExercise_Success(t *testing.T) {
 }
 
 func TestCreateExercise_MissingName(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	e := sampleExercise()
 	e.Name = ""
@@ -124,8 +173,19 @@ func TestCreateExercise_MissingName(t *testing.T) {
 	}
 }
 
+func TestCreateExercise_WhitespaceName(t *testing.T) {
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
+
+	e := sampleExercise()
+	e.Name = "   "
+
+	if err := svc.CreateExercise("user-1", e, false); err == nil {
+		t.Error("expected validation error for blank name, got nil")
+	}
+}
+
 func TestCreateExercise_MissingExerciseType(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	e := sampleExercise()
 	e.ExerciseType = ""
@@ -136,7 +196,7 @@ func TestCreateExercise_MissingExerciseType(t *testing.T) {
 }
 
 func TestCreateExercise_MissingID(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	e := sampleExercise()
 	e.ExerciseID = ""
@@ -147,7 +207,7 @@ func TestCreateExercise_MissingID(t *testing.T) {
 }
 
 func TestCreateExercise_RepoError(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{err: errors.New("write failed")})
+	svc := NewExerciseService(&mockExerciseRepo{err: errors.New("write failed")}, nil, nil)
 
 	if err := svc.CreateExercise("user-1", sampleExercise(), false); err == nil {
 		t.Error("expected repo error, got nil")
@@ -157,7 +217,7 @@ func TestCreateExercise_RepoError(t *testing.T) {
 // UpdateExercise
 
 func TestUpdateExercise_Success(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	if err := svc.UpdateExercise("user-1", "ex-1", sampleExercise(), false); err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -165,7 +225,7 @@ func TestUpdateExercise_Success(t *testing.T) {
 }
 
 func TestUpdateExercise_ValidationError(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	e := sampleExercise()
 	e.Name = ""
@@ -175,7 +235,32 @@ func TestUpdateExercise_ValidationError(t *testing.T) {
 	}
 }
 
-// calculateRPM
+func TestUpdateExercise_PublishesSetLogged(t *testing.T) {
+	stored := sampleExercise()
+	events := &recordingPublisher{}
+	svc := NewExerciseService(&mockExerciseRepo{exercise: stored}, nil, events)
+
+	renamed := sampleExercise()
+	renamed.Name = "Incline Bench"
+	if err := svc.UpdateExercise("user-1", stored.ExerciseID, renamed, false); err != nil {
+		t.Fatalf("UpdateExercise: %v", err)
+	}
+	logged := sampleExercise()
+	logged.Sets = append(logged.Sets, models.WeightItem{Weight: 60, Unit: "kg", Reps: 8})
+	if err := svc.UpdateExercise("user-1", stored.ExerciseID, logged, false); err != nil {
+		t.Fatalf("UpdateExercise: %v", err)
+	}
+
+	want := []string{models.EventExerciseUpdated, models.EventExerciseUpdated, models.EventSetLogged}
+	if got := events.types(); !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	if e := events.events[2]; e.ExerciseID != stored.ExerciseID || e.WorkoutID != "" {
+		t.Errorf("set_logged = %+v", e)
+	}
+}
+
+// RPMMetric
 
 func sampleCardioExercise() *models.Exercise {
 	return &models.Exercise{
@@ -188,17 +273,17 @@ func sampleCardioExercise() *models.Exercise {
 	}
 }
 
-func TestCalculateRPM_KM(t *testing.T) {
+func TestRPMMetric_KM(t *testing.T) {
 	// 10 km = 10000 m; revolutions = 10000 / 6.2 ≈ 1612.9; minutes = 3600/60 = 60; rpm ≈ 26.88
 	e := sampleCardioExercise()
-	rpm := calculateRPM(e)
+	rpm, ok := RPMMetric.Compute(e)
 	expected := (10000.0 / 6.2) / 60.0
-	if !approxEqual(rpm, expected) {
-		t.Errorf("expected rpm %f, got %f", expected, rpm)
+	if !ok || !approxEqual(rpm, expected) {
+		t.Errorf("expected rpm %f, got %f (ok=%v)", expected, rpm, ok)
 	}
 }
 
-func TestCalculateRPM_Miles(t *testing.T) {
+func TestRPMMetric_Miles(t *testing.T) {
 	// 5 miles = 5 * 1609.344 = 8046.72 m; revolutions = 8046.72 / 6.2 ≈ 1297.86; minutes = 1800/60 = 30; rpm ≈ 43.26
 	e := &models.Exercise{
 		ExerciseID:   "ex-3",
@@ -208,46 +293,57 @@ func TestCalculateRPM_Miles(t *testing.T) {
 		DistanceUnit: "miles",
 		Time:         1800,
 	}
-	rpm := calculateRPM(e)
+	rpm, ok := RPMMetric.Compute(e)
 	expected := (5 * 1609.344 / 6.2) / 30.0
-	if !approxEqual(rpm, expected) {
-		t.Errorf("expected rpm %f, got %f", expected, rpm)
+	if !ok || !approxEqual(rpm, expected) {
+		t.Errorf("expected rpm %f, got %f (ok=%v)", expected, rpm, ok)
 	}
 }
 
-func TestCalculateRPM_NonCardio(t *testing.T) {
+func TestRPMMetric_Meters(t *testing.T) {
+	e := sampleCardioExercise()
+	e.Distance = 10000
+	e.DistanceUnit = "m"
+	rpm, ok := RPMMetric.Compute(e)
+	expected := (10000.0 / 6.2) / 60.0
+	if !ok || !approxEqual(rpm, expected) {
+		t.Errorf("expected rpm %f, got %f (ok=%v)", expected, rpm, ok)
+	}
+}
+
+func TestRPMMetric_NonCardio(t *testing.T) {
 	e := sampleExercise() // weights exercise
-	if rpm := calculateRPM(e); rpm != 0 {
-		t.Errorf("expected 0 for non-cardio, got %f", rpm)
+	if rpm, ok := RPMMetric.Compute(e); ok {
+		t.Errorf("expected no rpm for non-cardio, got %f", rpm)
 	}
 }
 
-func TestCalculateRPM_MissingTime(t *testing.T) {
+func TestRPMMetric_MissingTime(t *testing.T) {
 	e := sampleCardioExercise()
 	e.Time = 0
-	if rpm := calculateRPM(e); rpm != 0 {
-		t.Errorf("expected 0 when time is missing, got %f", rpm)
+	if rpm, ok := RPMMetric.Compute(e); ok {
+		t.Errorf("expected no rpm when time is missing, got %f", rpm)
 	}
 }
 
-func TestCalculateRPM_MissingDistance(t *testing.T) {
+func TestRPMMetric_MissingDistance(t *testing.T) {
 	e := sampleCardioExercise()
 	e.Distance = 0
-	if rpm := calculateRPM(e); rpm != 0 {
-		t.Errorf("expected 0 when distance is missing, got %f", rpm)
+	if rpm, ok := RPMMetric.Compute(e); ok {
+		t.Errorf("expected no rpm when distance is missing, got %f", rpm)
 	}
 }
 
-func TestCalculateRPM_UnsupportedUnit(t *testing.T) {
+func TestRPMMetric_Calories(t *testing.T) {
 	e := sampleCardioExercise()
-	e.DistanceUnit = "meters"
-	if rpm := calculateRPM(e); rpm != 0 {
-		t.Errorf("expected 0 for unsupported distance unit, got %f", rpm)
+	e.DistanceUnit = "cal"
+	if rpm, ok := RPMMetric.Compute(e); ok {
+		t.Errorf("expected no rpm for calories, got %f", rpm)
 	}
 }
 
 func TestCreateExercise_StoreRPM(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	e := sampleCardioExercise()
 	if err := svc.CreateExercise("user-1", e, true); err != nil {
@@ -258,8 +354,21 @@ func TestCreateExercise_StoreRPM(t *testing.T) {
 	}
 }
 
+func TestCreateExercise_StoreRPM_Rower(t *testing.T) {
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
+
+	e := sampleCardioExercise()
+	e.Name = "Rowing"
+	if err := svc.CreateExercise("user-1", e, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.RPM != 0 {
+		t.Errorf("expected no RPM for a rower, got %f", e.RPM)
+	}
+}
+
 func TestCreateExercise_StoreRPM_False(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	e := sampleCardioExercise()
 	if err := svc.CreateExercise("user-1", e, false); err != nil {
@@ -271,7 +380,7 @@ func TestCreateExercise_StoreRPM_False(t *testing.T) {
 }
 
 func TestUpdateExercise_StoreRPM(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	e := sampleCardioExercise()
 	if err := svc.UpdateExercise("user-1", "ex-2", e, true); err != nil {
@@ -285,7 +394,7 @@ func TestUpdateExercise_StoreRPM(t *testing.T) {
 // DeleteExercise
 
 func TestDeleteExercise_Success(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{})
+	svc := NewExerciseService(&mockExerciseRepo{}, nil, nil)
 
 	if err := svc.DeleteExercise("user-1", "ex-1"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -293,7 +402,7 @@ func TestDeleteExercise_Success(t *testing.T) {
 }
 
 func TestDeleteExercise_RepoError(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{err: models.ErrExerciseNotFound})
+	svc := NewExerciseService(&mockExerciseRepo{err: models.ErrExerciseNotFound}, nil, nil)
 
 	if err := svc.DeleteExercise("user-1", "missing"); err == nil {
 		t.Error("expected error, got nil")
@@ -304,7 +413,7 @@ func TestDeleteExercise_RepoError(t *testing.T) {
 
 func TestListExercisesByName_Success(t *testing.T) {
 	exercises := []*models.Exercise{sampleExercise()}
-	svc := NewExerciseService(&mockExerciseRepo{exercises: exercises})
+	svc := NewExerciseService(&mockExerciseRepo{exercises: exercises}, nil, nil)
 
 	got, err := svc.ListExercisesByName("user-1", "Bench Press")
 	if err != nil {
@@ -316,7 +425,7 @@ func TestListExercisesByName_Success(t *testing.T) {
 }
 
 func TestListExercisesByName_RepoError(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{err: errors.New("db error")})
+	svc := NewExerciseService(&mockExerciseRepo{err: errors.New("db error")}, nil, nil)
 
 	_, err := svc.ListExercisesByName("user-1", "Squat")
 	if err == nil {
@@ -324,25 +433,59 @@ func TestListExercisesByName_RepoError(t *testing.T) {
 	}
 }
 
+// SearchExercisesByName
+
+func TestSearchExercisesByName_Prefix(t *testing.T) {
+	repo := &mockExerciseRepo{exercises: []*models.Exercise{sampleExercise()}}
+	svc := NewExerciseService(repo, nil, nil)
+
+	got, err := svc.SearchExercisesByName("user-1", "Ben", repository.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Items) != 1 {
+		t.Errorf("expected 1 result, got %d", len(got.Items))
+	}
+	if repo.prefix != "Ben" {
+		t.Errorf("expected prefix query for %q, got %q", "Ben", repo.prefix)
+	}
+}
+
+func TestSearchExercisesByName_BlankPrefixListsAll(t *testing.T) {
+	repo := &mockExerciseRepo{exercises: []*models.Exercise{sampleExercise()}}
+	svc := NewExerciseService(repo, nil, nil)
+
+	got, err := svc.SearchExercisesByName("user-1", "  ", repository.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Items) != 1 {
+		t.Errorf("expected 1 result, got %d", len(got.Items))
+	}
+	if repo.prefix != "" {
+		t.Errorf("expected no prefix query, got %q", repo.prefix)
+	}
+}
+
 // ListExercisesByType
 
 func TestListExercisesByType_Success(t *testing.T) {
 	exercises := []*models.Exercise{sampleExercise()}
-	svc := NewExerciseService(&mockExerciseRepo{exercises: exercises})
+	svc := NewExerciseService(&mockExerciseRepo{exercises: exercises}, nil, nil)
 
-	got, err := svc.ListExercisesByType("user-1", "strength")
+	got, err := svc.ListExercisesByType("user-1", "weights", repository.ListOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(got) != 1 {
-		t.Errorf("expected 1 result, got %d", len(got))
+	if len(got.Items) != 1 {
+		t.Errorf("expected 1 result, got %d", len(got.Items))
 	}
 }
 
 func TestListExercisesByType_RepoError(t *testing.T) {
-	svc := NewExerciseService(&mockExerciseRepo{err: errors.New("db error")})
+	svc := NewExerciseService(&mockExerciseRepo{err: errors.New("db error")}, nil, nil)
 
-	_, err := svc.ListExercisesByType("user-1", "cardio")
+	_, err := svc.ListExercisesByType("user-1", "cardio", repository.ListOptions{})
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
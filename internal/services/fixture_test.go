@@ -0,0 +1,44 @@
+package services
+
+import "gym-tracker-api/internal/repository/memory"
+
+// fixture wires the services to fresh in-memory repositories the way
+// cmd/api/main.go does, for tests that exercise a service together with the
+// ones it builds on.
+type fixture struct {
+	workouts     *memory.WorkoutRepository
+	exercises    *memory.ExerciseRepository
+	templates    *memory.TemplateRepository
+	programs     *memory.ProgramRepository
+	records      *memory.RecordRepository
+	measurements *memory.MeasurementRepository
+
+	workoutSvc  WorkoutService
+	recordSvc   RecordService
+	exerciseSvc ExerciseService // tracks personal records through recordSvc
+	plannedSvc  ExerciseService // creates template and program exercises without records
+	templateSvc TemplateService
+	programSvc  ProgramService
+	statsSvc    StatsService
+	cardioSvc   CardioService
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		workouts:     memory.NewWorkoutRepository(),
+		exercises:    memory.NewExerciseRepository(),
+		templates:    memory.NewTemplateRepository(),
+		programs:     memory.NewProgramRepository(),
+		records:      memory.NewRecordRepository(),
+		measurements: memory.NewMeasurementRepository(),
+	}
+	f.workoutSvc = NewWorkoutService(f.workouts, f.exercises, nil, nil)
+	f.recordSvc = NewRecordService(f.records)
+	f.exerciseSvc = NewExerciseService(f.exercises, f.recordSvc, nil)
+	f.plannedSvc = NewExerciseService(f.exercises, nil, nil)
+	f.templateSvc = NewTemplateService(f.templates, f.workoutSvc, f.plannedSvc)
+	f.programSvc = NewProgramService(f.programs, f.templates, f.workoutSvc, f.plannedSvc)
+	f.statsSvc = NewStatsService(f.workouts, f.exercises, f.measurements)
+	f.cardioSvc = NewCardioService(f.workouts, f.exercises, DefaultMachineMetrics())
+	return f
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"math"
+	"strings"
+
+	"gym-tracker-api/internal/models"
+)
+
+// MachineMetric derives a machine-specific figure from one round of a cardio
+// exercise. Compute returns false when the exercise lacks the data it needs.
+type MachineMetric struct {
+	Name          string
+	Unit          string
+	LowerIsBetter bool
+	Compute       func(exercise *models.Exercise) (float64, bool)
+}
+
+// MachineMetrics picks a MachineMetric by exercise name. Names are
+// normalized and matched on whole words, so "Upright row" is not a rower and
+// "Skipping" is not a ski erg. An exact name registration wins over the
+// keywords, which are tried first registration first; names that match
+// nothing use the fallback.
+type MachineMetrics struct {
+	names    map[string]MachineMetric
+	keywords [][]string
+	metrics  []MachineMetric
+	fallback MachineMetric
+}
+
+// NewMachineMetrics returns a registry that falls back to fallback.
+func NewMachineMetrics(fallback MachineMetric) *MachineMetrics {
+	return &MachineMetrics{names: make(map[string]MachineMetric), fallback: fallback}
+}
+
+// DefaultMachineMetrics knows rowers and ski ergs; everything else is
+// treated as a bike and reports RPM. A bare "Row" is the rower, but "row" is
+// not a keyword since it also ends the names of weighted rows.
+func DefaultMachineMetrics() *MachineMetrics {
+	m := NewMachineMetrics(RPMMetric)
+	m.RegisterName("row", RowerSplitMetric)
+	for _, keyword := range []string{"rower", "rowing", "row erg", "rowerg"} {
+		m.Register(keyword, RowerSplitMetric)
+	}
+	for _, keyword := range []string{"ski erg", "skierg"} {
+		m.Register(keyword, SkiErgWattsMetric)
+	}
+	return m
+}
+
+// Register uses metric for exercise names containing keyword's words in
+// order, each as a whole word.
+func (m *MachineMetrics) Register(keyword string, metric MachineMetric) {
+	m.keywords = append(m.keywords, strings.Fields(models.NormalizeName(keyword)))
+	m.metrics = append(m.metrics, metric)
+}
+
+// RegisterName uses metric for exercises named exactly name, ignoring case
+// and spacing.
+func (m *MachineMetrics) RegisterName(name string, metric MachineMetric) {
+	m.names[models.NormalizeName(name)] = metric
+}
+
+// For returns the metric for an exercise name.
+func (m *MachineMetrics) For(exerciseName string) MachineMetric {
+	name := models.NormalizeName(exerciseName)
+	if metric, ok := m.names[name]; ok {
+		return metric
+	}
+	words := strings.Fields(name)
+	for i, keyword := range m.keywords {
+		if containsWords(words, keyword) {
+			return m.metrics[i]
+		}
+	}
+	return m.fallback
+}
+
+// containsWords reports whether phrase appears in words as a consecutive run.
+func containsWords(words, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for start := 0; start+len(phrase) <= len(words); start++ {
+		match := true
+		for i, word := range phrase {
+			if words[start+i] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// RPMMetric is the bike cadence of a cardio exercise, counting 6.2 metres
+// per revolution.
+var RPMMetric = MachineMetric{
+	Name: "rpm",
+	Unit: "rpm",
+	Compute: func(e *models.Exercise) (float64, bool) {
+		if e.ExerciseType != models.ExerciseTypeCardio || e.Time <= 0 {
+			return 0, false
+		}
+		meters, ok := models.DistanceInMeters(e.Distance, e.DistanceUnit)
+		if !ok || meters <= 0 {
+			return 0, false
+		}
+		return meters / 6.2 / (float64(e.Time) / 60), true
+	},
+}
+
+// RowerSplitMetric is the average time per 500 m, in seconds.
+var RowerSplitMetric = MachineMetric{
+	Name:          "split_500m",
+	Unit:          "s/500m",
+	LowerIsBetter: true,
+	Compute: func(e *models.Exercise) (float64, bool) {
+		meters, ok := models.DistanceInMeters(e.Distance, e.DistanceUnit)
+		if !ok || meters <= 0 || e.Time <= 0 {
+			return 0, false
+		}
+		return float64(e.Time) / (meters / 500), true
+	},
+}
+
+// SkiErgWattsMetric is the average power, using Concept2's conversions:
+// watts = 2.80 / pace³ with pace in seconds per metre, or, for rounds logged
+// in calories, calories per hour = 4 × watts + 300.
+var SkiErgWattsMetric = MachineMetric{
+	Name: "watts",
+	Unit: "W",
+	Compute: func(e *models.Exercise) (float64, bool) {
+		if e.Time <= 0 || e.Distance <= 0 {
+			return 0, false
+		}
+		if models.NormalizeDistanceUnit(e.DistanceUnit) == models.DistanceCalories {
+			watts := (e.Distance*3600/float64(e.Time) - 300) / 4
+			return watts, watts > 0
+		}
+		meters, ok := models.DistanceInMeters(e.Distance, e.DistanceUnit)
+		if !ok {
+			return 0, false
+		}
+		return 2.80 / math.Pow(float64(e.Time)/meters, 3), true
+	},
+}
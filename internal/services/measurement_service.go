@@ -0,0 +1,68 @@
+package services
+
+import (
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+type MeasurementService interface {
+	GetMeasurement(userID, measurementID string) (*models.Measurement, error)
+	// ListMeasurements returns the user's measurements within dateRange,
+	// ordered by date.
+	ListMeasurements(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Measurement], error)
+	// CreateMeasurement stores a new measurement, dated today in the user's
+	// timezone when no date is given.
+	CreateMeasurement(measurement *models.Measurement) error
+	UpdateMeasurement(userID, measurementID string, measurement *models.Measurement) error
+	DeleteMeasurement(userID, measurementID string) error
+}
+
+type measurementService struct {
+	repo     repository.MeasurementRepository
+	calendar Calendar
+}
+
+// NewMeasurementService returns a MeasurementService. calendar supplies the
+// default date; nil means UTC.
+func NewMeasurementService(repo repository.MeasurementRepository, calendar Calendar) MeasurementService {
+	if calendar == nil {
+		calendar = utcCalendar{}
+	}
+	return &measurementService{
+		repo:     repo,
+		calendar: calendar,
+	}
+}
+
+func (s *measurementService) GetMeasurement(userID, measurementID string) (*models.Measurement, error) {
+	return s.repo.GetByID(userID, measurementID)
+}
+
+func (s *measurementService) ListMeasurements(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Measurement], error) {
+	return s.repo.ListByDateRange(userID, dateRange, pageOptions(opts))
+}
+
+func (s *measurementService) CreateMeasurement(measurement *models.Measurement) error {
+	if measurement.Date == "" {
+		measurement.Date = s.calendar.Today(measurement.UserID)
+	}
+	measurement.Normalize()
+	if err := measurement.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Create(measurement)
+}
+
+func (s *measurementService) UpdateMeasurement(userID, measurementID string, measurement *models.Measurement) error {
+	measurement.UserID = userID
+	measurement.MeasurementID = measurementID
+	measurement.Normalize()
+	if err := measurement.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Update(measurement)
+}
+
+func (s *measurementService) DeleteMeasurement(userID, measurementID string) error {
+	return s.repo.Delete(userID, measurementID)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/repository/memory"
+)
+
+type fixedCalendar string
+
+func (c fixedCalendar) Today(string) string { return string(c) }
+
+func TestCreateMeasurement_DefaultsDateAndNormalizesUnits(t *testing.T) {
+	repo := memory.NewMeasurementRepository()
+	svc := NewMeasurementService(repo, fixedCalendar("2024-03-05"))
+
+	m := &models.Measurement{UserID: "user-1", MeasurementID: "m-1", Bodyweight: 180, WeightUnit: "lbs", Waist: 33, LengthUnit: "inches"}
+	if err := svc.CreateMeasurement(m); err != nil {
+		t.Fatalf("CreateMeasurement: %v", err)
+	}
+	got, err := repo.GetByID("user-1", "m-1")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Date != "2024-03-05" || got.WeightUnit != models.UnitLb || got.LengthUnit != models.LengthIn {
+		t.Errorf("stored = %+v, want date 2024-03-05 in lb and in", got)
+	}
+}
+
+func TestCreateMeasurement_RejectsInvalid(t *testing.T) {
+	repo := memory.NewMeasurementRepository()
+	svc := NewMeasurementService(repo, nil)
+
+	m := &models.Measurement{UserID: "user-1", MeasurementID: "m-1", Date: "2024-03-05", Bodyweight: 80, WeightUnit: "stone"}
+	if err := svc.CreateMeasurement(m); !errors.Is(err, models.ErrInvalidMeasurement) {
+		t.Errorf("expected ErrInvalidMeasurement, got %v", err)
+	}
+	if _, err := repo.GetByID("user-1", "m-1"); !errors.Is(err, models.ErrMeasurementNotFound) {
+		t.Errorf("invalid measurement was stored: %v", err)
+	}
+}
+
+func TestUpdateMeasurement_KeepsIdentity(t *testing.T) {
+	repo := memory.NewMeasurementRepository()
+	svc := NewMeasurementService(repo, nil)
+	if err := svc.CreateMeasurement(&models.Measurement{UserID: "user-1", MeasurementID: "m-1", Date: "2024-03-05", BodyFatPercent: 18}); err != nil {
+		t.Fatalf("CreateMeasurement: %v", err)
+	}
+
+	update := &models.Measurement{UserID: "user-2", MeasurementID: "other", Date: "2024-03-06", BodyFatPercent: 17.5}
+	if err := svc.UpdateMeasurement("user-1", "m-1", update); err != nil {
+		t.Fatalf("UpdateMeasurement: %v", err)
+	}
+	page, err := svc.ListMeasurements("user-1", repository.DateRange{From: "2024-03-06"}, repository.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListMeasurements: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].MeasurementID != "m-1" || page.Items[0].BodyFatPercent != 17.5 {
+		t.Errorf("ListMeasurements = %+v, want the updated m-1", page.Items)
+	}
+}
@@ -0,0 +1,19 @@
+package services
+
+import "gym-tracker-api/internal/repository"
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+// pageOptions applies the default page size and caps client supplied limits.
+func pageOptions(opts repository.ListOptions) repository.ListOptions {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultPageSize
+	}
+	if opts.Limit > maxPageSize {
+		opts.Limit = maxPageSize
+	}
+	return opts
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"log"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/utils"
+)
+
+type ProfileService interface {
+	// GetProfile returns the user's profile, creating a default one if the
+	// user has none yet.
+	GetProfile(userID string) (*models.UserProfile, error)
+	// UpdateProfile replaces the user's settings. Empty units, timezone and
+	// week start fall back to their defaults.
+	UpdateProfile(userID string, profile *models.UserProfile) error
+	// EnsureProfile creates a default profile if the user has none.
+	EnsureProfile(userID string) error
+	// GetUnits returns the user's preferred units, or the defaults when the
+	// user has no profile yet.
+	GetUnits(userID string) (models.UnitPreferences, error)
+	// UpdateUnits stores the user's preferred units, creating the profile if
+	// needed, and returns them normalized.
+	UpdateUnits(userID string, units models.UnitPreferences) (models.UnitPreferences, error)
+	Calendar
+}
+
+// Calendar reports the current date for a user.
+type Calendar interface {
+	// Today returns the user's current date as YYYY-MM-DD.
+	Today(userID string) string
+}
+
+// utcCalendar is the Calendar for users without a timezone.
+type utcCalendar struct{}
+
+func (utcCalendar) Today(string) string {
+	return utils.GetCurrentTime().Format(models.DateLayout)
+}
+
+type profileService struct {
+	repo repository.ProfileRepository
+}
+
+func NewProfileService(repo repository.ProfileRepository) ProfileService {
+	return &profileService{
+		repo: repo,
+	}
+}
+
+func (s *profileService) GetProfile(userID string) (*models.UserProfile, error) {
+	profile, err := s.repo.GetByUserID(userID)
+	if errors.Is(err, models.ErrProfileNotFound) {
+		return s.create(userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Profiles stored before a setting existed read back with its default.
+	profile.Normalize()
+	return profile, nil
+}
+
+func (s *profileService) EnsureProfile(userID string) error {
+	_, err := s.repo.GetByUserID(userID)
+	if errors.Is(err, models.ErrProfileNotFound) {
+		_, err = s.create(userID)
+	}
+	return err
+}
+
+// create stores a default profile. If a concurrent request created one first,
+// that profile is returned instead.
+func (s *profileService) create(userID string) (*models.UserProfile, error) {
+	profile := models.NewUserProfile(userID)
+	profile.CreatedAt = utils.GetCurrentTime()
+	profile.UpdatedAt = profile.CreatedAt
+	err := s.repo.Create(profile)
+	if errors.Is(err, models.ErrProfileAlreadyExists) {
+		return s.repo.GetByUserID(userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *profileService) UpdateProfile(userID string, profile *models.UserProfile) error {
+	profile.UserID = userID
+	profile.Normalize()
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	return s.save(profile)
+}
+
+// save stores profile, creating it if the user has none and otherwise keeping
+// the stored CreatedAt.
+func (s *profileService) save(profile *models.UserProfile) error {
+	existing, err := s.repo.GetByUserID(profile.UserID)
+	if err != nil && !errors.Is(err, models.ErrProfileNotFound) {
+		return err
+	}
+	profile.UpdatedAt = utils.GetCurrentTime()
+	if existing == nil {
+		profile.CreatedAt = profile.UpdatedAt
+		return s.repo.Create(profile)
+	}
+	profile.CreatedAt = existing.CreatedAt
+	return s.repo.Update(profile)
+}
+
+func (s *profileService) GetUnits(userID string) (models.UnitPreferences, error) {
+	profile, err := s.repo.GetByUserID(userID)
+	if errors.Is(err, models.ErrProfileNotFound) {
+		return models.DefaultUnits, nil
+	}
+	if err != nil {
+		return models.UnitPreferences{}, err
+	}
+	return profile.Units, nil
+}
+
+func (s *profileService) UpdateUnits(userID string, units models.UnitPreferences) (models.UnitPreferences, error) {
+	profile, err := s.repo.GetByUserID(userID)
+	if errors.Is(err, models.ErrProfileNotFound) {
+		profile = models.NewUserProfile(userID)
+	} else if err != nil {
+		return models.UnitPreferences{}, err
+	}
+
+	profile.Units = units
+	profile.Normalize()
+	if err := profile.Validate(); err != nil {
+		return models.UnitPreferences{}, err
+	}
+	if err := s.save(profile); err != nil {
+		return models.UnitPreferences{}, err
+	}
+	return profile.Units, nil
+}
+
+// Today uses the profile's timezone, falling back to UTC when the profile
+// cannot be read.
+func (s *profileService) Today(userID string) string {
+	profile, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		if !errors.Is(err, models.ErrProfileNotFound) {
+			log.Printf("failed to read profile for %s, using UTC: %v", userID, err)
+		}
+		return utcCalendar{}.Today(userID)
+	}
+	return profile.Today(utils.GetCurrentTime())
+}
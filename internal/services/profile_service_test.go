@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository/memory"
+)
+
+func TestGetUnits_DefaultsWithoutProfile(t *testing.T) {
+	svc := NewProfileService(memory.NewProfileRepository())
+
+	units, err := svc.GetUnits("user-1")
+	if err != nil {
+		t.Fatalf("GetUnits: %v", err)
+	}
+	if units != models.DefaultUnits {
+		t.Errorf("units = %+v, want defaults %+v", units, models.DefaultUnits)
+	}
+}
+
+func TestUpdateUnits_CreatesThenUpdatesProfile(t *testing.T) {
+	repo := memory.NewProfileRepository()
+	svc := NewProfileService(repo)
+
+	units, err := svc.UpdateUnits("user-1", models.UnitPreferences{Weight: "Pounds", Distance: "miles"})
+	if err != nil {
+		t.Fatalf("UpdateUnits: %v", err)
+	}
+	want := models.UnitPreferences{Weight: models.UnitLb, Distance: models.DistanceMiles}
+	if units != want {
+		t.Errorf("units = %+v, want %+v", units, want)
+	}
+	created, err := repo.GetByUserID("user-1")
+	if err != nil {
+		t.Fatalf("profile was not created: %v", err)
+	}
+
+	if _, err := svc.UpdateUnits("user-1", models.DefaultUnits); err != nil {
+		t.Fatalf("second UpdateUnits: %v", err)
+	}
+	updated, _ := repo.GetByUserID("user-1")
+	if updated.Units != models.DefaultUnits || !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("after update = %+v, want default units and CreatedAt %v", updated, created.CreatedAt)
+	}
+}
+
+func TestUpdateUnits_RejectsUnknownUnits(t *testing.T) {
+	repo := memory.NewProfileRepository()
+	svc := NewProfileService(repo)
+
+	if _, err := svc.UpdateUnits("user-1", models.UnitPreferences{Weight: "stone", Distance: "m"}); !errors.Is(err, models.ErrInvalidProfile) {
+		t.Errorf("expected ErrInvalidProfile, got %v", err)
+	}
+	if _, err := repo.GetByUserID("user-1"); !errors.Is(err, models.ErrProfileNotFound) {
+		t.Errorf("invalid units created a profile: %v", err)
+	}
+}
+
+func TestGetProfile_CreatesDefaultProfile(t *testing.T) {
+	repo := memory.NewProfileRepository()
+	svc := NewProfileService(repo)
+
+	profile, err := svc.GetProfile("user-1")
+	if err != nil {
+		t.Fatalf("GetProfile: %v", err)
+	}
+	if profile.Timezone != models.DefaultTimezone || profile.DefaultRestSeconds != models.DefaultRestSeconds || profile.CreatedAt.IsZero() {
+		t.Errorf("profile = %+v, want defaults", profile)
+	}
+	if _, err := repo.GetByUserID("user-1"); err != nil {
+		t.Errorf("profile was not stored: %v", err)
+	}
+}
+
+func TestUpdateProfile_KeepsCreatedAt(t *testing.T) {
+	svc := NewProfileService(memory.NewProfileRepository())
+	created, _ := svc.GetProfile("user-1")
+
+	update := &models.UserProfile{
+		UserID:      "someone-else",
+		DisplayName: "Sam",
+		Timezone:    "Europe/Berlin",
+		Bodyweight:  []models.BodyweightEntry{{Date: "2024-01-01", Weight: 80, Unit: "kg"}},
+	}
+	if err := svc.UpdateProfile("user-1", update); err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	if update.UserID != "user-1" || !update.CreatedAt.Equal(created.CreatedAt) || update.Units != models.DefaultUnits {
+		t.Errorf("updated profile = %+v", update)
+	}
+
+	got, _ := svc.GetProfile("user-1")
+	if got.DisplayName != "Sam" || got.Timezone != "Europe/Berlin" || len(got.Bodyweight) != 1 {
+		t.Errorf("stored profile = %+v", got)
+	}
+}
+
+func TestToday_UsesProfileTimezone(t *testing.T) {
+	svc := NewProfileService(memory.NewProfileRepository())
+	if err := svc.UpdateProfile("user-1", &models.UserProfile{Timezone: "Pacific/Kiritimati"}); err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+
+	loc, _ := time.LoadLocation("Pacific/Kiritimati")
+	if got, want := svc.Today("user-1"), time.Now().In(loc).Format(models.DateLayout); got != want {
+		t.Errorf("Today = %s, want %s", got, want)
+	}
+	if got, want := svc.Today("no-profile"), time.Now().UTC().Format(models.DateLayout); got != want {
+		t.Errorf("Today without a profile = %s, want UTC %s", got, want)
+	}
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/utils"
+)
+
+type ProgramService interface {
+	GetProgram(userID, programID string) (*models.Program, error)
+	GetPrograms(userID string, opts repository.ListOptions) (*repository.Page[*models.Program], error)
+	CreateProgram(program *models.Program) error
+	UpdateProgram(userID, programID string, program *models.Program) error
+	DeleteProgram(userID, programID string) error
+	Enroll(userID, programID, startDate string) (*models.Program, error)
+	Unenroll(userID, programID string) (*models.Program, error)
+	SessionOn(userID, programID, date string) (*models.ScheduledSession, error)
+	Progress(userID, programID, date string) (*models.ProgramProgress, error)
+	StartSession(userID, programID, date string) (*models.WorkoutDetail, error)
+}
+
+type programService struct {
+	repo      repository.ProgramRepository
+	templates repository.TemplateRepository
+	workouts  WorkoutService
+	exercises ExerciseService
+}
+
+func NewProgramService(repo repository.ProgramRepository, templates repository.TemplateRepository, workouts WorkoutService, exercises ExerciseService) ProgramService {
+	return &programService{
+		repo:      repo,
+		templates: templates,
+		workouts:  workouts,
+		exercises: exercises,
+	}
+}
+
+func (s *programService) GetProgram(userID, programID string) (*models.Program, error) {
+	return s.repo.GetByID(userID, programID)
+}
+
+func (s *programService) GetPrograms(userID string, opts repository.ListOptions) (*repository.Page[*models.Program], error) {
+	return s.repo.ListByUserID(userID, pageOptions(opts))
+}
+
+// CreateProgram stores a new program. Enrolment is a separate step, so any
+// enrollment in the request is ignored.
+func (s *programService) CreateProgram(program *models.Program) error {
+	program.Enrollment = nil
+	if err := s.validate(program); err != nil {
+		return err
+	}
+	return s.repo.Create(program)
+}
+
+// UpdateProgram replaces the program's name, weeks and progression. The
+// enrolment and its started sessions are kept.
+func (s *programService) UpdateProgram(userID, programID string, program *models.Program) error {
+	existing, err := s.repo.GetByID(userID, programID)
+	if err != nil {
+		return err
+	}
+	program.UserID = userID
+	program.ProgramID = programID
+	program.CreatedAt = existing.CreatedAt
+	program.Enrollment = existing.Enrollment
+	program.Version = existing.Version
+	if err := s.validate(program); err != nil {
+		return err
+	}
+	return s.repo.Update(program)
+}
+
+func (s *programService) DeleteProgram(userID, programID string) error {
+	return s.repo.Delete(userID, programID)
+}
+
+// validate checks the program itself and that every template it schedules exists.
+func (s *programService) validate(program *models.Program) error {
+	if err := program.Validate(); err != nil {
+		return err
+	}
+	checked := make(map[string]bool)
+	for i, week := range program.Weeks {
+		for j, day := range week.Days {
+			if checked[day.TemplateID] {
+				continue
+			}
+			_, err := s.templates.GetByID(program.UserID, day.TemplateID)
+			if errors.Is(err, models.ErrTemplateNotFound) {
+				field := "weeks[" + strconv.Itoa(i) + "].days[" + strconv.Itoa(j) + "].templateId"
+				return models.NewValidationError(models.ErrInvalidProgram, field, "template not found")
+			}
+			if err != nil {
+				return err
+			}
+			checked[day.TemplateID] = true
+		}
+	}
+	return nil
+}
+
+// Enroll starts the program on startDate (today if empty). Enrolling again
+// restarts the program and forgets previously started sessions.
+func (s *programService) Enroll(userID, programID, startDate string) (*models.Program, error) {
+	program, err := s.repo.GetByID(userID, programID)
+	if err != nil {
+		return nil, err
+	}
+	if startDate == "" {
+		startDate = s.workouts.Today(userID)
+	}
+	program.Enrollment = &models.Enrollment{
+		StartDate:  startDate,
+		EnrolledAt: utils.GetCurrentTime(),
+		Sessions:   []models.ProgramSession{},
+	}
+	if err := program.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(program); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+func (s *programService) Unenroll(userID, programID string) (*models.Program, error) {
+	program, err := s.repo.GetByID(userID, programID)
+	if err != nil {
+		return nil, err
+	}
+	if program.Enrollment == nil {
+		return nil, models.ErrNotEnrolled
+	}
+	program.Enrollment = nil
+	if err := s.repo.Update(program); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+// SessionOn returns the session scheduled on date (today if empty), with
+// target weights progressed for its week. Rest days and dates outside the
+// block return ErrNoSessionScheduled.
+func (s *programService) SessionOn(userID, programID, date string) (*models.ScheduledSession, error) {
+	program, err := s.repo.GetByID(userID, programID)
+	if err != nil {
+		return nil, err
+	}
+	return s.sessionOn(program, date)
+}
+
+func (s *programService) sessionOn(program *models.Program, date string) (*models.ScheduledSession, error) {
+	if program.Enrollment == nil {
+		return nil, models.ErrNotEnrolled
+	}
+	if date == "" {
+		date = s.workouts.Today(program.UserID)
+	}
+	on, err := sessionDate(date)
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(models.DateLayout, program.Enrollment.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := daysBetween(start, on)
+	if offset < 0 || offset >= 7*len(program.Weeks) {
+		return nil, models.ErrNoSessionScheduled
+	}
+	week, day := offset/7+1, offset%7+1
+	programWeek := program.Weeks[week-1]
+
+	for _, slot := range programWeek.Days {
+		if slot.Day != day {
+			continue
+		}
+		template, err := s.templates.GetByID(program.UserID, slot.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		session := &models.ScheduledSession{
+			Week:       week,
+			Day:        day,
+			Date:       on.Format(models.DateLayout),
+			Deload:     programWeek.Deload,
+			TemplateID: template.TemplateID,
+			Name:       template.Name,
+			Exercises:  make([]models.ExercisePrescription, len(template.Exercises)),
+		}
+		for i, p := range template.Exercises {
+			session.Exercises[i] = program.Progression.Apply(p, week, programWeek.Deload)
+		}
+		if started, ok := program.Session(week, day); ok {
+			session.WorkoutID = started.WorkoutID
+		}
+		return session, nil
+	}
+	return nil, models.ErrNoSessionScheduled
+}
+
+// StartSession creates the workout for the session scheduled on date (today
+// if empty), with the progressed targets as its sets, and records it against
+// the enrolment. Each session can be started once; if the program changes
+// while the workout is being created, the workout is discarded and
+// ErrProgramConflict returned.
+func (s *programService) StartSession(userID, programID, date string) (*models.WorkoutDetail, error) {
+	program, err := s.repo.GetByID(userID, programID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.sessionOn(program, date)
+	if err != nil {
+		return nil, err
+	}
+	if session.WorkoutID != "" {
+		return nil, models.ErrSessionAlreadyStarted
+	}
+
+	detail, err := startWorkout(s.workouts, s.exercises, userID, session.Name, session.Date, session.Exercises)
+	if err != nil {
+		return nil, err
+	}
+
+	program.Enrollment.Sessions = append(program.Enrollment.Sessions, models.ProgramSession{
+		Week:      session.Week,
+		Day:       session.Day,
+		Date:      session.Date,
+		WorkoutID: detail.WorkoutID,
+	})
+	if err := s.repo.Update(program); err != nil {
+		discardWorkout(s.workouts, s.exercises, detail)
+		return nil, err
+	}
+	return detail, nil
+}
+
+// Progress reports the enrolment's position in the block on date (today if
+// empty) and how many scheduled sessions have been completed.
+func (s *programService) Progress(userID, programID, date string) (*models.ProgramProgress, error) {
+	program, err := s.repo.GetByID(userID, programID)
+	if err != nil {
+		return nil, err
+	}
+	if program.Enrollment == nil {
+		return nil, models.ErrNotEnrolled
+	}
+	if date == "" {
+		date = s.workouts.Today(program.UserID)
+	}
+	on, err := sessionDate(date)
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(models.DateLayout, program.Enrollment.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	totalDays := 7 * len(program.Weeks)
+	progress := &models.ProgramProgress{
+		StartDate:     program.Enrollment.StartDate,
+		EndDate:       start.AddDate(0, 0, totalDays-1).Format(models.DateLayout),
+		TotalWeeks:    len(program.Weeks),
+		SessionsTotal: program.SessionCount(),
+	}
+	if progress.SessionsCompleted, err = s.completedSessions(program); err != nil {
+		return nil, err
+	}
+	switch offset := daysBetween(start, on); {
+	case offset < 0:
+		progress.Status = models.ProgramStatusUpcoming
+	case offset >= totalDays:
+		progress.Status = models.ProgramStatusFinished
+		progress.CurrentWeek = len(program.Weeks)
+	default:
+		progress.Status = models.ProgramStatusActive
+		progress.CurrentWeek = offset/7 + 1
+	}
+	if progress.SessionsTotal > 0 {
+		percent := 100 * float64(progress.SessionsCompleted) / float64(progress.SessionsTotal)
+		progress.PercentComplete = math.Round(percent*10) / 10
+	}
+	return progress, nil
+}
+
+// completedSessions counts the enrolment's sessions whose workout has been
+// finished. Sessions whose workout was deleted count as not done.
+func (s *programService) completedSessions(program *models.Program) (int, error) {
+	completed := 0
+	for _, session := range program.Enrollment.Sessions {
+		workout, err := s.workouts.GetWorkout(program.UserID, session.WorkoutID)
+		if errors.Is(err, models.ErrWorkoutNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if workout.Status == models.WorkoutCompleted {
+			completed++
+		}
+	}
+	return completed, nil
+}
+
+// sessionDate parses a YYYY-MM-DD date.
+func sessionDate(date string) (time.Time, error) {
+	on, err := time.Parse(models.DateLayout, date)
+	if err != nil {
+		return time.Time{}, models.NewValidationError(models.ErrInvalidProgram, "date", "must be YYYY-MM-DD")
+	}
+	return on, nil
+}
+
+// daysBetween counts whole calendar days from a to b; both must be UTC midnights.
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/repository/memory"
+)
+
+// newProgramFixture stores a bench template and a four-week program that runs
+// it on days 1 and 3, deloading in week 4.
+func newProgramFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := newFixture()
+
+	bench := &models.Template{
+		UserID:     "user-1",
+		TemplateID: "bench",
+		Name:       "Bench Day",
+		Exercises: []models.ExercisePrescription{
+			{Name: "Bench Press", ExerciseType: models.ExerciseTypeWeights, TargetSets: 3, TargetReps: 5, TargetWeight: 80, Unit: "kg"},
+		},
+	}
+	if err := f.templates.Create(bench); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+
+	days := []models.ProgramDay{{Day: 1, TemplateID: "bench"}, {Day: 3, TemplateID: "bench"}}
+	program := &models.Program{
+		UserID:    "user-1",
+		ProgramID: "block",
+		Name:      "Bench Block",
+		Weeks: []models.ProgramWeek{
+			{Days: days}, {Days: days}, {Days: days}, {Days: days, Deload: true},
+		},
+		Progression: models.Progression{WeeklyIncrement: 2.5, DeloadPercent: 60},
+	}
+	if err := f.programSvc.CreateProgram(program); err != nil {
+		t.Fatalf("CreateProgram: %v", err)
+	}
+	return f
+}
+
+func TestCreateProgram_UnknownTemplate(t *testing.T) {
+	f := newProgramFixture(t)
+	program := &models.Program{
+		UserID:    "user-1",
+		ProgramID: "other",
+		Name:      "Other",
+		Weeks:     []models.ProgramWeek{{Days: []models.ProgramDay{{Day: 1, TemplateID: "missing"}}}},
+	}
+
+	var verr *models.ValidationError
+	if err := f.programSvc.CreateProgram(program); !errors.As(err, &verr) || verr.Fields[0].Field != "weeks[0].days[0].templateId" {
+		t.Errorf("expected a templateId validation error, got %v", err)
+	}
+}
+
+func TestSessionOn_NotEnrolled(t *testing.T) {
+	f := newProgramFixture(t)
+
+	if _, err := f.programSvc.SessionOn("user-1", "block", "2024-03-04"); !errors.Is(err, models.ErrNotEnrolled) {
+		t.Errorf("expected ErrNotEnrolled, got %v", err)
+	}
+}
+
+func TestSessionOn_AppliesProgression(t *testing.T) {
+	f := newProgramFixture(t)
+	if _, err := f.programSvc.Enroll("user-1", "block", "2024-03-04"); err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+
+	tests := []struct {
+		date   string
+		week   int
+		day    int
+		weight float64
+	}{
+		{"2024-03-04", 1, 1, 80},
+		{"2024-03-06", 1, 3, 80},
+		{"2024-03-11", 2, 1, 82.5},
+		{"2024-03-20", 3, 3, 85},
+		{"2024-03-25", 4, 1, 52.5},
+	}
+	for _, tt := range tests {
+		session, err := f.programSvc.SessionOn("user-1", "block", tt.date)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.date, err)
+		}
+		if session.Week != tt.week || session.Day != tt.day || session.Exercises[0].TargetWeight != tt.weight {
+			t.Errorf("%s: week %d day %d weight %v, want week %d day %d weight %v",
+				tt.date, session.Week, session.Day, session.Exercises[0].TargetWeight, tt.week, tt.day, tt.weight)
+		}
+	}
+
+	for _, date := range []string{"2024-03-05", "2024-03-03", "2024-04-01"} {
+		if _, err := f.programSvc.SessionOn("user-1", "block", date); !errors.Is(err, models.ErrNoSessionScheduled) {
+			t.Errorf("%s: expected ErrNoSessionScheduled, got %v", date, err)
+		}
+	}
+}
+
+func TestStartSession_CreatesProgressedWorkoutOnce(t *testing.T) {
+	f := newProgramFixture(t)
+	f.programSvc.Enroll("user-1", "block", "2024-03-04")
+
+	detail, err := f.programSvc.StartSession("user-1", "block", "2024-03-11")
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	if detail.Date != "2024-03-11" || detail.Name != "Bench Day" {
+		t.Errorf("unexpected workout %+v", detail.Workout)
+	}
+	sets := detail.Exercises[0].Sets
+	if len(sets) != 3 || sets[0].Weight != 82.5 || sets[0].Reps != 5 {
+		t.Errorf("expected 3x5 at 82.5, got %+v", sets)
+	}
+
+	session, _ := f.programSvc.SessionOn("user-1", "block", "2024-03-11")
+	if session.WorkoutID != detail.WorkoutID {
+		t.Errorf("session workoutId = %q, want %q", session.WorkoutID, detail.WorkoutID)
+	}
+	if _, err := f.programSvc.StartSession("user-1", "block", "2024-03-11"); !errors.Is(err, models.ErrSessionAlreadyStarted) {
+		t.Errorf("expected ErrSessionAlreadyStarted, got %v", err)
+	}
+}
+
+// racingProgramRepo runs race once just before the next Update, standing in
+// for a concurrent request.
+type racingProgramRepo struct {
+	*memory.ProgramRepository
+	race func()
+}
+
+func (r *racingProgramRepo) Update(program *models.Program) error {
+	if race := r.race; race != nil {
+		r.race = nil
+		race()
+	}
+	return r.ProgramRepository.Update(program)
+}
+
+func TestStartSession_ConcurrentStartConflicts(t *testing.T) {
+	f := newProgramFixture(t)
+	f.programSvc.Enroll("user-1", "block", "2024-03-04")
+	programs := &racingProgramRepo{ProgramRepository: f.programs}
+	svc := NewProgramService(programs, f.templates, f.workoutSvc, f.plannedSvc)
+
+	var winner *models.WorkoutDetail
+	programs.race = func() {
+		var err error
+		if winner, err = svc.StartSession("user-1", "block", "2024-03-04"); err != nil {
+			t.Fatalf("racing StartSession: %v", err)
+		}
+	}
+	if _, err := svc.StartSession("user-1", "block", "2024-03-04"); !errors.Is(err, models.ErrProgramConflict) {
+		t.Fatalf("expected ErrProgramConflict, got %v", err)
+	}
+
+	program, _ := f.programs.GetByID("user-1", "block")
+	if sessions := program.Enrollment.Sessions; len(sessions) != 1 || sessions[0].WorkoutID != winner.WorkoutID {
+		t.Errorf("sessions = %+v, want only the winner's", sessions)
+	}
+	workouts, _ := f.workouts.ListByUserID("user-1", repository.ListOptions{})
+	if len(workouts.Items) != 1 {
+		t.Errorf("%d workouts stored, want the loser's discarded", len(workouts.Items))
+	}
+	// The loser's workout was never shown to the user, so it must not
+	// surface in their trash either.
+	trashedWorkouts, _ := f.workouts.ListDeleted("user-1", repository.ListOptions{})
+	trashedExercises, _ := f.exercises.ListDeleted("user-1", repository.ListOptions{})
+	if len(trashedWorkouts.Items) != 0 || len(trashedExercises.Items) != 0 {
+		t.Errorf("trash holds %d workouts and %d exercises, want it empty", len(trashedWorkouts.Items), len(trashedExercises.Items))
+	}
+}
+
+func TestProgress(t *testing.T) {
+	f := newProgramFixture(t)
+	f.programSvc.Enroll("user-1", "block", "2024-03-04")
+	done, _ := f.programSvc.StartSession("user-1", "block", "2024-03-04")
+	f.programSvc.StartSession("user-1", "block", "2024-03-06")
+	abandoned, _ := f.programSvc.StartSession("user-1", "block", "2024-03-11")
+	// Only the first session is finished; started or abandoned ones do not count.
+	f.workoutSvc.StartWorkout("user-1", done.WorkoutID)
+	if _, err := f.workoutSvc.FinishWorkout("user-1", done.WorkoutID); err != nil {
+		t.Fatalf("FinishWorkout: %v", err)
+	}
+	f.workoutSvc.StartWorkout("user-1", abandoned.WorkoutID)
+	f.workoutSvc.AbandonWorkout("user-1", abandoned.WorkoutID)
+
+	tests := []struct {
+		date   string
+		status string
+		week   int
+	}{
+		{"2024-03-01", models.ProgramStatusUpcoming, 0},
+		{"2024-03-13", models.ProgramStatusActive, 2},
+		{"2024-03-31", models.ProgramStatusActive, 4},
+		{"2024-04-01", models.ProgramStatusFinished, 4},
+	}
+	for _, tt := range tests {
+		progress, err := f.programSvc.Progress("user-1", "block", tt.date)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.date, err)
+		}
+		if progress.Status != tt.status || progress.CurrentWeek != tt.week {
+			t.Errorf("%s: status %s week %d, want %s week %d", tt.date, progress.Status, progress.CurrentWeek, tt.status, tt.week)
+		}
+		if progress.SessionsCompleted != 1 || progress.SessionsTotal != 8 || progress.PercentComplete != 12.5 {
+			t.Errorf("%s: unexpected session counts %+v", tt.date, progress)
+		}
+		if progress.EndDate != "2024-03-31" {
+			t.Errorf("EndDate = %s, want 2024-03-31", progress.EndDate)
+		}
+	}
+}
+
+func TestUpdateProgram_KeepsEnrollment(t *testing.T) {
+	f := newProgramFixture(t)
+	f.programSvc.Enroll("user-1", "block", "2024-03-04")
+
+	update := &models.Program{
+		Name:  "Renamed",
+		Weeks: []models.ProgramWeek{{Days: []models.ProgramDay{{Day: 2, TemplateID: "bench"}}}},
+	}
+	if err := f.programSvc.UpdateProgram("user-1", "block", update); err != nil {
+		t.Fatalf("UpdateProgram: %v", err)
+	}
+	got, _ := f.programSvc.GetProgram("user-1", "block")
+	if got.Name != "Renamed" || got.Enrollment == nil || got.Enrollment.StartDate != "2024-03-04" {
+		t.Errorf("unexpected program after update %+v", got)
+	}
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/utils"
+)
+
+type RecordService interface {
+	// GetRecords returns the current records and history for every exercise
+	// name, or only for exerciseName when it is not empty.
+	GetRecords(userID, exerciseName string) ([]*models.ExerciseRecords, error)
+	// TrackExercise stores and returns the records the exercise beats, dated
+	// when the exercise was created so that re-tracking it after an edit or
+	// a restore keeps the original date.
+	TrackExercise(userID string, exercise *models.Exercise) ([]*models.PersonalRecord, error)
+	// ForgetExercise removes the records an exercise set, e.g. before it is
+	// re-tracked after an edit.
+	ForgetExercise(userID, exerciseID string) error
+}
+
+type recordService struct {
+	repo repository.RecordRepository
+}
+
+func NewRecordService(repo repository.RecordRepository) RecordService {
+	return &recordService{
+		repo: repo,
+	}
+}
+
+func (s *recordService) GetRecords(userID, exerciseName string) ([]*models.ExerciseRecords, error) {
+	var records []*models.PersonalRecord
+	var err error
+	if models.NormalizeName(exerciseName) != "" {
+		records, err = s.repo.ListByName(userID, exerciseName)
+	} else {
+		records, err = repository.ListAllRecords(s.repo, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	models.SortRecords(records)
+
+	byName := make(map[string]*models.ExerciseRecords)
+	var names []string
+	for _, record := range records {
+		key := models.NormalizeName(record.ExerciseName)
+		group, ok := byName[key]
+		if !ok {
+			group = &models.ExerciseRecords{History: []*models.PersonalRecord{}}
+			byName[key] = group
+			names = append(names, key)
+		}
+		// The most recent spelling of the name is the one shown.
+		group.Name = record.ExerciseName
+		group.History = append(group.History, record)
+	}
+	sort.Strings(names)
+
+	result := make([]*models.ExerciseRecords, 0, len(names))
+	for _, name := range names {
+		group := byName[name]
+		group.Current = currentRecords(group.History)
+		result = append(result, group)
+	}
+	return result, nil
+}
+
+// currentRecords picks the best record for each key from history, which is
+// oldest first; ties go to the earliest. The result is ordered by key.
+func currentRecords(history []*models.PersonalRecord) []*models.PersonalRecord {
+	best := bestByKey(history)
+	current := make([]*models.PersonalRecord, 0, len(best))
+	for _, record := range best {
+		current = append(current, record)
+	}
+	sort.Slice(current, func(i, j int) bool {
+		return current[i].Key() < current[j].Key()
+	})
+	return current
+}
+
+func bestByKey(records []*models.PersonalRecord) map[string]*models.PersonalRecord {
+	best := make(map[string]*models.PersonalRecord)
+	for _, record := range records {
+		if prev, ok := best[record.Key()]; !ok || record.Beats(prev) {
+			best[record.Key()] = record
+		}
+	}
+	return best
+}
+
+func (s *recordService) TrackExercise(userID string, exercise *models.Exercise) ([]*models.PersonalRecord, error) {
+	candidates := recordCandidates(exercise)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	existing, err := s.repo.ListByName(userID, exercise.Name)
+	if err != nil {
+		return nil, err
+	}
+	best := bestByKey(existing)
+
+	var set []*models.PersonalRecord
+	achievedAt := exercise.CreatedAt
+	if achievedAt.IsZero() {
+		achievedAt = utils.GetCurrentTime()
+	}
+	for _, candidate := range candidates {
+		prev, ok := best[candidate.Key()]
+		if ok && !candidate.Beats(prev) {
+			continue
+		}
+		if ok {
+			candidate.Previous = prev.Value
+		}
+		candidate.UserID = userID
+		candidate.RecordID = utils.GenerateUUID()
+		candidate.AchievedAt = achievedAt
+		if err := s.repo.Create(candidate); err != nil {
+			return set, err
+		}
+		set = append(set, candidate)
+	}
+	return set, nil
+}
+
+func (s *recordService) ForgetExercise(userID, exerciseID string) error {
+	return s.repo.DeleteByExercise(userID, exerciseID)
+}
+
+// recordCandidates returns the exercise's best performance for every record
+// key it has data for, ordered by key.
+func recordCandidates(exercise *models.Exercise) []*models.PersonalRecord {
+	best := make(map[string]*models.PersonalRecord)
+	offer := func(kind, qualifier string, value float64, unit string) {
+		if value <= 0 {
+			return
+		}
+		r := &models.PersonalRecord{
+			ExerciseName: exercise.Name,
+			ExerciseID:   exercise.ExerciseID,
+			Kind:         kind,
+			Qualifier:    qualifier,
+			Value:        round2(value),
+			Unit:         unit,
+		}
+		if prev, ok := best[r.Key()]; !ok || r.Beats(prev) {
+			best[r.Key()] = r
+		}
+	}
+
+	// Weights and distances are compared in kilograms and kilometres so that
+	// the same lift or distance logged in other units competes for one record.
+	// Warm-ups never set records.
+	for _, set := range exercise.Sets {
+		if set.IsWarmup() {
+			continue
+		}
+		weight := round2(models.ConvertWeight(set.Weight, set.Unit, models.UnitKg))
+		offer(models.RecordHeaviestWeight, "", weight, models.UnitKg)
+		offer(models.RecordEstimated1RM, "", models.EstimatedOneRepMax(weight, set.Reps), models.UnitKg)
+		if weight > 0 {
+			offer(models.RecordRepsAtWeight, formatAmount(weight, models.UnitKg), float64(set.Reps), "reps")
+		} else {
+			offer(models.RecordRepsAtWeight, "bodyweight", float64(set.Reps), "reps")
+		}
+		offer(models.RecordLongestDuration, "", float64(set.Duration), "s")
+	}
+	if len(exercise.Sets) == 0 {
+		offer(models.RecordRepsAtWeight, "bodyweight", float64(exercise.Reps), "reps")
+	}
+	if exercise.Distance > 0 {
+		distance, unit := exercise.Distance, exercise.DistanceUnit
+		if meters, ok := models.DistanceInMeters(distance, unit); ok {
+			distance, unit = math.Round(models.MetersIn(meters, models.DistanceKm)*1000)/1000, models.DistanceKm
+		}
+		offer(models.RecordFastestTime, formatAmount(distance, unit), float64(exercise.Time), "s")
+	}
+	offer(models.RecordHighestRPM, "", exercise.RPM, "rpm")
+
+	candidates := make([]*models.PersonalRecord, 0, len(best))
+	for _, r := range best {
+		candidates = append(candidates, r)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Key() < candidates[j].Key()
+	})
+	return candidates
+}
+
+// formatAmount renders a quantity and its unit, e.g. "102.5 kg".
+func formatAmount(value float64, unit string) string {
+	s := strconv.FormatFloat(value, 'f', -1, 64)
+	if unit == "" {
+		return s
+	}
+	return s + " " + unit
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gym-tracker-api/internal/models"
+)
+
+func benchPress(id string, sets ...models.WeightItem) *models.Exercise {
+	return &models.Exercise{ExerciseID: id, Name: "Bench Press", ExerciseType: models.ExerciseTypeWeights, Sets: sets}
+}
+
+// recordValues indexes records by kind and qualifier for compact assertions.
+func recordValues(records []*models.PersonalRecord) map[string]float64 {
+	values := make(map[string]float64, len(records))
+	for _, r := range records {
+		values[r.Kind+" "+r.Qualifier] = r.Value
+	}
+	return values
+}
+
+func TestCreateExercise_SetsRecords(t *testing.T) {
+	f := newFixture()
+	ex := benchPress("ex-1",
+		models.WeightItem{Weight: 100, Unit: "kg", Reps: 5},
+		models.WeightItem{Weight: 110, Unit: "kg", Reps: 1},
+		models.WeightItem{Weight: 100, Unit: "kg", Reps: 6},
+	)
+	if err := f.exerciseSvc.CreateExercise("user-1", ex, false); err != nil {
+		t.Fatalf("CreateExercise: %v", err)
+	}
+
+	got := recordValues(ex.NewRecords)
+	want := map[string]float64{
+		"heaviest_weight ":      110,
+		"estimated_1rm ":        120,
+		"reps_at_weight 100 kg": 6,
+		"reps_at_weight 110 kg": 1,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("NewRecords = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestCreateExercise_WarmupsSetNoRecords(t *testing.T) {
+	f := newFixture()
+	ex := benchPress("ex-1",
+		models.WeightItem{Weight: 140, Unit: "kg", Reps: 1, SetType: models.SetTypeWarmup},
+		models.WeightItem{Weight: 100, Unit: "kg", Reps: 5, SetType: models.SetTypeWorking},
+	)
+	if err := f.exerciseSvc.CreateExercise("user-1", ex, false); err != nil {
+		t.Fatalf("CreateExercise: %v", err)
+	}
+
+	got := recordValues(ex.NewRecords)
+	if _, ok := got["reps_at_weight 140 kg"]; ok || got["heaviest_weight "] != 100 {
+		t.Errorf("NewRecords = %v, want only the working set counted", got)
+	}
+}
+
+func TestCreateExercise_OnlyBeatenRecordsAreNew(t *testing.T) {
+	f := newFixture()
+	f.exerciseSvc.CreateExercise("user-1", benchPress("ex-1", models.WeightItem{Weight: 100, Unit: "kg", Reps: 5}), false)
+
+	ex := benchPress("ex-2", models.WeightItem{Weight: 100, Unit: "kg", Reps: 3})
+	ex.Name = "bench  press"
+	if err := f.exerciseSvc.CreateExercise("user-1", ex, false); err != nil {
+		t.Fatalf("CreateExercise: %v", err)
+	}
+	if len(ex.NewRecords) != 0 {
+		t.Errorf("a weaker session set records: %v", recordValues(ex.NewRecords))
+	}
+
+	ex = benchPress("ex-3", models.WeightItem{Weight: 105, Unit: "kg", Reps: 5})
+	f.exerciseSvc.CreateExercise("user-1", ex, false)
+	got := recordValues(ex.NewRecords)
+	if got["heaviest_weight "] != 105 || got["reps_at_weight 105 kg"] != 5 {
+		t.Errorf("NewRecords = %v, want heaviest weight 105 and 5 reps at 105 kg", got)
+	}
+	for _, r := range ex.NewRecords {
+		if r.Kind == models.RecordHeaviestWeight && r.Previous != 100 {
+			t.Errorf("Previous = %v, want 100", r.Previous)
+		}
+	}
+}
+
+func TestCreateExercise_CardioAndTimedRecords(t *testing.T) {
+	f := newFixture()
+	run := &models.Exercise{ExerciseID: "ex-1", Name: "Bike", ExerciseType: models.ExerciseTypeCardio, Time: 600, Distance: 5, DistanceUnit: "km"}
+	f.exerciseSvc.CreateExercise("user-1", run, true)
+	if got := recordValues(run.NewRecords); got["fastest_time 5 km"] != 600 || got["highest_rpm "] == 0 {
+		t.Errorf("NewRecords = %v, want fastest 5 km time and highest RPM", got)
+	}
+
+	slower := &models.Exercise{ExerciseID: "ex-2", Name: "Bike", ExerciseType: models.ExerciseTypeCardio, Time: 700, Distance: 5, DistanceUnit: "km"}
+	f.exerciseSvc.CreateExercise("user-1", slower, false)
+	if len(slower.NewRecords) != 0 {
+		t.Errorf("a slower time set records: %v", recordValues(slower.NewRecords))
+	}
+
+	plank := &models.Exercise{ExerciseID: "ex-3", Name: "Plank", ExerciseType: models.ExerciseTypeBodyWeight,
+		Sets: []models.WeightItem{{Duration: 60}, {Duration: 90}}}
+	f.exerciseSvc.CreateExercise("user-1", plank, false)
+	if got := recordValues(plank.NewRecords); got["longest_duration "] != 90 {
+		t.Errorf("NewRecords = %v, want longest duration 90", got)
+	}
+}
+
+func TestUpdateExercise_ReplacesItsRecords(t *testing.T) {
+	f := newFixture()
+	f.exerciseSvc.CreateExercise("user-1", benchPress("ex-1", models.WeightItem{Weight: 100, Unit: "kg", Reps: 5}), false)
+	f.exerciseSvc.CreateExercise("user-1", benchPress("ex-2", models.WeightItem{Weight: 200, Unit: "kg", Reps: 5}), false)
+
+	// 200 was a typo; the corrected session is not a record.
+	fixed := benchPress("ex-2", models.WeightItem{Weight: 90, Unit: "kg", Reps: 5})
+	if err := f.exerciseSvc.UpdateExercise("user-1", "ex-2", fixed, false); err != nil {
+		t.Fatalf("UpdateExercise: %v", err)
+	}
+	if got := recordValues(fixed.NewRecords); got["heaviest_weight "] != 0 || got["reps_at_weight 90 kg"] != 5 {
+		t.Errorf("NewRecords = %v, want only 5 reps at 90 kg", got)
+	}
+
+	groups, err := f.recordSvc.GetRecords("user-1", "Bench Press")
+	if err != nil {
+		t.Fatalf("GetRecords: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("GetRecords returned %d groups, want 1", len(groups))
+	}
+	if got := recordValues(groups[0].Current); got["heaviest_weight "] != 100 {
+		t.Errorf("current heaviest = %v, want 100 after the typo was fixed", got["heaviest_weight "])
+	}
+}
+
+// Template sessions hold planned sets rather than performances, so starting
+// one must not set records until the exercises are edited.
+func TestStartTemplate_SetsNoRecords(t *testing.T) {
+	f := newFixture()
+	if err := f.templateSvc.CreateTemplate(sampleTemplate()); err != nil {
+		t.Fatalf("CreateTemplate: %v", err)
+	}
+	if _, err := f.templateSvc.StartTemplate("user-1", "tmpl-1", "2024-03-04"); err != nil {
+		t.Fatalf("StartTemplate: %v", err)
+	}
+	groups, _ := f.recordSvc.GetRecords("user-1", "")
+	if len(groups) != 0 {
+		t.Errorf("starting a template set records: %+v", groups)
+	}
+}
+
+func TestDeleteExercise_ForgetsRecords(t *testing.T) {
+	f := newFixture()
+	f.exerciseSvc.CreateExercise("user-1", benchPress("ex-1", models.WeightItem{Weight: 100, Unit: "kg", Reps: 5}), false)
+	if err := f.exerciseSvc.DeleteExercise("user-1", "ex-1"); err != nil {
+		t.Fatalf("DeleteExercise: %v", err)
+	}
+	groups, _ := f.recordSvc.GetRecords("user-1", "")
+	if len(groups) != 0 {
+		t.Errorf("records remain after delete: %+v", groups)
+	}
+}
+
+func TestRestoreExercise_TracksRecordsAgain(t *testing.T) {
+	f := newFixture()
+	f.exerciseSvc.CreateExercise("user-1", benchPress("ex-1", models.WeightItem{Weight: 100, Unit: "kg", Reps: 5}), false)
+	if err := f.exerciseSvc.DeleteExercise("user-1", "ex-1"); err != nil {
+		t.Fatalf("DeleteExercise: %v", err)
+	}
+	if _, err := f.exerciseSvc.GetExercise("user-1", "ex-1"); !errors.Is(err, models.ErrExerciseNotFound) {
+		t.Fatalf("GetExercise after delete: err = %v, want ErrExerciseNotFound", err)
+	}
+
+	restored, err := f.exerciseSvc.RestoreExercise("user-1", "ex-1")
+	if err != nil {
+		t.Fatalf("RestoreExercise: %v", err)
+	}
+	if restored.DeletedAt != nil || len(restored.Sets) != 1 {
+		t.Errorf("restored exercise = %+v", restored)
+	}
+	groups, _ := f.recordSvc.GetRecords("user-1", "Bench Press")
+	if len(groups) != 1 || recordValues(groups[0].Current)["heaviest_weight "] != 100 {
+		t.Errorf("records after restore = %+v, want heaviest 100", groups)
+	}
+}
+
+// Records are dated by their exercise, so re-tracking one after an edit or a
+// restore does not make an old record look new.
+func TestRecords_KeepTheExerciseDate(t *testing.T) {
+	f := newFixture()
+	logged := time.Date(2024, 1, 15, 18, 0, 0, 0, time.UTC)
+	ex := benchPress("ex-1", models.WeightItem{Weight: 100, Unit: "kg", Reps: 5})
+	ex.CreatedAt = logged
+	if err := f.exerciseSvc.CreateExercise("user-1", ex, false); err != nil {
+		t.Fatalf("CreateExercise: %v", err)
+	}
+
+	edited := benchPress("ex-1", models.WeightItem{Weight: 102.5, Unit: "kg", Reps: 5})
+	if err := f.exerciseSvc.UpdateExercise("user-1", "ex-1", edited, false); err != nil {
+		t.Fatalf("UpdateExercise: %v", err)
+	}
+	for _, r := range edited.NewRecords {
+		if !r.AchievedAt.Equal(logged) {
+			t.Errorf("after update %s achieved at %v, want %v", r.Key(), r.AchievedAt, logged)
+		}
+	}
+
+	f.exerciseSvc.DeleteExercise("user-1", "ex-1")
+	if _, err := f.exerciseSvc.RestoreExercise("user-1", "ex-1"); err != nil {
+		t.Fatalf("RestoreExercise: %v", err)
+	}
+	groups, _ := f.recordSvc.GetRecords("user-1", "Bench Press")
+	if len(groups) != 1 || len(groups[0].History) == 0 {
+		t.Fatalf("records after restore = %+v", groups)
+	}
+	for _, r := range groups[0].History {
+		if !r.AchievedAt.Equal(logged) {
+			t.Errorf("after restore %s achieved at %v, want %v", r.Key(), r.AchievedAt, logged)
+		}
+	}
+}
+
+func TestGetRecords_GroupsByNameWithHistory(t *testing.T) {
+	f := newFixture()
+	f.exerciseSvc.CreateExercise("user-1", benchPress("ex-1", models.WeightItem{Weight: 100, Unit: "kg", Reps: 1}), false)
+	f.exerciseSvc.CreateExercise("user-1", benchPress("ex-2", models.WeightItem{Weight: 105, Unit: "kg", Reps: 1}), false)
+	f.exerciseSvc.CreateExercise("user-1", &models.Exercise{ExerciseID: "ex-3", Name: "Squat", ExerciseType: models.ExerciseTypeWeights,
+		Sets: []models.WeightItem{{Weight: 140, Unit: "kg", Reps: 1}}}, false)
+
+	groups, err := f.recordSvc.GetRecords("user-1", "")
+	if err != nil {
+		t.Fatalf("GetRecords: %v", err)
+	}
+	if len(groups) != 2 || groups[0].Name != "Bench Press" || groups[1].Name != "Squat" {
+		t.Fatalf("GetRecords groups = %+v, want Bench Press then Squat", groups)
+	}
+
+	bench := groups[0]
+	var heaviest []float64
+	for _, r := range bench.History {
+		if r.Kind == models.RecordHeaviestWeight {
+			heaviest = append(heaviest, r.Value)
+		}
+	}
+	if len(heaviest) != 2 || heaviest[0] != 100 || heaviest[1] != 105 {
+		t.Errorf("heaviest weight history = %v, want [100 105]", heaviest)
+	}
+	if got := recordValues(bench.Current); got["heaviest_weight "] != 105 || got["reps_at_weight 100 kg"] != 1 {
+		t.Errorf("current = %v, want heaviest 105 and 1 rep at 100 kg", got)
+	}
+}
+
+func TestRecords_CompareAcrossUnits(t *testing.T) {
+	f := newFixture()
+	if err := f.exerciseSvc.CreateExercise("user-1", benchPress("ex-1", models.WeightItem{Weight: 100, Unit: "kg", Reps: 1}), false); err != nil {
+		t.Fatalf("CreateExercise: %v", err)
+	}
+
+	// 225 lb is 102.06 kg, so it beats 100 kg despite being logged in pounds.
+	ex := benchPress("ex-2", models.WeightItem{Weight: 225, Unit: "lbs", Reps: 1})
+	if err := f.exerciseSvc.CreateExercise("user-1", ex, false); err != nil {
+		t.Fatalf("CreateExercise: %v", err)
+	}
+	got := recordValues(ex.NewRecords)
+	if got["heaviest_weight "] != 102.06 {
+		t.Errorf("heaviest_weight = %v, want 102.06 (kg)", got["heaviest_weight "])
+	}
+	for _, r := range ex.NewRecords {
+		if r.Kind == models.RecordHeaviestWeight && (r.Unit != models.UnitKg || r.Previous != 100) {
+			t.Errorf("record = %+v, want kg with previous 100", r)
+		}
+	}
+	if ex.Sets[0].Unit != models.UnitLb || ex.Sets[0].OriginalUnit != "lbs" {
+		t.Errorf("stored set = %+v, want lb with original lbs", ex.Sets[0])
+	}
+}
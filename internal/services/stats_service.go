@@ -0,0 +1,205 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+type StatsService interface {
+	// ExerciseStats returns a time series of one metric over every set logged
+	// for an exercise name, bucketed by the dates of the workouts the
+	// exercises belong to. Loads of body-weight exercises include the
+	// bodyweight measured on or before each workout's date. Density divides
+	// tonnage by the active minutes of the workouts it was lifted in, so
+	// workouts without a measured duration are left out.
+	ExerciseStats(userID, exerciseName string, query models.StatsQuery) (*models.ExerciseStats, error)
+}
+
+type statsService struct {
+	workouts     repository.WorkoutRepository
+	exercises    repository.ExerciseRepository
+	measurements repository.MeasurementRepository
+}
+
+// NewStatsService returns a StatsService. measurements supplies the
+// bodyweight added to body-weight exercises' loads; nil leaves their loads as
+// logged.
+func NewStatsService(workouts repository.WorkoutRepository, exercises repository.ExerciseRepository, measurements repository.MeasurementRepository) StatsService {
+	return &statsService{
+		workouts:     workouts,
+		exercises:    exercises,
+		measurements: measurements,
+	}
+}
+
+func (s *statsService) ExerciseStats(userID, exerciseName string, query models.StatsQuery) (*models.ExerciseStats, error) {
+	query.Normalize()
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	exercises, err := s.exercises.ListByName(userID, exerciseName)
+	if err != nil {
+		return nil, err
+	}
+	workouts, err := s.exerciseWorkouts(userID, repository.DateRange{From: query.From, To: query.To})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.ExerciseStats{
+		Name:   exerciseName,
+		Metric: query.Metric,
+		Bucket: query.Bucket,
+		Points: []models.StatsPoint{},
+	}
+	switch query.Metric {
+	case models.MetricE1RM:
+		stats.Formula = query.Formula
+		stats.Unit = query.Unit
+	case models.MetricTonnage, models.MetricDensity:
+		stats.Unit = query.Unit
+	}
+
+	var bodyweights *models.BodyweightHistory
+	values := make(map[string]float64)
+	minutes := make(map[string]float64) // density only: active minutes per period
+	timed := make(map[string]bool)      // workouts already counted in minutes
+	for _, exercise := range exercises {
+		workout, ok := workouts[exercise.ExerciseID]
+		if !ok {
+			// Exercises outside any workout (or outside the range) have no date.
+			continue
+		}
+		if query.Metric == models.MetricDensity && workout.durationSeconds == 0 {
+			continue
+		}
+		date := workout.date
+		period := models.BucketStart(query.Bucket, date).Format(models.DateLayout)
+		var bodyweight float64
+		if exercise.ExerciseType == models.ExerciseTypeBodyWeight && usesLoad(query.Metric) {
+			if bodyweights == nil {
+				if bodyweights, err = s.bodyweights(userID, query.To); err != nil {
+					return nil, err
+				}
+			}
+			bodyweight, _ = bodyweights.On(date.Format(models.DateLayout), query.Unit)
+		}
+		value, counted := metricValue(query, exercise.Sets, bodyweight)
+		if !counted {
+			continue
+		}
+		if query.Metric == models.MetricE1RM {
+			values[period] = math.Max(values[period], value)
+		} else {
+			values[period] += value
+		}
+		if query.Metric == models.MetricDensity && !timed[workout.workoutID] {
+			timed[workout.workoutID] = true
+			minutes[period] += float64(workout.durationSeconds) / 60
+		}
+	}
+
+	for period, value := range values {
+		if query.Metric == models.MetricDensity {
+			value /= minutes[period]
+		}
+		stats.Points = append(stats.Points, models.StatsPoint{Period: period, Value: round2(value)})
+	}
+	sort.Slice(stats.Points, func(i, j int) bool {
+		return stats.Points[i].Period < stats.Points[j].Period
+	})
+	return stats, nil
+}
+
+// usesLoad reports whether metric depends on the weight lifted.
+func usesLoad(metric string) bool {
+	return metric == models.MetricE1RM || metric == models.MetricTonnage || metric == models.MetricDensity
+}
+
+// bodyweights loads the user's bodyweight history up to to. Without a
+// measurement repository the history is empty.
+func (s *statsService) bodyweights(userID, to string) (*models.BodyweightHistory, error) {
+	if s.measurements == nil {
+		return models.NewBodyweightHistory(nil), nil
+	}
+	measurements, err := repository.CollectAll(func(opts repository.ListOptions) (*repository.Page[*models.Measurement], error) {
+		return s.measurements.ListByDateRange(userID, repository.DateRange{To: to}, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return models.NewBodyweightHistory(measurements), nil
+}
+
+// metricValue computes the query's metric over one exercise's sets, in the
+// query's unit. bodyweight, also in the query's unit, is added to each set's
+// weight so body-weight exercises count the lifter's own mass. Warm-ups are
+// skipped. It reports false when the sets contribute nothing.
+func metricValue(query models.StatsQuery, sets []models.WeightItem, bodyweight float64) (float64, bool) {
+	var value float64
+	counted := false
+	for _, set := range sets {
+		if set.IsWarmup() {
+			continue
+		}
+		weight := models.ConvertWeight(set.Weight, set.Unit, query.Unit) + bodyweight
+		switch query.Metric {
+		case models.MetricE1RM:
+			if e1rm := models.OneRepMax(query.Formula, weight, set.Reps); e1rm > 0 {
+				value = math.Max(value, e1rm)
+				counted = true
+			}
+		case models.MetricTonnage, models.MetricDensity:
+			value += weight * float64(set.Reps)
+			counted = true
+		case models.MetricReps:
+			value += float64(set.Reps)
+			counted = true
+		case models.MetricSets:
+			value++
+			counted = true
+		}
+	}
+	return value, counted
+}
+
+// exerciseWorkout is the workout an exercise is counted in.
+type exerciseWorkout struct {
+	workoutID       string
+	date            time.Time
+	durationSeconds int
+}
+
+// exerciseWorkouts maps each exercise ID to the earliest workout in dateRange
+// that contains it.
+func (s *statsService) exerciseWorkouts(userID string, dateRange repository.DateRange) (map[string]exerciseWorkout, error) {
+	workouts, err := repository.CollectAll(func(opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+		return s.workouts.ListByDateRange(userID, dateRange, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byExercise := make(map[string]exerciseWorkout)
+	for _, workout := range workouts {
+		date, err := models.ParseDate(workout.Date)
+		if err != nil {
+			continue
+		}
+		for _, id := range workout.Exercises {
+			if prev, ok := byExercise[id]; !ok || date.Before(prev.date) {
+				byExercise[id] = exerciseWorkout{
+					workoutID:       workout.WorkoutID,
+					date:            date,
+					durationSeconds: workout.DurationSeconds,
+				}
+			}
+		}
+	}
+	return byExercise, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+)
+
+// log stores a workout on date holding one exercise with the given sets.
+func (f *fixture) log(t *testing.T, id, date, name string, sets ...models.WeightItem) {
+	t.Helper()
+	f.logType(t, id, date, name, models.ExerciseTypeWeights, sets...)
+}
+
+func (f *fixture) logType(t *testing.T, id, date, name, exerciseType string, sets ...models.WeightItem) {
+	t.Helper()
+	exercise := &models.Exercise{ExerciseID: "ex-" + id, Name: name, ExerciseType: exerciseType, Sets: sets}
+	if err := f.exercises.Create("user-1", exercise); err != nil {
+		t.Fatalf("create exercise: %v", err)
+	}
+	workout := &models.Workout{UserID: "user-1", WorkoutID: "w-" + id, Name: "Session", Date: date, Exercises: []string{exercise.ExerciseID}}
+	if err := f.workouts.Create(workout); err != nil {
+		t.Fatalf("create workout: %v", err)
+	}
+}
+
+func TestExerciseStats_WeeklyE1RM(t *testing.T) {
+	f := newFixture()
+	f.log(t, "1", "2024-03-04", "Squat", models.WeightItem{Weight: 100, Unit: "kg", Reps: 5})
+	f.log(t, "2", "2024-03-07", "squat", models.WeightItem{Weight: 105, Unit: "kg", Reps: 3})
+	f.log(t, "3", "2024-03-12", "Squat", models.WeightItem{Weight: 110, Unit: "kg", Reps: 1})
+	f.log(t, "4", "2024-03-12", "Bench Press", models.WeightItem{Weight: 200, Unit: "kg", Reps: 1})
+
+	stats, err := f.statsSvc.ExerciseStats("user-1", "Squat", models.StatsQuery{})
+	if err != nil {
+		t.Fatalf("ExerciseStats: %v", err)
+	}
+	want := []models.StatsPoint{
+		{Period: "2024-03-04", Value: 116.67},
+		{Period: "2024-03-11", Value: 110},
+	}
+	if !reflect.DeepEqual(stats.Points, want) {
+		t.Errorf("Points = %+v, want %+v", stats.Points, want)
+	}
+	if stats.Metric != models.MetricE1RM || stats.Bucket != models.BucketWeek || stats.Formula != models.FormulaEpley || stats.Unit != models.UnitKg {
+		t.Errorf("defaults = %s/%s/%s/%s, want e1rm/week/epley/kg", stats.Metric, stats.Bucket, stats.Formula, stats.Unit)
+	}
+}
+
+func TestExerciseStats_TonnageConvertsUnits(t *testing.T) {
+	f := newFixture()
+	f.log(t, "1", "2024-03-04", "Deadlift",
+		models.WeightItem{Weight: 100, Unit: "kg", Reps: 5},
+		models.WeightItem{Weight: 220.462262185, Unit: "lbs", Reps: 2},
+	)
+	f.log(t, "2", "2024-04-20", "Deadlift", models.WeightItem{Weight: 50, Unit: "kg", Reps: 10})
+
+	stats, err := f.statsSvc.ExerciseStats("user-1", "Deadlift", models.StatsQuery{Metric: models.MetricTonnage, Bucket: models.BucketMonth})
+	if err != nil {
+		t.Fatalf("ExerciseStats: %v", err)
+	}
+	want := []models.StatsPoint{
+		{Period: "2024-03-01", Value: 700},
+		{Period: "2024-04-01", Value: 500},
+	}
+	if !reflect.DeepEqual(stats.Points, want) {
+		t.Errorf("Points = %+v, want %+v", stats.Points, want)
+	}
+
+	stats, _ = f.statsSvc.ExerciseStats("user-1", "Deadlift", models.StatsQuery{Metric: models.MetricTonnage, Bucket: models.BucketMonth, Unit: "lb"})
+	if got := stats.Points[1].Value; got != 1102.31 {
+		t.Errorf("April tonnage in lb = %v, want 1102.31", got)
+	}
+}
+
+func TestExerciseStats_RepsSetsAndRange(t *testing.T) {
+	f := newFixture()
+	f.log(t, "1", "2024-03-04", "Pull Up", models.WeightItem{Reps: 8}, models.WeightItem{Reps: 6})
+	f.log(t, "2", "2024-03-05", "Pull Up", models.WeightItem{Reps: 10})
+	f.log(t, "3", "2024-03-20", "Pull Up", models.WeightItem{Reps: 12})
+
+	query := models.StatsQuery{Metric: models.MetricReps, Bucket: models.BucketDay, From: "2024-03-01", To: "2024-03-10"}
+	stats, err := f.statsSvc.ExerciseStats("user-1", "Pull Up", query)
+	if err != nil {
+		t.Fatalf("ExerciseStats: %v", err)
+	}
+	want := []models.StatsPoint{{Period: "2024-03-04", Value: 14}, {Period: "2024-03-05", Value: 10}}
+	if !reflect.DeepEqual(stats.Points, want) {
+		t.Errorf("reps Points = %+v, want %+v", stats.Points, want)
+	}
+
+	query.Metric = models.MetricSets
+	stats, _ = f.statsSvc.ExerciseStats("user-1", "Pull Up", query)
+	want = []models.StatsPoint{{Period: "2024-03-04", Value: 2}, {Period: "2024-03-05", Value: 1}}
+	if !reflect.DeepEqual(stats.Points, want) {
+		t.Errorf("sets Points = %+v, want %+v", stats.Points, want)
+	}
+}
+
+func TestExerciseStats_SkipsWarmups(t *testing.T) {
+	f := newFixture()
+	f.log(t, "1", "2024-03-04", "Squat",
+		models.WeightItem{Weight: 60, Unit: "kg", Reps: 10, SetType: models.SetTypeWarmup},
+		models.WeightItem{Weight: 100, Unit: "kg", Reps: 5},
+		models.WeightItem{Weight: 80, Unit: "kg", Reps: 8, SetType: models.SetTypeDrop},
+	)
+
+	for metric, want := range map[string]float64{
+		models.MetricTonnage: 1140,
+		models.MetricReps:    13,
+		models.MetricSets:    2,
+	} {
+		stats, err := f.statsSvc.ExerciseStats("user-1", "Squat", models.StatsQuery{Metric: metric})
+		if err != nil {
+			t.Fatalf("ExerciseStats(%s): %v", metric, err)
+		}
+		if len(stats.Points) != 1 || stats.Points[0].Value != want {
+			t.Errorf("%s points = %+v, want one point of %v", metric, stats.Points, want)
+		}
+	}
+}
+
+func TestExerciseStats_BodyweightExerciseAddsMeasuredBodyweight(t *testing.T) {
+	f := newFixture()
+	for _, m := range []*models.Measurement{
+		{UserID: "user-1", MeasurementID: "m-1", Date: "2024-03-01", Bodyweight: 80, WeightUnit: models.UnitKg},
+		{UserID: "user-1", MeasurementID: "m-2", Date: "2024-03-20", Bodyweight: 187.3929, WeightUnit: models.UnitLb},
+	} {
+		if err := f.measurements.Create(m); err != nil {
+			t.Fatalf("create measurement: %v", err)
+		}
+	}
+	f.logType(t, "1", "2024-02-26", "Pull-up", models.ExerciseTypeBodyWeight, models.WeightItem{Reps: 10})
+	f.logType(t, "2", "2024-03-05", "Pull-up", models.ExerciseTypeBodyWeight,
+		models.WeightItem{Reps: 10},
+		models.WeightItem{Weight: 10, Unit: "kg", Reps: 5},
+	)
+	f.logType(t, "3", "2024-04-02", "Pull-up", models.ExerciseTypeBodyWeight, models.WeightItem{Reps: 8})
+
+	stats, err := f.statsSvc.ExerciseStats("user-1", "Pull-up", models.StatsQuery{Metric: models.MetricTonnage, Bucket: models.BucketMonth})
+	if err != nil {
+		t.Fatalf("ExerciseStats: %v", err)
+	}
+	// February predates any measurement, so only the logged load counts.
+	want := []models.StatsPoint{
+		{Period: "2024-02-01", Value: 0},
+		{Period: "2024-03-01", Value: 80*10 + 90*5},
+		{Period: "2024-04-01", Value: 680},
+	}
+	if !reflect.DeepEqual(stats.Points, want) {
+		t.Errorf("Points = %+v, want %+v", stats.Points, want)
+	}
+}
+
+func TestExerciseStats_DensityUsesMeasuredDuration(t *testing.T) {
+	f := newFixture()
+	f.log(t, "1", "2024-03-04", "Squat", models.WeightItem{Weight: 100, Unit: "kg", Reps: 5})
+	f.log(t, "2", "2024-03-06", "Squat", models.WeightItem{Weight: 100, Unit: "kg", Reps: 10})
+	f.log(t, "3", "2024-03-07", "Squat", models.WeightItem{Weight: 500, Unit: "kg", Reps: 10})
+	for id, seconds := range map[string]int{"w-1": 600, "w-2": 1200} {
+		w, _ := f.workouts.GetByID("user-1", id)
+		w.WorkoutSession = models.WorkoutSession{Status: models.WorkoutCompleted, DurationSeconds: seconds}
+		if err := f.workouts.Update(w); err != nil {
+			t.Fatalf("update workout: %v", err)
+		}
+	}
+
+	stats, err := f.statsSvc.ExerciseStats("user-1", "Squat", models.StatsQuery{Metric: models.MetricDensity})
+	if err != nil {
+		t.Fatalf("ExerciseStats: %v", err)
+	}
+	// 1500 kg over 30 minutes; the untimed workout is left out.
+	want := []models.StatsPoint{{Period: "2024-03-04", Value: 50}}
+	if !reflect.DeepEqual(stats.Points, want) {
+		t.Errorf("Points = %+v, want %+v", stats.Points, want)
+	}
+	if stats.Unit != models.UnitKg {
+		t.Errorf("Unit = %q, want kg", stats.Unit)
+	}
+}
+
+func TestExerciseStats_InvalidQuery(t *testing.T) {
+	f := newFixture()
+	_, err := f.statsSvc.ExerciseStats("user-1", "Squat", models.StatsQuery{Metric: "speed", Bucket: "year"})
+	if !errors.Is(err, models.ErrInvalidStatsQuery) {
+		t.Fatalf("err = %v, want ErrInvalidStatsQuery", err)
+	}
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 2 {
+		t.Errorf("err = %v, want field errors for metric and bucket", err)
+	}
+}
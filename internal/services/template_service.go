@@ -0,0 +1,196 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/utils"
+)
+
+type TemplateService interface {
+	GetTemplate(userID, templateID string) (*models.Template, error)
+	GetTemplates(userID string, opts repository.ListOptions) (*repository.Page[*models.Template], error)
+	CreateTemplate(template *models.Template) error
+	UpdateTemplate(userID, templateID string, template *models.Template) error
+	DeleteTemplate(userID, templateID string) error
+	StartTemplate(userID, templateID, date string) (*models.WorkoutDetail, error)
+	TemplateFromWorkout(userID, workoutID, name string) (*models.Template, error)
+}
+
+type templateService struct {
+	repo      repository.TemplateRepository
+	workouts  WorkoutService
+	exercises ExerciseService
+}
+
+func NewTemplateService(repo repository.TemplateRepository, workouts WorkoutService, exercises ExerciseService) TemplateService {
+	return &templateService{
+		repo:      repo,
+		workouts:  workouts,
+		exercises: exercises,
+	}
+}
+
+func (s *templateService) GetTemplate(userID, templateID string) (*models.Template, error) {
+	return s.repo.GetByID(userID, templateID)
+}
+
+func (s *templateService) GetTemplates(userID string, opts repository.ListOptions) (*repository.Page[*models.Template], error) {
+	return s.repo.ListByUserID(userID, pageOptions(opts))
+}
+
+func (s *templateService) CreateTemplate(template *models.Template) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Create(template)
+}
+
+func (s *templateService) UpdateTemplate(userID, templateID string, template *models.Template) error {
+	template.UserID = userID
+	template.TemplateID = templateID
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Update(template)
+}
+
+func (s *templateService) DeleteTemplate(userID, templateID string) error {
+	return s.repo.Delete(userID, templateID)
+}
+
+// StartTemplate creates a workout on date (today if empty) with one new
+// exercise per prescription, each pre-filled with its target sets.
+func (s *templateService) StartTemplate(userID, templateID, date string) (*models.WorkoutDetail, error) {
+	template, err := s.repo.GetByID(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if date == "" {
+		date = s.workouts.Today(userID)
+	}
+	return startWorkout(s.workouts, s.exercises, userID, template.Name, date, template.Exercises)
+}
+
+// startWorkout creates a workout named name on date with one new exercise per
+// prescription. If any step fails, the exercises created so far are
+// discarded again.
+func startWorkout(workouts WorkoutService, exercises ExerciseService, userID, name, date string, prescriptions []models.ExercisePrescription) (*models.WorkoutDetail, error) {
+	workout := &models.Workout{
+		UserID:    userID,
+		WorkoutID: utils.GenerateUUID(),
+		Name:      name,
+		Date:      date,
+		Exercises: []string{},
+		CreatedAt: utils.GetCurrentTime(),
+	}
+	// Check the workout before creating exercises so a bad date fails cleanly.
+	if err := workout.Validate(); err != nil {
+		return nil, err
+	}
+
+	created := make([]*models.Exercise, 0, len(prescriptions))
+	rollback := func() {
+		for _, exercise := range created {
+			exercises.DiscardExercise(userID, exercise.ExerciseID)
+		}
+	}
+
+	for _, p := range prescriptions {
+		exercise := exerciseFromPrescription(p)
+		if err := exercises.CreateExercise(userID, exercise, false); err != nil {
+			rollback()
+			return nil, fmt.Errorf("failed to create exercise %q: %w", p.Name, err)
+		}
+		created = append(created, exercise)
+		workout.Exercises = append(workout.Exercises, exercise.ExerciseID)
+	}
+
+	if err := workouts.CreateWorkout(workout); err != nil {
+		rollback()
+		return nil, err
+	}
+
+	return &models.WorkoutDetail{Workout: *workout, Exercises: created}, nil
+}
+
+// discardWorkout permanently deletes a workout created by startWorkout along
+// with its exercises, bypassing the trash. Errors are ignored; this is
+// best-effort cleanup.
+func discardWorkout(workouts WorkoutService, exercises ExerciseService, detail *models.WorkoutDetail) {
+	for _, exercise := range detail.Exercises {
+		exercises.DiscardExercise(detail.UserID, exercise.ExerciseID)
+	}
+	workouts.DiscardWorkout(detail.UserID, detail.WorkoutID)
+}
+
+// exerciseFromPrescription builds a new exercise with TargetSets identical
+// sets. Prescriptions without a set count produce an exercise with no sets.
+func exerciseFromPrescription(p models.ExercisePrescription) *models.Exercise {
+	exercise := &models.Exercise{
+		ExerciseID:   utils.GenerateUUID(),
+		Name:         p.Name,
+		ExerciseType: p.ExerciseType,
+	}
+	for i := 0; i < p.TargetSets; i++ {
+		exercise.Sets = append(exercise.Sets, models.WeightItem{
+			Weight: p.TargetWeight,
+			Unit:   p.Unit,
+			Reps:   p.TargetReps,
+		})
+	}
+	return exercise
+}
+
+// TemplateFromWorkout saves an existing workout as a new template. Each
+// exercise becomes a prescription of its set count, using the reps and weight
+// of its heaviest set as targets. An empty name reuses the workout's name.
+func (s *templateService) TemplateFromWorkout(userID, workoutID, name string) (*models.Template, error) {
+	detail, err := s.workouts.GetWorkoutDetail(userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(name) == "" {
+		name = detail.Name
+	}
+
+	template := &models.Template{
+		UserID:     userID,
+		TemplateID: utils.GenerateUUID(),
+		Name:       name,
+		Exercises:  make([]models.ExercisePrescription, 0, len(detail.Exercises)),
+		CreatedAt:  utils.GetCurrentTime(),
+	}
+	for _, exercise := range detail.Exercises {
+		template.Exercises = append(template.Exercises, prescriptionFromExercise(exercise))
+	}
+
+	if err := s.CreateTemplate(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// prescriptionFromExercise targets the exercise's working sets; warm-ups are
+// left out.
+func prescriptionFromExercise(exercise *models.Exercise) models.ExercisePrescription {
+	p := models.ExercisePrescription{
+		Name:         exercise.Name,
+		ExerciseType: exercise.ExerciseType,
+		TargetReps:   exercise.Reps,
+	}
+	for _, set := range exercise.Sets {
+		if set.IsWarmup() {
+			continue
+		}
+		p.TargetSets++
+		if p.TargetSets == 1 || set.Weight > p.TargetWeight {
+			p.TargetWeight = set.Weight
+			p.TargetReps = set.Reps
+			p.Unit = set.Unit
+		}
+	}
+	return p
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
+)
+
+func sampleTemplate() *models.Template {
+	return &models.Template{
+		UserID:     "user-1",
+		TemplateID: "tmpl-1",
+		Name:       "Push Day A",
+		Exercises: []models.ExercisePrescription{
+			{Name: "Bench Press", ExerciseType: models.ExerciseTypeWeights, TargetSets: 3, TargetReps: 5, TargetWeight: 80, Unit: "kg"},
+			{Name: "Rowing", ExerciseType: models.ExerciseTypeCardio},
+		},
+	}
+}
+
+func TestCreateTemplate_ValidationError(t *testing.T) {
+	f := newFixture()
+	tmpl := sampleTemplate()
+	tmpl.Exercises = nil
+
+	if err := f.templateSvc.CreateTemplate(tmpl); !errors.Is(err, models.ErrInvalidTemplate) {
+		t.Errorf("expected ErrInvalidTemplate, got %v", err)
+	}
+}
+
+func TestStartTemplate_CreatesWorkoutAndExercises(t *testing.T) {
+	f := newFixture()
+	if err := f.templateSvc.CreateTemplate(sampleTemplate()); err != nil {
+		t.Fatalf("CreateTemplate: %v", err)
+	}
+
+	detail, err := f.templateSvc.StartTemplate("user-1", "tmpl-1", "2024-03-04")
+	if err != nil {
+		t.Fatalf("StartTemplate: %v", err)
+	}
+	if detail.Name != "Push Day A" || detail.Date != "2024-03-04" {
+		t.Errorf("unexpected workout %+v", detail.Workout)
+	}
+	if len(detail.Exercises) != 2 || len(detail.Workout.Exercises) != 2 {
+		t.Fatalf("expected 2 exercises, got %+v", detail.Exercises)
+	}
+
+	bench := detail.Exercises[0]
+	if bench.Name != "Bench Press" || len(bench.Sets) != 3 {
+		t.Fatalf("expected 3 bench press sets, got %+v", bench)
+	}
+	if set := bench.Sets[0]; set.Weight != 80 || set.Reps != 5 || set.Unit != "kg" {
+		t.Errorf("unexpected set %+v", set)
+	}
+	if detail.Workout.Exercises[0] != bench.ExerciseID {
+		t.Errorf("workout exercises %v not in template order", detail.Workout.Exercises)
+	}
+
+	stored, err := f.workouts.GetByID("user-1", detail.WorkoutID)
+	if err != nil {
+		t.Fatalf("workout was not stored: %v", err)
+	}
+	found, _ := f.exercises.GetMany("user-1", stored.Exercises)
+	if len(found) != 2 {
+		t.Errorf("expected 2 stored exercises, got %d", len(found))
+	}
+}
+
+func TestStartTemplate_InvalidDateCreatesNothing(t *testing.T) {
+	f := newFixture()
+	f.templateSvc.CreateTemplate(sampleTemplate())
+
+	if _, err := f.templateSvc.StartTemplate("user-1", "tmpl-1", "next tuesday"); !errors.Is(err, models.ErrInvalidWorkout) {
+		t.Fatalf("expected ErrInvalidWorkout, got %v", err)
+	}
+	exercises, _ := repository.ListAllExercises(f.exercises, "user-1")
+	if len(exercises) != 0 {
+		t.Errorf("expected no exercises to be left behind, got %d", len(exercises))
+	}
+}
+
+func TestStartTemplate_NotFound(t *testing.T) {
+	f := newFixture()
+
+	if _, err := f.templateSvc.StartTemplate("user-1", "missing", ""); !errors.Is(err, models.ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, got %v", err)
+	}
+}
+
+func TestTemplateFromWorkout(t *testing.T) {
+	f := newFixture()
+	f.exercises.Create("user-1", &models.Exercise{
+		ExerciseID:   "ex-1",
+		Name:         "Squat",
+		ExerciseType: models.ExerciseTypeWeights,
+		Sets: []models.WeightItem{
+			{Weight: 60, Unit: "kg", Reps: 10},
+			{Weight: 100, Unit: "kg", Reps: 5},
+			{Weight: 80, Unit: "kg", Reps: 8},
+		},
+	})
+	f.workouts.Create(&models.Workout{UserID: "user-1", WorkoutID: "w-1", Name: "Leg Day", Date: "2024-01-15", Exercises: []string{"ex-1"}})
+
+	tmpl, err := f.templateSvc.TemplateFromWorkout("user-1", "w-1", "")
+	if err != nil {
+		t.Fatalf("TemplateFromWorkout: %v", err)
+	}
+	if tmpl.Name != "Leg Day" || len(tmpl.Exercises) != 1 {
+		t.Fatalf("unexpected template %+v", tmpl)
+	}
+	want := models.ExercisePrescription{Name: "Squat", ExerciseType: models.ExerciseTypeWeights, TargetSets: 3, TargetReps: 5, TargetWeight: 100, Unit: "kg"}
+	if tmpl.Exercises[0] != want {
+		t.Errorf("prescription = %+v, want %+v", tmpl.Exercises[0], want)
+	}
+	if _, err := f.templates.GetByID("user-1", tmpl.TemplateID); err != nil {
+		t.Errorf("template was not stored: %v", err)
+	}
+}
@@ -1,27 +1,70 @@
 package services
 
 import (
+	"time"
+
 	"gym-tracker-api/internal/models"
 	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/utils"
 )
 
 type WorkoutService interface {
 	GetWorkout(userID, workoutID string) (*models.Workout, error)
-	GetWorkouts(userID string) ([]*models.Workout, error)
+	GetWorkoutDetail(userID, workoutID string) (*models.WorkoutDetail, error)
+	GetWorkouts(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error)
+	ListWorkoutsByDateRange(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Workout], error)
 	CreateWorkout(workout *models.Workout) error
 	UpdateWorkout(userID, workoutID string, workout *models.Workout) error
+	// DeleteWorkout moves the workout to the trash.
 	DeleteWorkout(userID, workoutID string) error
+	ListDeletedWorkouts(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error)
+	// RestoreWorkout takes the workout out of the trash and returns it.
+	RestoreWorkout(userID, workoutID string) (*models.Workout, error)
+	// DiscardWorkout permanently deletes a workout the user never saw, such
+	// as one whose program session lost a race. It skips the trash and
+	// publishes nothing.
+	DiscardWorkout(userID, workoutID string) error
 	AddExerciseToWorkout(userID, workoutID string, exerciseID string) error
 	RemoveExerciseFromWorkout(userID, workoutID, exerciseID string) error
+	// ReorderWorkout rearranges the workout's exercises; see Workout.Reorder.
+	ReorderWorkout(userID, workoutID string, exerciseIDs []string) (*models.Workout, error)
+	// GroupExercises gathers exercises already in the workout into a new
+	// group, such as a superset, and returns the updated workout.
+	GroupExercises(userID, workoutID string, group models.ExerciseGroup) (*models.Workout, error)
+	// UngroupExercises splits a group back into single exercises.
+	UngroupExercises(userID, workoutID, groupID string) (*models.Workout, error)
+	// StartWorkout, PauseWorkout, ResumeWorkout, FinishWorkout and
+	// AbandonWorkout move the workout through its session and return it.
+	// Transitions the current status does not allow fail with
+	// models.ErrInvalidTransition.
+	StartWorkout(userID, workoutID string) (*models.Workout, error)
+	PauseWorkout(userID, workoutID string) (*models.Workout, error)
+	ResumeWorkout(userID, workoutID string) (*models.Workout, error)
+	FinishWorkout(userID, workoutID string) (*models.Workout, error)
+	AbandonWorkout(userID, workoutID string) (*models.Workout, error)
+	// Today returns the user's current date, used when a client omits one.
+	Today(userID string) string
 }
 
 type workoutService struct {
-	repo repository.WorkoutRepository
+	repo         repository.WorkoutRepository
+	exerciseRepo repository.ExerciseRepository
+	calendar     Calendar
+	events       EventPublisher
 }
 
-func NewWorkoutService(repo repository.WorkoutRepository) WorkoutService {
+// NewWorkoutService returns a WorkoutService. calendar decides each user's
+// current date; when it is nil, dates are in UTC. Changes to existing
+// workouts are published to events unless it is nil.
+func NewWorkoutService(repo repository.WorkoutRepository, exerciseRepo repository.ExerciseRepository, calendar Calendar, events EventPublisher) WorkoutService {
+	if calendar == nil {
+		calendar = utcCalendar{}
+	}
 	return &workoutService{
-		repo: repo,
+		repo:         repo,
+		exerciseRepo: exerciseRepo,
+		calendar:     calendar,
+		events:       events,
 	}
 }
 
@@ -30,33 +73,160 @@ func (s *workoutService) GetWorkout(userID, workoutID string) (*models.Workout,
 	if err != nil {
 		return nil, err
 	}
+	normalizeWorkout(workout)
 	return workout, nil
 }
 
-func (s *workoutService) GetWorkouts(userID string) ([]*models.Workout, error) {
-	workouts, err := s.repo.ListByUserID(userID)
+// GetWorkoutDetail returns the workout with its exercises resolved in a single
+// batch lookup. Exercise IDs that no longer exist are reported rather than
+// failing the whole call.
+func (s *workoutService) GetWorkoutDetail(userID, workoutID string) (*models.WorkoutDetail, error) {
+	workout, err := s.repo.GetByID(userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	normalizeWorkout(workout)
+
+	detail := &models.WorkoutDetail{
+		Workout:   *workout,
+		Exercises: []*models.Exercise{},
+	}
+	if len(workout.Exercises) == 0 {
+		return detail, nil
+	}
+
+	found, err := s.exerciseRepo.GetMany(userID, workout.Exercises)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Exercise, len(found))
+	for _, exercise := range found {
+		byID[exercise.ExerciseID] = exercise
+	}
+
+	for _, id := range workout.Exercises {
+		if exercise, ok := byID[id]; ok {
+			detail.Exercises = append(detail.Exercises, exercise)
+		} else {
+			detail.MissingExerciseIDs = append(detail.MissingExerciseIDs, id)
+		}
+	}
+
+	return detail, nil
+}
+
+func (s *workoutService) GetWorkouts(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	page, err := s.repo.ListByUserID(userID, pageOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	normalizeWorkouts(page.Items)
+	return page, nil
+}
+
+func (s *workoutService) ListWorkoutsByDateRange(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	page, err := s.repo.ListByDateRange(userID, dateRange, pageOptions(opts))
 	if err != nil {
 		return nil, err
 	}
-	return workouts, nil
+	normalizeWorkouts(page.Items)
+	return page, nil
 }
 
+// sameIDs reports whether a and b hold the same IDs in the same order.
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeWorkout fills in the groups of a workout stored as a plain list
+// and the status of one never started.
+func normalizeWorkout(workout *models.Workout) {
+	workout.NormalizeGroups()
+	workout.Status = workout.CurrentStatus()
+}
+
+func normalizeWorkouts(workouts []*models.Workout) {
+	for _, workout := range workouts {
+		normalizeWorkout(workout)
+	}
+}
+
+// CreateWorkout stores a new, planned workout. Session fields in the body
+// are ignored.
 func (s *workoutService) CreateWorkout(workout *models.Workout) error {
+	workout.WorkoutSession = models.WorkoutSession{Status: models.WorkoutPlanned}
+	workout.NormalizeGroups()
 	if err := workout.Validate(); err != nil {
 		return err
 	}
 	return s.repo.Create(workout)
 }
 
+// UpdateWorkout replaces the workout. A body without groups keeps the stored
+// groups when its exercise list is unchanged, so clients unaware of groups do
+// not flatten them; otherwise each exercise becomes a single. The session is
+// only changed by the session transitions, so the stored one is kept.
 func (s *workoutService) UpdateWorkout(userID, workoutID string, workout *models.Workout) error {
+	workout.UserID = userID
+	workout.WorkoutID = workoutID
+	workout.WorkoutSession = models.WorkoutSession{}
+	if existing, err := s.repo.GetByID(userID, workoutID); err == nil && existing != nil {
+		workout.WorkoutSession = existing.WorkoutSession
+		if len(workout.Groups) == 0 {
+			existing.NormalizeGroups()
+			if sameIDs(existing.Exercises, workout.Exercises) {
+				workout.Groups = existing.Groups
+			}
+		}
+	}
+	workout.NormalizeGroups()
 	if err := workout.Validate(); err != nil {
 		return err
 	}
-	return s.repo.Update(workout)
+	if err := s.repo.Update(workout); err != nil {
+		return err
+	}
+	s.publish(models.EventWorkoutUpdated, workout, "")
+	return nil
 }
 
 func (s *workoutService) DeleteWorkout(userID, workoutID string) error {
-	return s.repo.Delete(workoutID, userID)
+	if err := s.repo.Delete(workoutID, userID); err != nil {
+		return err
+	}
+	s.publish(models.EventWorkoutDeleted, &models.Workout{UserID: userID, WorkoutID: workoutID}, "")
+	return nil
+}
+
+func (s *workoutService) ListDeletedWorkouts(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	page, err := s.repo.ListDeleted(userID, pageOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	normalizeWorkouts(page.Items)
+	return page, nil
+}
+
+func (s *workoutService) RestoreWorkout(userID, workoutID string) (*models.Workout, error) {
+	if err := s.repo.Restore(userID, workoutID); err != nil {
+		return nil, err
+	}
+	return s.GetWorkout(userID, workoutID)
+}
+
+func (s *workoutService) DiscardWorkout(userID, workoutID string) error {
+	if err := s.repo.Delete(workoutID, userID); err != nil {
+		return err
+	}
+	return s.repo.Purge(userID, workoutID)
 }
 
 func (s *workoutService) AddExerciseToWorkout(userID, workoutID string, exerciseID string) error {
@@ -69,8 +239,17 @@ func (s *workoutService) AddExerciseToWorkout(userID, workoutID string, exercise
 		return models.ErrWorkoutNotFound
 	}
 
-	workout.Exercises = append(workout.Exercises, exerciseID)
-	return s.repo.Update(workout)
+	if !workout.AddExercise(exerciseID) {
+		return nil // already in the workout
+	}
+	if err := workout.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.Update(workout); err != nil {
+		return err
+	}
+	s.publish(models.EventExerciseAdded, workout, exerciseID)
+	return nil
 }
 
 func (s *workoutService) RemoveExerciseFromWorkout(userID, workoutID, exerciseID string) error {
@@ -83,12 +262,93 @@ func (s *workoutService) RemoveExerciseFromWorkout(userID, workoutID, exerciseID
 		return models.ErrWorkoutNotFound
 	}
 
-	for i, ex := range workout.Exercises {
-		if ex == exerciseID {
-			workout.Exercises = append(workout.Exercises[:i], workout.Exercises[i+1:]...)
-			return s.repo.Update(workout)
-		}
+	if !workout.RemoveExercise(exerciseID) {
+		return models.ErrExerciseNotFound
+	}
+	if err := s.repo.Update(workout); err != nil {
+		return err
 	}
+	s.publish(models.EventExerciseRemoved, workout, exerciseID)
+	return nil
+}
+
+func (s *workoutService) ReorderWorkout(userID, workoutID string, exerciseIDs []string) (*models.Workout, error) {
+	return s.modify(userID, workoutID, models.EventWorkoutUpdated, func(workout *models.Workout) error {
+		return workout.Reorder(exerciseIDs)
+	})
+}
+
+func (s *workoutService) GroupExercises(userID, workoutID string, group models.ExerciseGroup) (*models.Workout, error) {
+	group.GroupID = utils.GenerateUUID()
+	return s.modify(userID, workoutID, models.EventWorkoutUpdated, func(workout *models.Workout) error {
+		return workout.Group(group)
+	})
+}
+
+func (s *workoutService) UngroupExercises(userID, workoutID, groupID string) (*models.Workout, error) {
+	return s.modify(userID, workoutID, models.EventWorkoutUpdated, func(workout *models.Workout) error {
+		return workout.Ungroup(groupID)
+	})
+}
+
+func (s *workoutService) StartWorkout(userID, workoutID string) (*models.Workout, error) {
+	return s.transition(userID, workoutID, (*models.WorkoutSession).Start)
+}
+
+func (s *workoutService) PauseWorkout(userID, workoutID string) (*models.Workout, error) {
+	return s.transition(userID, workoutID, (*models.WorkoutSession).Pause)
+}
+
+func (s *workoutService) ResumeWorkout(userID, workoutID string) (*models.Workout, error) {
+	return s.transition(userID, workoutID, (*models.WorkoutSession).Resume)
+}
+
+func (s *workoutService) FinishWorkout(userID, workoutID string) (*models.Workout, error) {
+	return s.transition(userID, workoutID, (*models.WorkoutSession).Finish)
+}
+
+func (s *workoutService) AbandonWorkout(userID, workoutID string) (*models.Workout, error) {
+	return s.transition(userID, workoutID, (*models.WorkoutSession).Abandon)
+}
+
+// transition applies a session transition at the current time.
+func (s *workoutService) transition(userID, workoutID string, apply func(*models.WorkoutSession, time.Time) error) (*models.Workout, error) {
+	now := utils.GetCurrentTime()
+	return s.modify(userID, workoutID, models.EventStatusChanged, func(workout *models.Workout) error {
+		return apply(&workout.WorkoutSession, now)
+	})
+}
+
+// modify applies change to the stored workout, saves it if the result is
+// valid and publishes eventType.
+func (s *workoutService) modify(userID, workoutID, eventType string, change func(*models.Workout) error) (*models.Workout, error) {
+	workout, err := s.repo.GetByID(userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(workout); err != nil {
+		return nil, err
+	}
+	workout.NormalizeGroups()
+	if err := workout.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(workout); err != nil {
+		return nil, err
+	}
+	normalizeWorkout(workout)
+	s.publish(eventType, workout, "")
+	return workout, nil
+}
+
+func (s *workoutService) publish(eventType string, workout *models.Workout, exerciseID string) {
+	event := models.WorkoutEvent{Type: eventType, UserID: workout.UserID, WorkoutID: workout.WorkoutID, ExerciseID: exerciseID}
+	if eventType == models.EventStatusChanged {
+		event.Status = workout.Status
+	}
+	publish(s.events, event)
+}
 
-	return models.ErrExerciseNotFound
+func (s *workoutService) Today(userID string) string {
+	return s.calendar.Today(userID)
 }
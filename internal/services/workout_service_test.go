@@ -2,10 +2,12 @@ package services
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
 	"gym-tracker-api/internal/models"
+	"gym-tracker-api/internal/repository"
 )
 
 // mockWorkoutRepo implements repository.WorkoutRepository for testing.
@@ -14,14 +16,33 @@ type mockWorkoutRepo struct {
 	workouts []*models.Workout
 	err      error
 	updated  *models.Workout
+	listOpts repository.ListOptions
 }
 
 func (m *mockWorkoutRepo) GetByID(userID, workoutID string) (*models.Workout, error) {
 	return m.workout, m.err
 }
 
-func (m *mockWorkoutRepo) ListByUserID(userID string) ([]*models.Workout, error) {
-	return m.workouts, m.err
+func (m *mockWorkoutRepo) ListByUserID(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	m.listOpts = opts
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &repository.Page[*models.Workout]{Items: m.workouts}, nil
+}
+
+func (m *mockWorkoutRepo) ListByDateRange(userID string, dateRange repository.DateRange, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	m.listOpts = opts
+	if m.err != nil {
+		return nil, m.err
+	}
+	var items []*models.Workout
+	for _, w := range m.workouts {
+		if dateRange.Contains(w.Date) {
+			items = append(items, w)
+		}
+	}
+	return &repository.Page[*models.Workout]{Items: items}, nil
 }
 
 func (m *mockWorkoutRepo) Create(workout *models.Workout) error {
@@ -37,6 +58,21 @@ func (m *mockWorkoutRepo) Delete(workoutID, userID string) error {
 	return m.err
 }
 
+func (m *mockWorkoutRepo) ListDeleted(userID string, opts repository.ListOptions) (*repository.Page[*models.Workout], error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &repository.Page[*models.Workout]{}, nil
+}
+
+func (m *mockWorkoutRepo) Restore(userID, workoutID string) error {
+	return m.err
+}
+
+func (m *mockWorkoutRepo) Purge(userID, workoutID string) error {
+	return m.err
+}
+
 func sampleWorkout() *models.Workout {
 	return &models.Workout{
 		UserID:    "user-1",
@@ -52,7 +88,7 @@ func sampleWorkout() *models.Workout {
 
 func TestGetWorkout_Success(t *testing.T) {
 	want := sampleWorkout()
-	svc := NewWorkoutService(&mockWorkoutRepo{workout: want})
+	svc := NewWorkoutService(&mockWorkoutRepo{workout: want}, &mockExerciseRepo{}, nil, nil)
 
 	got, err := svc.GetWorkout("user-1", "workout-1")
 	if err != nil {
@@ -64,7 +100,7 @@ func TestGetWorkout_Success(t *testing.T) {
 }
 
 func TestGetWorkout_RepoError(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{err: errors.New("db error")})
+	svc := NewWorkoutService(&mockWorkoutRepo{err: errors.New("db error")}, &mockExerciseRepo{}, nil, nil)
 
 	_, err := svc.GetWorkout("user-1", "workout-1")
 	if err == nil {
@@ -76,30 +112,126 @@ func TestGetWorkout_RepoError(t *testing.T) {
 
 func TestGetWorkouts_Success(t *testing.T) {
 	workouts := []*models.Workout{sampleWorkout()}
-	svc := NewWorkoutService(&mockWorkoutRepo{workouts: workouts})
+	svc := NewWorkoutService(&mockWorkoutRepo{workouts: workouts}, &mockExerciseRepo{}, nil, nil)
 
-	got, err := svc.GetWorkouts("user-1")
+	got, err := svc.GetWorkouts("user-1", repository.ListOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(got) != 1 {
-		t.Errorf("expected 1 workout, got %d", len(got))
+	if len(got.Items) != 1 {
+		t.Errorf("expected 1 workout, got %d", len(got.Items))
+	}
+}
+
+func TestGetWorkouts_PageSize(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, defaultPageSize},
+		{10, 10},
+		{1000, maxPageSize},
+	}
+
+	for _, tt := range tests {
+		repo := &mockWorkoutRepo{}
+		svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
+
+		if _, err := svc.GetWorkouts("user-1", repository.ListOptions{Limit: tt.limit, Cursor: "abc"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.listOpts.Limit != tt.want {
+			t.Errorf("limit %d: expected repo limit %d, got %d", tt.limit, tt.want, repo.listOpts.Limit)
+		}
+		if repo.listOpts.Cursor != "abc" {
+			t.Errorf("expected cursor to be passed through, got %q", repo.listOpts.Cursor)
+		}
 	}
 }
 
 func TestGetWorkouts_RepoError(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{err: errors.New("db error")})
+	svc := NewWorkoutService(&mockWorkoutRepo{err: errors.New("db error")}, &mockExerciseRepo{}, nil, nil)
 
-	_, err := svc.GetWorkouts("user-1")
+	_, err := svc.GetWorkouts("user-1", repository.ListOptions{})
 	if err == nil {
 		t.Error("expected error, got nil")
 	}
 }
 
+// ListWorkoutsByDateRange
+
+func TestListWorkoutsByDateRange(t *testing.T) {
+	march := sampleWorkout()
+	march.Date = "2024-03-10"
+	mayEvening := sampleWorkout()
+	mayEvening.Date = "2024-05-31T19:00:00Z"
+	june := sampleWorkout()
+	june.Date = "2024-06-01"
+
+	repo := &mockWorkoutRepo{workouts: []*models.Workout{march, mayEvening, june}}
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
+
+	got, err := svc.ListWorkoutsByDateRange("user-1", repository.DateRange{From: "2024-03-01", To: "2024-05-31"}, repository.ListOptions{Descending: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Items) != 2 {
+		t.Errorf("expected 2 workouts in range, got %d", len(got.Items))
+	}
+	if !repo.listOpts.Descending {
+		t.Error("expected descending order to be passed to the repository")
+	}
+}
+
+// GetWorkoutDetail
+
+func TestGetWorkoutDetail_ResolvesInOrder(t *testing.T) {
+	workout := sampleWorkout()
+	workout.Exercises = []string{"ex-2", "ex-missing", "ex-1"}
+	exercises := &mockExerciseRepo{exercises: []*models.Exercise{
+		{ExerciseID: "ex-1", Name: "Bench Press", ExerciseType: models.ExerciseTypeWeights},
+		{ExerciseID: "ex-2", Name: "Row", ExerciseType: models.ExerciseTypeCardio},
+	}}
+	svc := NewWorkoutService(&mockWorkoutRepo{workout: workout}, exercises, nil, nil)
+
+	got, err := svc.GetWorkoutDetail("user-1", "workout-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Exercises) != 2 || got.Exercises[0].ExerciseID != "ex-2" || got.Exercises[1].ExerciseID != "ex-1" {
+		t.Errorf("expected exercises [ex-2 ex-1] in workout order, got %+v", got.Exercises)
+	}
+	if len(got.MissingExerciseIDs) != 1 || got.MissingExerciseIDs[0] != "ex-missing" {
+		t.Errorf("expected missing [ex-missing], got %v", got.MissingExerciseIDs)
+	}
+}
+
+func TestGetWorkoutDetail_NoExercises(t *testing.T) {
+	workout := sampleWorkout()
+	workout.Exercises = nil
+	svc := NewWorkoutService(&mockWorkoutRepo{workout: workout}, &mockExerciseRepo{err: errors.New("should not be called")}, nil, nil)
+
+	got, err := svc.GetWorkoutDetail("user-1", "workout-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Exercises == nil || len(got.Exercises) != 0 {
+		t.Errorf("expected empty exercise list, got %v", got.Exercises)
+	}
+}
+
+func TestGetWorkoutDetail_ExerciseRepoError(t *testing.T) {
+	svc := NewWorkoutService(&mockWorkoutRepo{workout: sampleWorkout()}, &mockExerciseRepo{err: errors.New("db error")}, nil, nil)
+
+	if _, err := svc.GetWorkoutDetail("user-1", "workout-1"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
 // CreateWorkout
 
 func TestCreateWorkout_Success(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{})
+	svc := NewWorkoutService(&mockWorkoutRepo{}, &mockExerciseRepo{}, nil, nil)
 
 	w := sampleWorkout()
 	if err := svc.CreateWorkout(w); err != nil {
@@ -108,7 +240,7 @@ func TestCreateWorkout_Success(t *testing.T) {
 }
 
 func TestCreateWorkout_MissingName(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{})
+	svc := NewWorkoutService(&mockWorkoutRepo{}, &mockExerciseRepo{}, nil, nil)
 
 	w := sampleWorkout()
 	w.Name = ""
@@ -119,7 +251,7 @@ func TestCreateWorkout_MissingName(t *testing.T) {
 }
 
 func TestCreateWorkout_MissingDate(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{})
+	svc := NewWorkoutService(&mockWorkoutRepo{}, &mockExerciseRepo{}, nil, nil)
 
 	w := sampleWorkout()
 	w.Date = ""
@@ -129,8 +261,32 @@ func TestCreateWorkout_MissingDate(t *testing.T) {
 	}
 }
 
+func TestCreateWorkout_InvalidDate(t *testing.T) {
+	svc := NewWorkoutService(&mockWorkoutRepo{}, &mockExerciseRepo{}, nil, nil)
+
+	for _, date := range []string{"15/01/2024", "yesterday", "2024-13-01"} {
+		w := sampleWorkout()
+		w.Date = date
+
+		if err := svc.CreateWorkout(w); err == nil {
+			t.Errorf("expected validation error for date %q, got nil", date)
+		}
+	}
+}
+
+func TestCreateWorkout_TimestampDate(t *testing.T) {
+	svc := NewWorkoutService(&mockWorkoutRepo{}, &mockExerciseRepo{}, nil, nil)
+
+	w := sampleWorkout()
+	w.Date = "2024-01-15T18:30:00Z"
+
+	if err := svc.CreateWorkout(w); err != nil {
+		t.Errorf("unexpected error for RFC 3339 date: %v", err)
+	}
+}
+
 func TestCreateWorkout_RepoError(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{err: errors.New("write failed")})
+	svc := NewWorkoutService(&mockWorkoutRepo{err: errors.New("write failed")}, &mockExerciseRepo{}, nil, nil)
 
 	if err := svc.CreateWorkout(sampleWorkout()); err == nil {
 		t.Error("expected repo error, got nil")
@@ -140,7 +296,7 @@ func TestCreateWorkout_RepoError(t *testing.T) {
 // UpdateWorkout
 
 func TestUpdateWorkout_Success(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{})
+	svc := NewWorkoutService(&mockWorkoutRepo{}, &mockExerciseRepo{}, nil, nil)
 
 	w := sampleWorkout()
 	if err := svc.UpdateWorkout("user-1", "workout-1", w); err != nil {
@@ -149,7 +305,7 @@ func TestUpdateWorkout_Success(t *testing.T) {
 }
 
 func TestUpdateWorkout_ValidationError(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{})
+	svc := NewWorkoutService(&mockWorkoutRepo{}, &mockExerciseRepo{}, nil, nil)
 
 	w := sampleWorkout()
 	w.Name = ""
@@ -162,7 +318,7 @@ func TestUpdateWorkout_ValidationError(t *testing.T) {
 // DeleteWorkout
 
 func TestDeleteWorkout_Success(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{})
+	svc := NewWorkoutService(&mockWorkoutRepo{}, &mockExerciseRepo{}, nil, nil)
 
 	if err := svc.DeleteWorkout("user-1", "workout-1"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -170,18 +326,39 @@ func TestDeleteWorkout_Success(t *testing.T) {
 }
 
 func TestDeleteWorkout_RepoError(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{err: models.ErrWorkoutNotFound})
+	svc := NewWorkoutService(&mockWorkoutRepo{err: models.ErrWorkoutNotFound}, &mockExerciseRepo{}, nil, nil)
 
 	if err := svc.DeleteWorkout("user-1", "missing"); err == nil {
 		t.Error("expected error, got nil")
 	}
 }
 
+func TestRestoreWorkout_ReturnsWorkout(t *testing.T) {
+	repo := &mockWorkoutRepo{workout: sampleWorkout()}
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
+
+	workout, err := svc.RestoreWorkout("user-1", "workout-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if workout.WorkoutID != "workout-1" || workout.Status != models.WorkoutPlanned {
+		t.Errorf("restored workout = %+v", workout)
+	}
+}
+
+func TestRestoreWorkout_NotInTrash(t *testing.T) {
+	svc := NewWorkoutService(&mockWorkoutRepo{err: models.ErrWorkoutNotFound}, &mockExerciseRepo{}, nil, nil)
+
+	if _, err := svc.RestoreWorkout("user-1", "missing"); !errors.Is(err, models.ErrWorkoutNotFound) {
+		t.Errorf("err = %v, want ErrWorkoutNotFound", err)
+	}
+}
+
 // AddExerciseToWorkout
 
 func TestAddExerciseToWorkout_Success(t *testing.T) {
 	repo := &mockWorkoutRepo{workout: sampleWorkout()}
-	svc := NewWorkoutService(repo)
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
 
 	if err := svc.AddExerciseToWorkout("user-1", "workout-1", "ex-new"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -199,7 +376,7 @@ func TestAddExerciseToWorkout_Success(t *testing.T) {
 }
 
 func TestAddExerciseToWorkout_WorkoutNotFound(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{err: models.ErrWorkoutNotFound})
+	svc := NewWorkoutService(&mockWorkoutRepo{err: models.ErrWorkoutNotFound}, &mockExerciseRepo{}, nil, nil)
 
 	if err := svc.AddExerciseToWorkout("user-1", "missing", "ex-1"); err == nil {
 		t.Error("expected error, got nil")
@@ -210,7 +387,7 @@ func TestAddExerciseToWorkout_WorkoutNotFound(t *testing.T) {
 
 func TestRemoveExerciseFromWorkout_Success(t *testing.T) {
 	repo := &mockWorkoutRepo{workout: sampleWorkout()}
-	svc := NewWorkoutService(repo)
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
 
 	if err := svc.RemoveExerciseFromWorkout("user-1", "workout-1", "ex-1"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -224,7 +401,7 @@ func TestRemoveExerciseFromWorkout_Success(t *testing.T) {
 }
 
 func TestRemoveExerciseFromWorkout_ExerciseNotInWorkout(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{workout: sampleWorkout()})
+	svc := NewWorkoutService(&mockWorkoutRepo{workout: sampleWorkout()}, &mockExerciseRepo{}, nil, nil)
 
 	err := svc.RemoveExerciseFromWorkout("user-1", "workout-1", "not-there")
 	if err == nil {
@@ -236,9 +413,209 @@ func TestRemoveExerciseFromWorkout_ExerciseNotInWorkout(t *testing.T) {
 }
 
 func TestRemoveExerciseFromWorkout_WorkoutNotFound(t *testing.T) {
-	svc := NewWorkoutService(&mockWorkoutRepo{err: models.ErrWorkoutNotFound})
+	svc := NewWorkoutService(&mockWorkoutRepo{err: models.ErrWorkoutNotFound}, &mockExerciseRepo{}, nil, nil)
 
 	if err := svc.RemoveExerciseFromWorkout("user-1", "missing", "ex-1"); err == nil {
 		t.Error("expected error, got nil")
 	}
 }
+
+// Groups
+
+func TestGetWorkout_MigratesPlainList(t *testing.T) {
+	svc := NewWorkoutService(&mockWorkoutRepo{workout: sampleWorkout()}, &mockExerciseRepo{}, nil, nil)
+
+	got, err := svc.GetWorkout("user-1", "workout-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Groups) != 1 || got.Groups[0].Kind != models.GroupSingle || got.Groups[0].GroupID != "ex-1" {
+		t.Errorf("expected one single group for ex-1, got %+v", got.Groups)
+	}
+}
+
+func TestAddAndRemoveExercise_KeepGroups(t *testing.T) {
+	w := sampleWorkout()
+	w.Exercises = nil
+	w.Groups = []models.ExerciseGroup{{GroupID: "g-1", Kind: models.GroupSuperset, ExerciseIDs: []string{"ex-1", "ex-2"}, Rounds: 3}}
+	repo := &mockWorkoutRepo{workout: w}
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
+
+	if err := svc.AddExerciseToWorkout("user-1", "workout-1", "ex-3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.updated.Groups; len(got) != 2 || got[0].GroupID != "g-1" || got[0].Rounds != 3 || got[1].GroupID != "ex-3" {
+		t.Errorf("groups after add = %+v", got)
+	}
+
+	if err := svc.RemoveExerciseFromWorkout("user-1", "workout-1", "ex-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.updated.Groups; len(got) != 2 || got[0].GroupID != "g-1" || len(got[0].ExerciseIDs) != 1 {
+		t.Errorf("groups after remove = %+v", got)
+	}
+}
+
+func TestAddExerciseToWorkout_AlreadyInWorkout(t *testing.T) {
+	events := &recordingPublisher{}
+	repo := &mockWorkoutRepo{workout: sampleWorkout()}
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, events)
+
+	if err := svc.AddExerciseToWorkout("user-1", "workout-1", "ex-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updated != nil {
+		t.Errorf("workout was updated to %+v, want it unchanged", repo.updated)
+	}
+	if got := repo.workout.Exercises; !reflect.DeepEqual(got, []string{"ex-1"}) {
+		t.Errorf("exercises = %v, want [ex-1]", got)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("events = %v, want none", events.types())
+	}
+}
+
+func TestUpdateWorkout_KeepsGroupsForUnchangedList(t *testing.T) {
+	stored := sampleWorkout()
+	stored.Exercises = []string{"ex-1", "ex-2"}
+	stored.Groups = []models.ExerciseGroup{{GroupID: "g-1", Kind: models.GroupSuperset, ExerciseIDs: []string{"ex-1", "ex-2"}}}
+	repo := &mockWorkoutRepo{workout: stored}
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
+
+	renamed := sampleWorkout()
+	renamed.Name = "Pull Day"
+	renamed.Exercises = []string{"ex-1", "ex-2"}
+	if err := svc.UpdateWorkout("user-1", "workout-1", renamed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.updated.Groups) != 1 || repo.updated.Groups[0].GroupID != "g-1" {
+		t.Errorf("expected superset to survive, got %+v", repo.updated.Groups)
+	}
+
+	reordered := sampleWorkout()
+	reordered.Exercises = []string{"ex-2", "ex-1"}
+	if err := svc.UpdateWorkout("user-1", "workout-1", reordered); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.updated.Groups) != 2 || repo.updated.Groups[0].GroupID != "ex-2" {
+		t.Errorf("expected singles for a changed list, got %+v", repo.updated.Groups)
+	}
+}
+
+func TestGroupExercises_AssignsID(t *testing.T) {
+	w := sampleWorkout()
+	w.Exercises = []string{"ex-1", "ex-2"}
+	repo := &mockWorkoutRepo{workout: w}
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
+
+	got, err := svc.GroupExercises("user-1", "workout-1", models.ExerciseGroup{GroupID: "client-id", Kind: "Superset", ExerciseIDs: []string{"ex-1", "ex-2"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Groups) != 1 || got.Groups[0].GroupID == "client-id" || got.Groups[0].GroupID == "" {
+		t.Errorf("expected one group with a server ID, got %+v", got.Groups)
+	}
+	if repo.updated != got {
+		t.Error("grouped workout was not saved")
+	}
+
+	if _, err := svc.UngroupExercises("user-1", "workout-1", "missing"); !errors.Is(err, models.ErrWorkoutGroupNotFound) {
+		t.Errorf("expected ErrWorkoutGroupNotFound, got %v", err)
+	}
+}
+
+// Sessions
+
+func TestWorkoutSession_StartAndFinish(t *testing.T) {
+	repo := &mockWorkoutRepo{workout: sampleWorkout()}
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
+
+	got, err := svc.StartWorkout("user-1", "workout-1")
+	if err != nil {
+		t.Fatalf("StartWorkout: %v", err)
+	}
+	if got.Status != models.WorkoutInProgress || got.StartedAt == nil || repo.updated != got {
+		t.Errorf("started workout = %+v", got.WorkoutSession)
+	}
+
+	if _, err := svc.StartWorkout("user-1", "workout-1"); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Errorf("second start: expected ErrInvalidTransition, got %v", err)
+	}
+	if _, err := svc.ResumeWorkout("user-1", "workout-1"); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Errorf("resume running: expected ErrInvalidTransition, got %v", err)
+	}
+
+	got, err = svc.FinishWorkout("user-1", "workout-1")
+	if err != nil {
+		t.Fatalf("FinishWorkout: %v", err)
+	}
+	if got.Status != models.WorkoutCompleted || got.FinishedAt == nil || got.FinishedAt.Before(*got.StartedAt) {
+		t.Errorf("finished workout = %+v", got.WorkoutSession)
+	}
+}
+
+func TestWorkoutSession_NotFound(t *testing.T) {
+	svc := NewWorkoutService(&mockWorkoutRepo{err: models.ErrWorkoutNotFound}, &mockExerciseRepo{}, nil, nil)
+	if _, err := svc.PauseWorkout("user-1", "workout-1"); !errors.Is(err, models.ErrWorkoutNotFound) {
+		t.Errorf("expected ErrWorkoutNotFound, got %v", err)
+	}
+}
+
+func TestCreateAndUpdateWorkout_IgnoreClientSession(t *testing.T) {
+	started := time.Date(2024, 1, 15, 18, 0, 0, 0, time.UTC)
+	repo := &mockWorkoutRepo{}
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, nil)
+
+	w := sampleWorkout()
+	w.WorkoutSession = models.WorkoutSession{Status: models.WorkoutCompleted, DurationSeconds: 60}
+	if err := svc.CreateWorkout(w); err != nil {
+		t.Fatalf("CreateWorkout: %v", err)
+	}
+	if w.Status != models.WorkoutPlanned || w.DurationSeconds != 0 {
+		t.Errorf("created session = %+v, want planned", w.WorkoutSession)
+	}
+
+	stored := sampleWorkout()
+	stored.WorkoutSession = models.WorkoutSession{Status: models.WorkoutInProgress, StartedAt: &started}
+	repo.workout = stored
+	update := sampleWorkout()
+	update.Name = "Leg Day"
+	if err := svc.UpdateWorkout("user-1", "workout-1", update); err != nil {
+		t.Fatalf("UpdateWorkout: %v", err)
+	}
+	if repo.updated.Status != models.WorkoutInProgress || repo.updated.StartedAt == nil {
+		t.Errorf("updated session = %+v, want the stored one", repo.updated.WorkoutSession)
+	}
+}
+
+// Events
+
+func TestWorkoutService_PublishesChanges(t *testing.T) {
+	events := &recordingPublisher{}
+	repo := &mockWorkoutRepo{workout: sampleWorkout()}
+	svc := NewWorkoutService(repo, &mockExerciseRepo{}, nil, events)
+
+	if err := svc.AddExerciseToWorkout("user-1", "workout-1", "ex-2"); err != nil {
+		t.Fatalf("AddExerciseToWorkout: %v", err)
+	}
+	if _, err := svc.StartWorkout("user-1", "workout-1"); err != nil {
+		t.Fatalf("StartWorkout: %v", err)
+	}
+	if _, err := svc.StartWorkout("user-1", "workout-1"); err == nil {
+		t.Fatal("expected second start to fail")
+	}
+	if err := svc.DeleteWorkout("user-1", "workout-1"); err != nil {
+		t.Fatalf("DeleteWorkout: %v", err)
+	}
+
+	want := []string{models.EventExerciseAdded, models.EventStatusChanged, models.EventWorkoutDeleted}
+	if got := events.types(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	if e := events.events[0]; e.UserID != "user-1" || e.WorkoutID != "workout-1" || e.ExerciseID != "ex-2" || e.At.IsZero() {
+		t.Errorf("exercise_added = %+v", e)
+	}
+	if e := events.events[1]; e.Status != models.WorkoutInProgress {
+		t.Errorf("status_changed status = %q, want in_progress", e.Status)
+	}
+}
@@ -0,0 +1,148 @@
+// Package storage builds the repository implementations for the configured
+// backend so that the API and the CLIs select storage the same way.
+package storage
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"gym-tracker-api/internal/repository"
+	"gym-tracker-api/internal/repository/db"
+	"gym-tracker-api/internal/repository/memory"
+	"gym-tracker-api/internal/repository/sqlstore"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Supported backends.
+const (
+	BackendDynamo   = "dynamo"
+	BackendMemory   = "memory"
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+// DefaultTrashRetention is how long deleted workouts and exercises stay
+// restorable when Config.TrashRetention is zero.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// Config selects and configures a backend. DSN is used by the SQL backends;
+// Dynamo and the table names by the DynamoDB backend.
+type Config struct {
+	Backend           string
+	DSN               string
+	TrashRetention    time.Duration
+	Dynamo            *dynamodb.DynamoDB
+	WorkoutsTable     string
+	ExercisesTable    string
+	TemplatesTable    string
+	ProgramsTable     string
+	RecordsTable      string
+	ProfilesTable     string
+	MeasurementsTable string
+}
+
+// Repositories holds one implementation of each repository interface.
+type Repositories struct {
+	Workouts     repository.WorkoutRepository
+	Exercises    repository.ExerciseRepository
+	Templates    repository.TemplateRepository
+	Programs     repository.ProgramRepository
+	Records      repository.RecordRepository
+	Profiles     repository.ProfileRepository
+	Measurements repository.MeasurementRepository
+	// TrashRetention is how long deleted items can be restored.
+	TrashRetention time.Duration
+	sweepers       []repository.TrashSweeper
+	closer         io.Closer
+}
+
+// SweepTrash purges the workouts and exercises deleted more than
+// TrashRetention ago and returns how many it removed. DynamoDB expires them
+// through TTL, so there it does nothing.
+func (r *Repositories) SweepTrash() (int, error) {
+	cutoff := time.Now().Add(-r.TrashRetention)
+	total := 0
+	for _, sweeper := range r.sweepers {
+		n, err := sweeper.PurgeDeletedBefore(cutoff)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Sweeps reports whether SweepTrash must be called periodically to enforce
+// TrashRetention.
+func (r *Repositories) Sweeps() bool {
+	return len(r.sweepers) > 0
+}
+
+// Close releases any connection held by the backend.
+func (r *Repositories) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// Open builds the repositories for cfg.Backend. An empty backend means DynamoDB.
+func Open(cfg Config) (*Repositories, error) {
+	retention := cfg.TrashRetention
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+
+	switch cfg.Backend {
+	case "", BackendDynamo:
+		return &Repositories{
+			Workouts:       db.NewDynamoWorkoutRepository(cfg.Dynamo, cfg.WorkoutsTable, retention),
+			Exercises:      db.NewDynamoExerciseRepository(cfg.Dynamo, cfg.ExercisesTable, retention),
+			Templates:      db.NewDynamoTemplateRepository(cfg.Dynamo, cfg.TemplatesTable),
+			Programs:       db.NewDynamoProgramRepository(cfg.Dynamo, cfg.ProgramsTable),
+			Records:        db.NewDynamoRecordRepository(cfg.Dynamo, cfg.RecordsTable),
+			Profiles:       db.NewDynamoProfileRepository(cfg.Dynamo, cfg.ProfilesTable),
+			Measurements:   db.NewDynamoMeasurementRepository(cfg.Dynamo, cfg.MeasurementsTable),
+			TrashRetention: retention,
+		}, nil
+	case BackendMemory:
+		workouts := memory.NewWorkoutRepository().WithRetention(retention)
+		exercises := memory.NewExerciseRepository().WithRetention(retention)
+		return &Repositories{
+			Workouts:       workouts,
+			Exercises:      exercises,
+			Templates:      memory.NewTemplateRepository(),
+			Programs:       memory.NewProgramRepository(),
+			Records:        memory.NewRecordRepository(),
+			Profiles:       memory.NewProfileRepository(),
+			Measurements:   memory.NewMeasurementRepository(),
+			TrashRetention: retention,
+			sweepers:       []repository.TrashSweeper{workouts, exercises},
+		}, nil
+	case BackendSQLite, BackendPostgres:
+		if cfg.DSN == "" {
+			return nil, fmt.Errorf("%s storage requires a DSN", cfg.Backend)
+		}
+		store, err := sqlstore.Open(cfg.Backend, cfg.DSN)
+		if err != nil {
+			return nil, err
+		}
+		workouts, exercises := store.Workouts().WithRetention(retention), store.Exercises().WithRetention(retention)
+		return &Repositories{
+			Workouts:       workouts,
+			Exercises:      exercises,
+			Templates:      store.Templates(),
+			Programs:       store.Programs(),
+			Records:        store.Records(),
+			Profiles:       store.Profiles(),
+			Measurements:   store.Measurements(),
+			TrashRetention: retention,
+			sweepers:       []repository.TrashSweeper{workouts, exercises},
+			closer:         store,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
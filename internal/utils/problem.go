@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"gym-tracker-api/internal/models"
+)
+
+// ProblemContentType is the media type of RFC 7807 error responses.
+const ProblemContentType = "application/problem+json"
+
+// RequestIDHeader carries the request ID. middleware.RequestID sets it on every
+// response, and error responses echo it in the problem body.
+const RequestIDHeader = "X-Request-ID"
+
+// problemTypePrefix namespaces problem types; append the problem code.
+const problemTypePrefix = "urn:gym-tracker:problem:"
+
+// Problem is an RFC 7807 problem details body. Code is a stable identifier
+// clients can branch on; Errors lists invalid fields for validation failures.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Code      string              `json:"code"`
+	Detail    string              `json:"detail,omitempty"`
+	Errors    []models.FieldError `json:"errors,omitempty"`
+	RequestID string              `json:"requestId,omitempty"`
+}
+
+// domainProblems maps domain errors to a status, code and title. Errors are
+// matched with errors.Is, so wrapped errors map the same way.
+var domainProblems = []struct {
+	err    error
+	status int
+	code   string
+	title  string
+}{
+	{models.ErrWorkoutNotFound, http.StatusNotFound, "workout_not_found", "Workout not found"},
+	{models.ErrExerciseNotFound, http.StatusNotFound, "exercise_not_found", "Exercise not found"},
+	{models.ErrUserNotFound, http.StatusNotFound, "user_not_found", "User not found"},
+	{models.ErrTemplateNotFound, http.StatusNotFound, "template_not_found", "Template not found"},
+	{models.ErrProgramNotFound, http.StatusNotFound, "program_not_found", "Program not found"},
+	{models.ErrProfileNotFound, http.StatusNotFound, "profile_not_found", "Profile not found"},
+	{models.ErrMeasurementNotFound, http.StatusNotFound, "measurement_not_found", "Measurement not found"},
+	{models.ErrWorkoutGroupNotFound, http.StatusNotFound, "workout_group_not_found", "Workout group not found"},
+	{models.ErrNoSessionScheduled, http.StatusNotFound, "no_session_scheduled", "No session scheduled"},
+	{models.ErrWorkoutAlreadyExists, http.StatusConflict, "workout_already_exists", "Workout already exists"},
+	{models.ErrExerciseAlreadyExists, http.StatusConflict, "exercise_already_exists", "Exercise already exists"},
+	{models.ErrTemplateAlreadyExists, http.StatusConflict, "template_already_exists", "Template already exists"},
+	{models.ErrProgramAlreadyExists, http.StatusConflict, "program_already_exists", "Program already exists"},
+	{models.ErrNotEnrolled, http.StatusConflict, "not_enrolled", "Not enrolled in program"},
+	{models.ErrSessionAlreadyStarted, http.StatusConflict, "session_already_started", "Session already started"},
+	{models.ErrProgramConflict, http.StatusConflict, "program_conflict", "Program was changed concurrently"},
+	{models.ErrRecordAlreadyExists, http.StatusConflict, "record_already_exists", "Record already exists"},
+	{models.ErrProfileAlreadyExists, http.StatusConflict, "profile_already_exists", "Profile already exists"},
+	{models.ErrMeasurementAlreadyExists, http.StatusConflict, "measurement_already_exists", "Measurement already exists"},
+	{models.ErrInvalidTransition, http.StatusConflict, "invalid_transition", "Invalid workout status transition"},
+	{models.ErrUserAlreadyExists, http.StatusConflict, "user_already_exists", "User already exists"},
+	{models.ErrEmailAlreadyExists, http.StatusConflict, "email_already_exists", "Email already exists"},
+	{models.ErrInvalidWorkout, http.StatusBadRequest, "invalid_workout", "Invalid workout"},
+	{models.ErrInvalidExercise, http.StatusBadRequest, "invalid_exercise", "Invalid exercise"},
+	{models.ErrInvalidTemplate, http.StatusBadRequest, "invalid_template", "Invalid template"},
+	{models.ErrInvalidProgram, http.StatusBadRequest, "invalid_program", "Invalid program"},
+	{models.ErrInvalidStatsQuery, http.StatusBadRequest, "invalid_stats_query", "Invalid stats query"},
+	{models.ErrInvalidProfile, http.StatusBadRequest, "invalid_profile", "Invalid profile"},
+	{models.ErrInvalidMeasurement, http.StatusBadRequest, "invalid_measurement", "Invalid measurement"},
+	{models.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor", "Invalid pagination cursor"},
+	{models.ErrInvalidEmailFormat, http.StatusBadRequest, "invalid_email_format", "Invalid email format"},
+	{models.ErrPasswordTooShort, http.StatusBadRequest, "password_too_short", "Password too short"},
+	{models.ErrUnauthorized, http.StatusUnauthorized, "unauthorized", "Unauthorized"},
+	{models.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials", "Invalid credentials"},
+	{models.ErrTokenInvalid, http.StatusUnauthorized, "token_invalid", "Invalid token"},
+	{models.ErrTokenExpired, http.StatusUnauthorized, "token_expired", "Token expired"},
+}
+
+// NewProblem describes err as a problem. Unrecognised errors become a generic
+// 500 whose detail does not leak internals.
+func NewProblem(err error) Problem {
+	var verr *models.ValidationError
+	if errors.As(err, &verr) {
+		return newProblem(http.StatusBadRequest, "validation_failed", "Validation failed", err.Error(), verr.Fields)
+	}
+
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		code := httpErr.Code
+		if code == "" {
+			code = StatusCodeName(httpErr.StatusCode)
+		}
+		return newProblem(httpErr.StatusCode, code, http.StatusText(httpErr.StatusCode), httpErr.Message, nil)
+	}
+
+	for _, p := range domainProblems {
+		if errors.Is(err, p.err) {
+			return newProblem(p.status, p.code, p.title, err.Error(), nil)
+		}
+	}
+
+	return newProblem(http.StatusInternalServerError, "internal_error", "Internal server error", "An unexpected error occurred", nil)
+}
+
+func newProblem(status int, code, title, detail string, fields []models.FieldError) Problem {
+	return Problem{
+		Type:   problemTypePrefix + code,
+		Title:  title,
+		Status: status,
+		Code:   code,
+		Detail: detail,
+		Errors: fields,
+	}
+}
+
+// StatusCodeName is the default problem code for a status, e.g. 404 -> "not_found".
+func StatusCodeName(status int) string {
+	if status == http.StatusInternalServerError {
+		return "internal_error"
+	}
+	name := strings.ToLower(http.StatusText(status))
+	name = strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(name)
+	if name == "" {
+		return "error"
+	}
+	return name
+}
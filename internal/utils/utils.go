@@ -4,16 +4,22 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"log"
 	"net/http"
 	"time"
 
+	"gym-tracker-api/internal/models"
+
 	"github.com/google/uuid"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// HTTPError is an error with an explicit status. Code is the problem code
+// reported to clients; when empty it is derived from the status.
 type HTTPError struct {
 	StatusCode int    `json:"statusCode"`
+	Code       string `json:"code,omitempty"`
 	Message    string `json:"message"`
 }
 
@@ -24,6 +30,15 @@ func NewHTTPError(statusCode int, message string) HTTPError {
 	}
 }
 
+// NewHTTPErrorCode is NewHTTPError with a specific problem code.
+func NewHTTPErrorCode(statusCode int, code, message string) HTTPError {
+	return HTTPError{
+		StatusCode: statusCode,
+		Code:       code,
+		Message:    message,
+	}
+}
+
 func (e HTTPError) Error() string {
 	return e.Message
 }
@@ -37,32 +52,42 @@ func WriteJSONResponse(w http.ResponseWriter, data interface{}, statusCode int)
 	}
 }
 
-// WriteErrorResponse writes an error response with the given error message and status code.
-func WriteErrorResponse(w http.ResponseWriter, err error) {
-	w.Header().Set("Content-Type", "application/json")
-	statusCode := http.StatusInternalServerError
+// StatusCode returns the HTTP status code for err, defaulting to 500.
+func StatusCode(err error) int {
+	return NewProblem(err).Status
+}
 
+// WriteErrorResponse writes err as an application/problem+json response. It is
+// the single error writer shared by every handler and middleware.
+func WriteErrorResponse(w http.ResponseWriter, err error) {
 	if ve, ok := err.(*validator.ValidationErrors); ok {
-		statusCode = http.StatusBadRequest
-		translations := ve.Translate(nil) // Translate validation errors if needed
-		var errMessages string
-		for field, message := range translations {
-			errMessages += field + ": " + message + "; "
+		verr := &models.ValidationError{Kind: errors.New("invalid request")}
+		for field, message := range ve.Translate(nil) {
+			verr.Fields = append(verr.Fields, models.FieldError{Field: field, Message: message})
 		}
-		err = errors.New(errMessages)
-	} else if httpErr, ok := err.(HTTPError); ok {
-		statusCode = httpErr.StatusCode
+		err = verr
 	}
 
-	w.WriteHeader(statusCode)
-	response := map[string]string{"error": err.Error()}
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode error response", http.StatusInternalServerError)
+	problem := NewProblem(err)
+	problem.RequestID = w.Header().Get(RequestIDHeader)
+	if problem.Status == http.StatusInternalServerError {
+		log.Printf("request %s: %v", problem.RequestID, err)
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.Printf("request %s: failed to encode error response: %v", problem.RequestID, err)
 	}
 }
-// DecodeJSON decodes a JSON request body into the provided struct.
+
+// DecodeJSON decodes a JSON request body into the provided struct. A malformed
+// body is reported as a 400 HTTPError.
 func DecodeJSON(body io.Reader, v interface{}) error {
-	return json.NewDecoder(body).Decode(v)
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return NewHTTPErrorCode(http.StatusBadRequest, "invalid_json", "invalid request body: "+err.Error())
+	}
+	return nil
 }
 
 func GetCurrentTime() time.Time {
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gym-tracker-api/internal/models"
+)
+
+func TestWriteErrorResponse_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+		code string
+	}{
+		{"workout not found", models.ErrWorkoutNotFound, http.StatusNotFound, "workout_not_found"},
+		{"wrapped exercise not found", fmt.Errorf("remove: %w", models.ErrExerciseNotFound), http.StatusNotFound, "exercise_not_found"},
+		{"duplicate workout", models.ErrWorkoutAlreadyExists, http.StatusConflict, "workout_already_exists"},
+		{"duplicate exercise", models.ErrExerciseAlreadyExists, http.StatusConflict, "exercise_already_exists"},
+		{"validation", (&models.Workout{}).Validate(), http.StatusBadRequest, "validation_failed"},
+		{"bad cursor", models.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor"},
+		{"expired token", models.ErrTokenExpired, http.StatusUnauthorized, "token_expired"},
+		{"unauthorized", models.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+		{"http error", NewHTTPError(http.StatusForbidden, "not yours"), http.StatusForbidden, "forbidden"},
+		{"http error with code", NewHTTPErrorCode(http.StatusBadRequest, "invalid_query", "limit"), http.StatusBadRequest, "invalid_query"},
+		{"unknown", errors.New("dynamo exploded"), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			WriteErrorResponse(rr, tt.err)
+			if rr.Code != tt.want {
+				t.Errorf("status = %d, want %d", rr.Code, tt.want)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", ct, ProblemContentType)
+			}
+			problem := decodeProblem(t, rr)
+			if problem.Status != tt.want || problem.Code != tt.code || problem.Type != "urn:gym-tracker:problem:"+tt.code || problem.Title == "" {
+				t.Errorf("problem = %+v, want status %d and code %q", problem, tt.want, tt.code)
+			}
+		})
+	}
+}
+
+func TestWriteErrorResponse_FieldErrors(t *testing.T) {
+	rr := httptest.NewRecorder()
+	WriteErrorResponse(rr, (&models.Exercise{ExerciseID: "ex-1", Name: "Squat"}).Validate())
+
+	problem := decodeProblem(t, rr)
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "exerciseType" {
+		t.Errorf("errors = %+v, want one exerciseType error", problem.Errors)
+	}
+	if problem.Detail == "" {
+		t.Error("expected a detail message")
+	}
+}
+
+func TestWriteErrorResponse_RequestIDAndHiddenInternals(t *testing.T) {
+	rr := httptest.NewRecorder()
+	rr.Header().Set(RequestIDHeader, "req-123")
+	WriteErrorResponse(rr, errors.New("connection refused to 10.0.0.12"))
+
+	problem := decodeProblem(t, rr)
+	if problem.RequestID != "req-123" {
+		t.Errorf("requestId = %q, want req-123", problem.RequestID)
+	}
+	if strings.Contains(problem.Detail, "10.0.0.12") {
+		t.Errorf("detail leaks internal error: %q", problem.Detail)
+	}
+}
+
+func decodeProblem(t *testing.T, rr *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	var problem Problem
+	if err := json.NewDecoder(rr.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	return problem
+}
+
+func TestDecodeJSON_MalformedBody(t *testing.T) {
+	var v map[string]string
+	err := DecodeJSON(strings.NewReader("{not json"), &v)
+	if StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("StatusCode = %d, want %d", StatusCode(err), http.StatusBadRequest)
+	}
+}
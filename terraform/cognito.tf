@@ -79,6 +79,13 @@ resource "aws_cognito_user_pool_client" "gym_tracker_client" {
   supported_identity_providers = ["COGNITO"]
 }
 
+# Members of this group may access any user's data through the API
+resource "aws_cognito_user_group" "admin" {
+  name         = "admin"
+  user_pool_id = aws_cognito_user_pool.gym_tracker_pool.id
+  description  = "Administrators allowed to act on behalf of other users"
+}
+
 # Cognito User Pool Domain
 resource "aws_cognito_user_pool_domain" "gym_tracker_domain" {
   domain       = "gym-tracker-${var.environment}-${random_string.domain_suffix.result}"
@@ -15,6 +15,25 @@ resource "aws_dynamodb_table" "workouts" {
     type = "S"
   }
 
+  # Workout.Date, stored under its JSON name
+  attribute {
+    name = "date"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "WorkoutDateIndex"
+    hash_key        = "UserID"
+    range_key       = "date"
+    projection_type = "ALL"
+  }
+
+  # Deleted items carry expiresAt (epoch seconds) and are removed once the
+  # trash retention has passed.
+  ttl {
+    attribute_name = "expiresAt"
+    enabled        = true
+  }
 
   tags = {
     Environment = var.environment
@@ -39,17 +58,187 @@ resource "aws_dynamodb_table" "exercises" {
     type = "S"
   }
 
+  # "<UserID>#<ExerciseType>", so type queries only read one user's items
+  attribute {
+    name = "UserType"
+    type = "S"
+  }
+
+  # Lower-cased, whitespace-collapsed exercise name
+  attribute {
+    name = "NameKey"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "UserExerciseTypeIndex"
+    hash_key        = "UserType"
+    range_key       = "NameKey"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "ExerciseNameIndex"
+    hash_key        = "UserID"
+    range_key       = "NameKey"
+    projection_type = "ALL"
+  }
+
+  # Deleted items carry expiresAt (epoch seconds) and are removed once the
+  # trash retention has passed.
+  ttl {
+    attribute_name = "expiresAt"
+    enabled        = true
+  }
+
+  tags = {
+    Environment = var.environment
+    Project     = "gym-tracker"
+  }
+}
+
+resource "aws_dynamodb_table" "templates" {
+  name         = "Templates-${var.environment}"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key  = "UserID"
+  range_key = "TemplateID"
+
+  attribute {
+    name = "UserID"
+    type = "S"
+  }
+
+  attribute {
+    name = "TemplateID"
+    type = "S"
+  }
+
+  tags = {
+    Environment = var.environment
+    Project     = "gym-tracker"
+  }
+}
+
+resource "aws_dynamodb_table" "programs" {
+  name         = "Programs-${var.environment}"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key  = "UserID"
+  range_key = "ProgramID"
+
+  attribute {
+    name = "UserID"
+    type = "S"
+  }
+
+  attribute {
+    name = "ProgramID"
+    type = "S"
+  }
+
+  tags = {
+    Environment = var.environment
+    Project     = "gym-tracker"
+  }
+}
+
+resource "aws_dynamodb_table" "records" {
+  name         = "Records-${var.environment}"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key  = "UserID"
+  range_key = "RecordID"
+
+  attribute {
+    name = "UserID"
+    type = "S"
+  }
+
   attribute {
-    name = "ExerciseType"
+    name = "RecordID"
+    type = "S"
+  }
+
+  # Normalized exercise name, as on the exercises table
+  attribute {
+    name = "NameKey"
+    type = "S"
+  }
+
+  # PersonalRecord.ExerciseID, stored under its JSON name
+  attribute {
+    name = "exerciseId"
     type = "S"
   }
 
   global_secondary_index {
-    name            = "ExerciseTypeIndex"
-    hash_key        = "ExerciseType"
+    name            = "RecordNameIndex"
+    hash_key        = "UserID"
+    range_key       = "NameKey"
     projection_type = "ALL"
   }
 
+  # Deleting an exercise's records only needs their keys
+  global_secondary_index {
+    name            = "RecordExerciseIndex"
+    hash_key        = "UserID"
+    range_key       = "exerciseId"
+    projection_type = "KEYS_ONLY"
+  }
+
+  tags = {
+    Environment = var.environment
+    Project     = "gym-tracker"
+  }
+}
+
+resource "aws_dynamodb_table" "profiles" {
+  name         = "Profiles-${var.environment}"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key = "UserID"
+
+  attribute {
+    name = "UserID"
+    type = "S"
+  }
+
+  tags = {
+    Environment = var.environment
+    Project     = "gym-tracker"
+  }
+}
+
+resource "aws_dynamodb_table" "measurements" {
+  name         = "Measurements-${var.environment}"
+  billing_mode = "PAY_PER_REQUEST"
+
+  hash_key  = "UserID"
+  range_key = "MeasurementID"
+
+  attribute {
+    name = "UserID"
+    type = "S"
+  }
+
+  attribute {
+    name = "MeasurementID"
+    type = "S"
+  }
+
+  # Measurement.Date, stored under its JSON name
+  attribute {
+    name = "date"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "MeasurementDateIndex"
+    hash_key        = "UserID"
+    range_key       = "date"
+    projection_type = "ALL"
+  }
 
   tags = {
     Environment = var.environment
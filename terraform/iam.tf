@@ -35,6 +35,7 @@ resource "aws_iam_role_policy" "lambda_dynamo_policy" {
         Action = [
           "dynamodb:PutItem",
           "dynamodb:GetItem",
+          "dynamodb:BatchGetItem",
           "dynamodb:UpdateItem",
           "dynamodb:DeleteItem",
           "dynamodb:Query",
@@ -43,8 +44,16 @@ resource "aws_iam_role_policy" "lambda_dynamo_policy" {
         Effect = "Allow"
         Resource = [
           aws_dynamodb_table.workouts.arn,
+          "${aws_dynamodb_table.workouts.arn}/index/*",
           aws_dynamodb_table.exercises.arn,
-          "${aws_dynamodb_table.exercises.arn}/index/*"
+          "${aws_dynamodb_table.exercises.arn}/index/*",
+          aws_dynamodb_table.templates.arn,
+          aws_dynamodb_table.programs.arn,
+          aws_dynamodb_table.records.arn,
+          "${aws_dynamodb_table.records.arn}/index/*",
+          aws_dynamodb_table.profiles.arn,
+          aws_dynamodb_table.measurements.arn,
+          "${aws_dynamodb_table.measurements.arn}/index/*"
         ]
       }
     ]
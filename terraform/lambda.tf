@@ -11,9 +11,16 @@ resource "aws_lambda_function" "api_handler" {
       ENVIRONMENT          = var.environment
       DYNAMO_TABLE_WORKOUTS  = aws_dynamodb_table.workouts.name
       DYNAMO_TABLE_EXERCISES = aws_dynamodb_table.exercises.name
+      DYNAMO_TABLE_TEMPLATES = aws_dynamodb_table.templates.name
+      DYNAMO_TABLE_PROGRAMS  = aws_dynamodb_table.programs.name
+      DYNAMO_TABLE_RECORDS   = aws_dynamodb_table.records.name
+      DYNAMO_TABLE_PROFILES  = aws_dynamodb_table.profiles.name
+      DYNAMO_TABLE_MEASUREMENTS = aws_dynamodb_table.measurements.name
       COGNITO_USER_POOL_ID = aws_cognito_user_pool.gym_tracker_pool.id
       COGNITO_CLIENT_ID    = aws_cognito_user_pool_client.gym_tracker_client.id
+      COGNITO_ADMIN_GROUP  = aws_cognito_user_group.admin.name
       CORS_ALLOWED_ORIGINS = var.cors_allowed_origins
+      TRASH_RETENTION_DAYS = var.trash_retention_days
     }
   }
 
@@ -30,3 +30,9 @@ variable "cors_allowed_origins" {
   type        = string
   default     = "http://localhost:5173"
 }
+
+variable "trash_retention_days" {
+  description = "Days deleted workouts and exercises can be restored before they are purged"
+  type        = number
+  default     = 30
+}